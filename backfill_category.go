@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/ai"
+	aiRepository "itmrchow/tw-media-analytics-service/domain/ai/repository"
+	"itmrchow/tw-media-analytics-service/domain/category"
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+	mAi "itmrchow/tw-media-analytics-service/domain/utils/ai"
+	"itmrchow/tw-media-analytics-service/domain/utils/db"
+)
+
+// runBackfillCategory 為加入統一分類前儲存的新聞 (category_key 為空) 補上分類,
+// 與儲存新聞時相同依序使用版面對應, 別名, 關鍵字與 AI 模型 (CATEGORY_LLM_ENABLED 時).
+//
+// Usage:
+//
+//	tw-media-analytics-service backfill-category [-batch 500]
+func runBackfillCategory(ctx context.Context, logger *zerolog.Logger, args []string) error {
+	flagSet := flag.NewFlagSet("backfill-category", flag.ContinueOnError)
+	batch := flagSet.Int("batch", 500, "number of news classified per query")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+	if *batch <= 0 {
+		return errors.New("backfill-category: -batch must be positive")
+	}
+
+	tracer := otel.Tracer("tw-media-analytics-service")
+
+	// db
+	ormDB := db.NewDatabase(ctx, logger, tracer)
+	defer func() {
+		if sqlDB, err := ormDB.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}()
+	newsRepo := repository.NewNewsRepositoryImpl(logger, ormDB)
+
+	// classifier, AI 模型分類與服務共用預算
+	usage := ai.NewUsageService(logger, aiRepository.NewUsageRepositoryImpl(logger, ormDB))
+	classifier := category.NewClassifier(logger, mAi.NewCategoryLLM(ctx, logger, usage))
+
+	// 每篇更新後 category_key 不再為空, 因此每次都從頭取下一批
+	var total int
+	sources := map[category.Source]int{}
+	for {
+		newsList, err := newsRepo.FindUncategorizedNews(ctx, *batch)
+		if err != nil {
+			return err
+		}
+		if len(newsList) == 0 {
+			break
+		}
+
+		for _, news := range newsList {
+			result := classifier.Classify(ctx, news.MediaID, news.Category, news.Title, news.Content)
+			news.CategoryKey = result.Key
+			news.CategorySource = string(result.Source)
+			if err = newsRepo.UpdateCategory(ctx, news); err != nil {
+				return err
+			}
+			sources[result.Source]++
+		}
+
+		total += len(newsList)
+		logger.Info().Ctx(ctx).Int("classified", total).Msg("backfill-category: batch classified")
+	}
+
+	logger.Info().Ctx(ctx).
+		Int("classified", total).
+		Int("mapping", sources[category.SourceMapping]).
+		Int("alias", sources[category.SourceAlias]).
+		Int("keyword", sources[category.SourceKeyword]).
+		Int("llm", sources[category.SourceLLM]).
+		Int("default", sources[category.SourceDefault]).
+		Msg("backfill-category completed")
+
+	return nil
+}
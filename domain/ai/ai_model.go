@@ -1,8 +1,28 @@
 package ai
 
-import "itmrchow/tw-media-analytics-service/domain/ai/dto"
+import (
+	"context"
+	"errors"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+// ErrInvalidResponse 模型回應格式錯誤, 無法解析為分析結果.
+var ErrInvalidResponse = errors.New("invalid response format")
 
 type AiModel interface {
-	AnalyzeNews(title string, content string) (*dto.NewsAnalytics, error)
+	AnalyzeNews(ctx context.Context, title string, content string) (*dto.NewsAnalytics, error)
+	// Ping 以不消耗 token 的請求確認 AI 服務可連線
+	Ping(ctx context.Context) error
 	CloseClient() error
 }
+
+// FramingModel 分析新聞對主要政黨的立場, 情緒語氣與帶有價值判斷的用語, 與品質評分分開.
+type FramingModel interface {
+	AnalyzeFraming(ctx context.Context, title string, content string) (*dto.Analytics, error)
+}
+
+// SummaryModel 產生新聞的中立摘要並擷取可查核的事實陳述.
+type SummaryModel interface {
+	SummarizeNews(ctx context.Context, title string, content string) (*dto.NewsSummary, error)
+}
@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+// ErrBudgetExceeded AI 預算已用完, 暫停分析.
+var ErrBudgetExceeded = errors.New("ai budget exceeded")
+
+var _ AiModel = &BudgetedModel{}
+
+// BudgetedModel 主要模型因預算用完回傳 ErrBudgetExceeded 時, 有設定 fallback 則改用較便宜的模型.
+// 預算檢查由主要模型進行 (見 WithBudget), 主要模型外層的快取命中時不受預算限制.
+type BudgetedModel struct {
+	logger   *zerolog.Logger
+	primary  AiModel
+	fallback AiModel // nil 代表預算用完即暫停
+}
+
+// NewBudgetedModel 建立預算用完時改用 fallback 的模型, fallback 可為 nil.
+func NewBudgetedModel(logger *zerolog.Logger, primary AiModel, fallback AiModel) *BudgetedModel {
+	return &BudgetedModel{
+		logger:   logger,
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+func (m *BudgetedModel) AnalyzeNews(ctx context.Context, title string, content string) (*dto.NewsAnalytics, error) {
+	result, err := m.primary.AnalyzeNews(ctx, title, content)
+	if !errors.Is(err, ErrBudgetExceeded) || m.fallback == nil {
+		return result, err
+	}
+
+	m.logger.Warn().Ctx(ctx).Msg("ai budget exceeded, use fallback model")
+	return m.fallback.AnalyzeNews(ctx, title, content)
+}
+
+func (m *BudgetedModel) Ping(ctx context.Context) error {
+	return m.primary.Ping(ctx)
+}
+
+func (m *BudgetedModel) CloseClient() error {
+	err := m.primary.CloseClient()
+	if m.fallback != nil {
+		err = errors.Join(err, m.fallback.CloseClient())
+	}
+	return err
+}
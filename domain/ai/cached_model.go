@@ -0,0 +1,156 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+	"itmrchow/tw-media-analytics-service/domain/ai/entity"
+	"itmrchow/tw-media-analytics-service/domain/ai/repository"
+)
+
+var _ AiModel = &CachedModel{}
+
+// CachedModel 於 AnalyzeNews 前查詢 DB 快取, 相同內容 (例如多家媒體轉載的通訊社稿) 不重複呼叫 AI.
+// 快取讀寫失敗只記錄 log, 不影響分析.
+type CachedModel struct {
+	logger        *zerolog.Logger
+	repo          repository.AnalysisCacheRepository
+	model         AiModel
+	modelName     string
+	promptVersion string
+	now           func() time.Time
+
+	requests metric.Int64Counter
+}
+
+// NewCachedModel 建立具分析快取的模型, modelName 與 promptVersion 為快取 key 的一部分.
+func NewCachedModel(
+	logger *zerolog.Logger,
+	meter metric.Meter,
+	repo repository.AnalysisCacheRepository,
+	model AiModel,
+	modelName string,
+	promptVersion string,
+) *CachedModel {
+	m := &CachedModel{
+		logger:        logger,
+		repo:          repo,
+		model:         model,
+		modelName:     modelName,
+		promptVersion: promptVersion,
+		now:           time.Now,
+	}
+
+	var err error
+	if m.requests, err = meter.Int64Counter(
+		"ai.cache.requests",
+		metric.WithDescription("Number of analysis cache lookups by result"),
+	); err != nil {
+		logger.Fatal().Err(err).Msg("failed to create ai.cache.requests counter")
+	}
+
+	return m
+}
+
+func (m *CachedModel) AnalyzeNews(ctx context.Context, title string, content string) (*dto.NewsAnalytics, error) {
+	key := AnalysisCacheKey(m.modelName, m.promptVersion, title, content)
+
+	if result, ok := m.get(ctx, key); ok {
+		m.record(ctx, "hit")
+		return result, nil
+	}
+	m.record(ctx, "miss")
+
+	result, err := m.model.AnalyzeNews(ctx, title, content)
+	if err != nil {
+		return nil, err
+	}
+
+	m.save(ctx, key, result)
+
+	return result, nil
+}
+
+func (m *CachedModel) Ping(ctx context.Context) error {
+	return m.model.Ping(ctx)
+}
+
+func (m *CachedModel) CloseClient() error {
+	return m.model.CloseClient()
+}
+
+// get 取得快取結果, 不存在或讀取失敗時 ok 為 false.
+func (m *CachedModel) get(ctx context.Context, key string) (*dto.NewsAnalytics, bool) {
+	cache, err := m.repo.GetAnalysisCache(ctx, key)
+	if err != nil {
+		m.logger.Error().Err(err).Ctx(ctx).Str("key", key).Msg("failed to get analysis cache")
+		return nil, false
+	}
+	if cache == nil {
+		return nil, false
+	}
+
+	var result dto.NewsAnalytics
+	if err := json.Unmarshal([]byte(cache.Result), &result); err != nil {
+		m.logger.Error().Err(err).Ctx(ctx).Str("key", key).Msg("failed to unmarshal analysis cache")
+		return nil, false
+	}
+
+	if err := m.repo.IncrHitCount(ctx, key); err != nil {
+		m.logger.Error().Err(err).Ctx(ctx).Str("key", key).Msg("failed to increase analysis cache hit count")
+	}
+
+	return &result, true
+}
+
+func (m *CachedModel) save(ctx context.Context, key string, result *dto.NewsAnalytics) {
+	resultJson, err := json.Marshal(result)
+	if err != nil {
+		m.logger.Error().Err(err).Ctx(ctx).Str("key", key).Msg("failed to marshal analysis cache")
+		return
+	}
+
+	now := m.now()
+	err = m.repo.SaveAnalysisCache(ctx, &entity.AnalysisCache{
+		Key:           key,
+		Model:         m.modelName,
+		PromptVersion: m.promptVersion,
+		Result:        string(resultJson),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		m.logger.Error().Err(err).Ctx(ctx).Str("key", key).Msg("failed to save analysis cache")
+	}
+}
+
+func (m *CachedModel) record(ctx context.Context, result string) {
+	m.requests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("model", m.modelName),
+		attribute.String("result", result),
+	))
+}
+
+// AnalysisCacheKey 以模型, prompt 版本與正規化後的標題, 內容計算快取 key.
+func AnalysisCacheKey(modelName string, promptVersion string, title string, content string) string {
+	h := sha256.New()
+	for _, s := range []string{modelName, promptVersion, normalizeText(title), normalizeText(content)} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeText 合併空白並去除前後空白, 避免排版差異造成快取失效.
+func normalizeText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
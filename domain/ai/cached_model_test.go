@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/entity"
+)
+
+// fakeCacheRepo 以記憶體儲存分析快取.
+type fakeCacheRepo struct {
+	caches map[string]*entity.AnalysisCache
+	getErr error
+}
+
+func (r *fakeCacheRepo) GetAnalysisCache(_ context.Context, key string) (*entity.AnalysisCache, error) {
+	if r.getErr != nil {
+		return nil, r.getErr
+	}
+	return r.caches[key], nil
+}
+
+func (r *fakeCacheRepo) SaveAnalysisCache(_ context.Context, cache *entity.AnalysisCache) error {
+	r.caches[cache.Key] = cache
+	return nil
+}
+
+func (r *fakeCacheRepo) IncrHitCount(_ context.Context, key string) error {
+	r.caches[key].HitCount++
+	return nil
+}
+
+func TestAnalysisCacheKey(t *testing.T) {
+	key := AnalysisCacheKey("model-a", "v1", "標題", "第一段\n\n第二段")
+
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, AnalysisCacheKey("model-a", "v1", "  標題 ", "第一段 \n 第二段\n"), "空白差異應視為相同內容")
+	assert.NotEqual(t, key, AnalysisCacheKey("model-b", "v1", "標題", "第一段 第二段"))
+	assert.NotEqual(t, key, AnalysisCacheKey("model-a", "v2", "標題", "第一段 第二段"))
+	assert.NotEqual(t, key, AnalysisCacheKey("model-a", "v1", "標題2", "第一段 第二段"))
+}
+
+func TestCachedModel_AnalyzeNews(t *testing.T) {
+	logger := zerolog.Nop()
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	repo := &fakeCacheRepo{caches: map[string]*entity.AnalysisCache{}}
+	model := &fakeModel{}
+	cached := NewCachedModel(&logger, meter, repo, model, "model-a", "v1")
+
+	_, err := cached.AnalyzeNews(context.Background(), "標題", "內容")
+	require.NoError(t, err)
+	_, err = cached.AnalyzeNews(context.Background(), "標題", " 內容 ")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, model.calls, "第二次應命中快取")
+	require.Len(t, repo.caches, 1)
+	for _, cache := range repo.caches {
+		assert.Equal(t, int64(1), cache.HitCount)
+	}
+
+	// 快取讀取失敗仍呼叫模型
+	repo.getErr = errors.New("db down")
+	_, err = cached.AnalyzeNews(context.Background(), "標題", "內容")
+	require.NoError(t, err)
+	assert.Equal(t, 2, model.calls)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "ai.cache.requests" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				result, _ := dp.Attributes.Value("result")
+				counts[result.AsString()] = dp.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"hit": 1, "miss": 2}, counts)
+}
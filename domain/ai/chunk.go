@@ -0,0 +1,147 @@
+package ai
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// sentenceEnds 斷句符號, 斷在符號之後.
+const sentenceEnds = "。！？!?；;\n"
+
+// EstimateTokens 估算 token 數: 中日韓文字每字約 1 token, 其他文字每 4 字元約 1 token.
+func EstimateTokens(s string) int {
+	cjk, other := 0, 0
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+		case isCJK(r):
+			cjk++
+		default:
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// isCJK 中日韓文字與全形標點.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+// SplitContent 依句子將內容切成每段不超過 maxTokens 的區塊, 過長的句子會再依字數切開.
+func SplitContent(content string, maxTokens int) []string {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil
+	}
+	if maxTokens <= 0 || EstimateTokens(content) <= maxTokens {
+		return []string{content}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentTokens := 0
+
+	flush := func() {
+		if chunk := strings.TrimSpace(current.String()); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current.Reset()
+		currentTokens = 0
+	}
+
+	for _, sentence := range splitSentences(content) {
+		for _, piece := range splitByTokens(sentence, maxTokens) {
+			tokens := EstimateTokens(piece)
+			if currentTokens+tokens > maxTokens {
+				flush()
+			}
+			current.WriteString(piece)
+			currentTokens += tokens
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// Abstract 從每個區塊依序取前幾句, 組成不超過約 maxTokens 的摘要, 讓標題分析能看到全文重點.
+func Abstract(chunks []string, maxTokens int) string {
+	if len(chunks) == 0 {
+		return ""
+	}
+
+	budget := maxTokens / len(chunks)
+	if budget <= 0 {
+		budget = 1
+	}
+
+	parts := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		var b strings.Builder
+		tokens := 0
+		for _, sentence := range splitSentences(chunk) {
+			sentenceTokens := EstimateTokens(sentence)
+			if tokens+sentenceTokens > budget {
+				// 每個區塊至少保留開頭
+				if tokens == 0 {
+					b.WriteString(splitByTokens(sentence, budget)[0])
+				}
+				break
+			}
+			b.WriteString(sentence)
+			tokens += sentenceTokens
+		}
+		if part := strings.TrimSpace(b.String()); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// splitSentences 依斷句符號切分, 保留斷句符號.
+func splitSentences(s string) []string {
+	var sentences []string
+	start := 0
+	for i, r := range s {
+		if strings.ContainsRune(sentenceEnds, r) {
+			end := i + utf8.RuneLen(r)
+			sentences = append(sentences, s[start:end])
+			start = end
+		}
+	}
+	if start < len(s) {
+		sentences = append(sentences, s[start:])
+	}
+	return sentences
+}
+
+// splitByTokens 將過長的文字依字數切成每段不超過 maxTokens.
+func splitByTokens(s string, maxTokens int) []string {
+	if EstimateTokens(s) <= maxTokens {
+		return []string{s}
+	}
+
+	var pieces []string
+	start, cjk, other := 0, 0, 0
+	for i, r := range s {
+		dCjk, dOther := 0, 0
+		switch {
+		case unicode.IsSpace(r):
+		case isCJK(r):
+			dCjk = 1
+		default:
+			dOther = 1
+		}
+
+		if i > start && (cjk+dCjk)+(other+dOther+3)/4 > maxTokens {
+			pieces = append(pieces, s[start:i])
+			start, cjk, other = i, 0, 0
+		}
+		cjk, other = cjk+dCjk, other+dOther
+	}
+	return append(pieces, s[start:])
+}
@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{name: "中文", s: "台灣新聞。", want: 5},
+		{name: "英文", s: "abcd efgh", want: 2},
+		{name: "混合", s: "AI 分析", want: 3},
+		{name: "空白", s: " \n\t", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EstimateTokens(tt.s))
+		})
+	}
+}
+
+func TestSplitContent(t *testing.T) {
+	t.Run("短文不切段", func(t *testing.T) {
+		assert.Equal(t, []string{"一句話。"}, SplitContent(" 一句話。\n", 10))
+		assert.Nil(t, SplitContent("  ", 10))
+	})
+
+	t.Run("依句子切段", func(t *testing.T) {
+		content := "第一句話。第二句話。\n第三句話！第四句話？"
+		chunks := SplitContent(content, 10)
+
+		assert.Equal(t, []string{"第一句話。第二句話。", "第三句話！第四句話？"}, chunks)
+	})
+
+	t.Run("過長的句子依字數切開", func(t *testing.T) {
+		content := strings.Repeat("字", 25)
+		chunks := SplitContent(content, 10)
+
+		require.Len(t, chunks, 3)
+		for _, chunk := range chunks {
+			assert.LessOrEqual(t, EstimateTokens(chunk), 10)
+		}
+		assert.Equal(t, content, strings.Join(chunks, ""))
+	})
+}
+
+func TestAbstract(t *testing.T) {
+	chunks := []string{
+		"甲段開頭。甲段第二句。甲段第三句。",
+		"乙段開頭。乙段第二句。",
+	}
+
+	assert.Equal(t, "甲段開頭。\n乙段開頭。", Abstract(chunks, 12))
+	assert.Equal(t, "甲段開頭。甲段第二句。\n乙段開頭。乙段第二句。", Abstract(chunks, 24))
+	// 摘要上限小於第一句時仍保留開頭
+	assert.Equal(t, "甲段\n乙段", Abstract(chunks, 4))
+}
@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+const (
+	defaultChunkMaxTokens    = 4000
+	defaultAbstractMaxTokens = 800
+)
+
+// ChunkConfig 長文切段設定.
+type ChunkConfig struct {
+	MaxTokens         int // 內容超過此 token 數才切段, 亦為每段上限
+	AbstractMaxTokens int // 標題分析使用的摘要上限
+}
+
+// NewChunkConfig 從 viper 讀取長文切段設定.
+func NewChunkConfig() ChunkConfig {
+	cfg := ChunkConfig{
+		MaxTokens:         viper.GetInt("AI_CHUNK_MAX_TOKENS"),
+		AbstractMaxTokens: viper.GetInt("AI_ABSTRACT_MAX_TOKENS"),
+	}
+	if cfg.MaxTokens <= 0 {
+		cfg.MaxTokens = defaultChunkMaxTokens
+	}
+	if cfg.AbstractMaxTokens <= 0 {
+		cfg.AbstractMaxTokens = defaultAbstractMaxTokens
+	}
+	return cfg
+}
+
+var _ AiModel = &ChunkedModel{}
+
+// ChunkedModel 以 map-reduce 分析長文, 避免超出模型 context 或被截斷.
+// map: 每段分別分析內容指標; reduce: 依各段 token 數加權平均合併.
+// 標題分析則使用由各段開頭組成的摘要, 讓標題仍能與全文比對.
+type ChunkedModel struct {
+	logger *zerolog.Logger
+	model  AiModel
+	cfg    ChunkConfig
+}
+
+// NewChunkedModel 建立長文切段分析模型.
+func NewChunkedModel(logger *zerolog.Logger, model AiModel, cfg ChunkConfig) *ChunkedModel {
+	return &ChunkedModel{
+		logger: logger,
+		model:  model,
+		cfg:    cfg,
+	}
+}
+
+func (m *ChunkedModel) AnalyzeNews(ctx context.Context, title string, content string) (*dto.NewsAnalytics, error) {
+	chunks := SplitContent(content, m.cfg.MaxTokens)
+	if len(chunks) <= 1 {
+		return m.model.AnalyzeNews(ctx, title, content)
+	}
+
+	m.logger.Info().Ctx(ctx).
+		Int("chunks", len(chunks)).
+		Int("tokens", EstimateTokens(content)).
+		Msg("ChunkedModel: analyze long content by chunks")
+
+	// title: 以全文摘要分析
+	titleResult, err := m.model.AnalyzeNews(ctx, title, Abstract(chunks, m.cfg.AbstractMaxTokens))
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze abstract: %w", err)
+	}
+
+	// map: 各段內容分析
+	contentList := make([]dto.Analytics, 0, len(chunks))
+	weights := make([]float64, 0, len(chunks))
+	for i, chunk := range chunks {
+		chunkResult, err := m.model.AnalyzeNews(ctx, title, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		contentList = append(contentList, chunkResult.ContentAnalytics)
+		weights = append(weights, float64(EstimateTokens(chunk)))
+	}
+
+	// reduce
+	return &dto.NewsAnalytics{
+		TitleAnalytics:   titleResult.TitleAnalytics,
+		ContentAnalytics: MergeAnalytics(contentList, weights),
+	}, nil
+}
+
+func (m *ChunkedModel) Ping(ctx context.Context) error {
+	return m.model.Ping(ctx)
+}
+
+func (m *ChunkedModel) CloseClient() error {
+	return m.model.CloseClient()
+}
+
+// MergeAnalytics 依權重加權平均各段分數 (四捨五入至小數點下一位) 與變異數, 評語依段落順序合併.
+func MergeAnalytics(list []dto.Analytics, weights []float64) dto.Analytics {
+	if len(list) == 1 {
+		return list[0]
+	}
+
+	type metricSum struct {
+		score    float64
+		variance float64
+		weight   float64
+		reasons  []string
+	}
+
+	var merged dto.Analytics
+	var totalWeight float64
+	var reasons []string
+	var metricKeys []string
+	metrics := map[string]*metricSum{}
+
+	for i, analytics := range list {
+		w := weights[i]
+		totalWeight += w
+		merged.Score += analytics.Score * w
+		reasons = append(reasons, fmt.Sprintf("[%d] %s", i+1, analytics.Reason))
+
+		for _, metric := range analytics.MetricList {
+			sum, ok := metrics[metric.MetricKey]
+			if !ok {
+				sum = &metricSum{}
+				metrics[metric.MetricKey] = sum
+				metricKeys = append(metricKeys, metric.MetricKey)
+			}
+			sum.score += metric.Score * w
+			sum.variance += metric.Variance * w
+			sum.weight += w
+			sum.reasons = append(sum.reasons, fmt.Sprintf("[%d] %s", i+1, metric.Reason))
+		}
+	}
+
+	if totalWeight > 0 {
+		merged.Score = roundScore(merged.Score / totalWeight)
+	}
+	merged.Reason = strings.Join(reasons, " ")
+
+	for _, key := range metricKeys {
+		sum := metrics[key]
+		merged.MetricList = append(merged.MetricList, dto.Metric{
+			MetricKey: key,
+			Score:     roundScore(sum.score / sum.weight),
+			Reason:    strings.Join(sum.reasons, " "),
+			Variance:  math.Round(sum.variance/sum.weight*100) / 100,
+		})
+	}
+
+	return merged
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*10) / 10
+}
@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+// contentScoreModel 依內容回傳指定分數, 並記錄收到的內容.
+type contentScoreModel struct {
+	scores   map[string]float64
+	contents []string
+	err      error
+}
+
+func (m *contentScoreModel) AnalyzeNews(_ context.Context, _ string, content string) (*dto.NewsAnalytics, error) {
+	m.contents = append(m.contents, content)
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	score := m.scores[content]
+	return &dto.NewsAnalytics{
+		TitleAnalytics: dto.Analytics{Score: 4, Reason: "title:" + content},
+		ContentAnalytics: dto.Analytics{
+			Score:  score,
+			Reason: content,
+			MetricList: []dto.Metric{
+				{MetricKey: "accuracy", Score: score, Reason: "ok"},
+			},
+		},
+	}, nil
+}
+
+func (m *contentScoreModel) Ping(context.Context) error { return nil }
+
+func (m *contentScoreModel) CloseClient() error { return nil }
+
+func TestChunkedModel_AnalyzeNews(t *testing.T) {
+	logger := zerolog.Nop()
+	cfg := ChunkConfig{MaxTokens: 10, AbstractMaxTokens: 10}
+
+	t.Run("短文直接分析", func(t *testing.T) {
+		model := &contentScoreModel{scores: map[string]float64{"短文。": 3}}
+
+		result, err := NewChunkedModel(&logger, model, cfg).AnalyzeNews(context.Background(), "標題", "短文。")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"短文。"}, model.contents)
+		assert.Equal(t, 3.0, result.ContentAnalytics.Score)
+	})
+
+	t.Run("長文切段後合併", func(t *testing.T) {
+		chunk1 := "第一段內容很長。" // 8 tokens
+		chunk2 := "第二段。"     // 4 tokens
+		model := &contentScoreModel{scores: map[string]float64{chunk1: 5, chunk2: 2}}
+
+		result, err := NewChunkedModel(&logger, model, cfg).AnalyzeNews(context.Background(), "標題", chunk1+chunk2)
+		require.NoError(t, err)
+
+		// 第一次為摘要, 其後為各段
+		require.Len(t, model.contents, 3)
+		assert.Equal(t, "第一段內容\n第二段。", model.contents[0])
+		assert.Equal(t, []string{chunk1, chunk2}, model.contents[1:])
+
+		assert.Equal(t, "title:"+model.contents[0], result.TitleAnalytics.Reason)
+		// (5*8 + 2*4) / 12 = 4.0
+		assert.Equal(t, 4.0, result.ContentAnalytics.Score)
+		require.Len(t, result.ContentAnalytics.MetricList, 1)
+		assert.Equal(t, 4.0, result.ContentAnalytics.MetricList[0].Score)
+		assert.True(t, strings.HasPrefix(result.ContentAnalytics.Reason, "[1] "+chunk1))
+	})
+
+	t.Run("任一段失敗回傳錯誤", func(t *testing.T) {
+		model := &contentScoreModel{err: ErrBudgetExceeded}
+
+		_, err := NewChunkedModel(&logger, model, cfg).AnalyzeNews(context.Background(), "標題", "第一段內容很長。第二段。")
+		assert.True(t, errors.Is(err, ErrBudgetExceeded))
+	})
+}
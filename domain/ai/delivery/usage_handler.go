@@ -0,0 +1,45 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/ai"
+)
+
+type UsageHandler struct {
+	tracer trace.Tracer
+	logger *zerolog.Logger
+	usage  *ai.UsageService
+}
+
+func NewUsageHandler(logger *zerolog.Logger, tracer trace.Tracer, usage *ai.UsageService) *UsageHandler {
+	return &UsageHandler{
+		tracer: tracer,
+		logger: logger,
+		usage:  usage,
+	}
+}
+
+// GetUsage 取得當日與當月的 AI 使用量與預算.
+// GET /api/ai/usage
+func (h *UsageHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	// Tracer
+	ctx, span := h.tracer.Start(r.Context(), "domain/ai/delivery/usage_handler/GetUsage: Get AI Usage")
+	defer span.End()
+
+	totals, err := h.usage.Totals(ctx)
+	if err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to get ai usage totals")
+		http.Error(w, "failed to get ai usage", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(totals); err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to write ai usage")
+	}
+}
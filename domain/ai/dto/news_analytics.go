@@ -6,6 +6,8 @@ type Metric struct {
 	MetricKey string  `json:"metricKey"`
 	Score     float64 `json:"score"`
 	Reason    string  `json:"reason"`
+	// Variance 多模型評分的母體變異數, 單一模型時為 0
+	Variance float64 `json:"variance,omitempty"`
 }
 
 // Analytics 定義分析結果的基本結構
@@ -19,4 +21,14 @@ type Analytics struct {
 type NewsAnalytics struct {
 	TitleAnalytics   Analytics `json:"titleAnalytics"`
 	ContentAnalytics Analytics `json:"contentAnalytics"`
+	// RunList 多模型評分時各次的原始結果
+	RunList []ModelRun `json:"runList,omitempty"`
+}
+
+// ModelRun 多模型評分中單一模型單次的結果
+type ModelRun struct {
+	Model            string    `json:"model"`
+	Run              int       `json:"run"` // 同一模型第幾次, 從 1 開始
+	TitleAnalytics   Analytics `json:"titleAnalytics"`
+	ContentAnalytics Analytics `json:"contentAnalytics"`
 }
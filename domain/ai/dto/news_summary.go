@@ -0,0 +1,13 @@
+package dto
+
+// NewsSummary 新聞的中立摘要與可查核的事實陳述
+type NewsSummary struct {
+	Summary   string  `json:"summary"`
+	ClaimList []Claim `json:"claimList"`
+}
+
+// Claim 新聞中可查核的事實陳述, Quote 為內容中的原文
+type Claim struct {
+	Claim string `json:"claim"`
+	Quote string `json:"quote"`
+}
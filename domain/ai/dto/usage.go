@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+// Usage 單次 AI 呼叫的 token 使用量.
+type Usage struct {
+	Model            string
+	PromptVersion    string
+	Operation        string
+	PromptTokens     int64
+	CompletionTokens int64
+	TotalTokens      int64
+}
+
+// ModelUsage 單一模型於期間內的使用量.
+type ModelUsage struct {
+	Model            string  `json:"model"`
+	Calls            int64   `json:"calls"`
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	TotalTokens      int64   `json:"totalTokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// UsagePeriod 期間內的使用量與預算.
+type UsagePeriod struct {
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"`
+	Budget   float64      `json:"budget"` // USD, 0 代表不限制
+	Cost     float64      `json:"cost"`   // USD
+	Tokens   int64        `json:"tokens"`
+	Calls    int64        `json:"calls"`
+	Exceeded bool         `json:"exceeded"`
+	Models   []ModelUsage `json:"models"`
+}
+
+// UsageTotals 當日與當月的使用量.
+type UsageTotals struct {
+	Daily   UsagePeriod `json:"daily"`
+	Monthly UsagePeriod `json:"monthly"`
+}
+
+// Exceeded 當日或當月預算是否已用完.
+func (t *UsageTotals) Exceeded() bool {
+	return t.Daily.Exceeded || t.Monthly.Exceeded
+}
@@ -0,0 +1,240 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"unicode"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"google.golang.org/api/option"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+// embeddingContentRunes 向量只取新聞前段, 避免超出 embedding 模型的輸入上限.
+const embeddingContentRunes = 2000
+
+// EmbeddingDocument 待計算向量的新聞.
+type EmbeddingDocument struct {
+	Title   string
+	Content string
+}
+
+// EmbeddingModel 計算新聞與查詢文字的向量, 不同模型的向量不可互相比較.
+type EmbeddingModel interface {
+	// Name 模型名稱, 與向量一起儲存
+	Name() string
+	EmbedDocuments(ctx context.Context, docs []EmbeddingDocument) ([][]float32, error)
+	EmbedQuery(ctx context.Context, text string) ([]float32, error)
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
+
+var _ EmbeddingModel = &GeminiEmbedding{}
+
+// GeminiEmbedding 以 Gemini embedding 模型計算向量.
+// embedding API 不回傳 token 數, 使用量以 EstimateTokens 估算.
+type GeminiEmbedding struct {
+	client    *genai.Client
+	modelName string
+	document  *genai.EmbeddingModel
+	query     *genai.EmbeddingModel
+
+	recorder UsageRecorder // nil 代表不記錄使用量
+	budget   BudgetChecker // nil 代表不檢查預算
+}
+
+// NewGeminiEmbedding 建立 Gemini embedding 模型, 新聞與查詢分別使用 retrieval document / query 任務類型.
+// recorder 與 budget 可為 nil.
+func NewGeminiEmbedding(
+	ctx context.Context,
+	log *zerolog.Logger,
+	modelName string,
+	recorder UsageRecorder,
+	budget BudgetChecker,
+) (*GeminiEmbedding, error) {
+	client, err := genai.NewClient(ctx, option.WithAPIKey(viper.GetString("GEMINI_API_KEY")))
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("failed to create embedding client")
+		return nil, err
+	}
+
+	document := client.EmbeddingModel(modelName)
+	document.TaskType = genai.TaskTypeRetrievalDocument
+	query := client.EmbeddingModel(modelName)
+	query.TaskType = genai.TaskTypeRetrievalQuery
+
+	return &GeminiEmbedding{
+		client:    client,
+		modelName: modelName,
+		document:  document,
+		query:     query,
+		recorder:  recorder,
+		budget:    budget,
+	}, nil
+}
+
+func (g *GeminiEmbedding) Name() string {
+	return g.modelName
+}
+
+func (g *GeminiEmbedding) EmbedDocuments(ctx context.Context, docs []EmbeddingDocument) ([][]float32, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	if err := g.checkBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	batch := g.document.NewBatch()
+	tokens := 0
+	for _, doc := range docs {
+		content := truncateRunes(doc.Content, embeddingContentRunes)
+		batch.AddContentWithTitle(doc.Title, genai.Text(content))
+		tokens += EstimateTokens(doc.Title) + EstimateTokens(content)
+	}
+
+	resp, err := g.document.BatchEmbedContents(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	g.recordUsage(ctx, "embed_documents", tokens)
+	if len(resp.Embeddings) != len(docs) {
+		return nil, fmt.Errorf("%w: got %d embeddings for %d documents", ErrInvalidResponse, len(resp.Embeddings), len(docs))
+	}
+
+	vectors := make([][]float32, 0, len(docs))
+	for _, e := range resp.Embeddings {
+		if e == nil || len(e.Values) == 0 {
+			return nil, fmt.Errorf("%w: empty embedding", ErrInvalidResponse)
+		}
+		vectors = append(vectors, e.Values)
+	}
+	return vectors, nil
+}
+
+func (g *GeminiEmbedding) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	if err := g.checkBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := g.query.EmbedContent(ctx, genai.Text(text))
+	if err != nil {
+		return nil, err
+	}
+	g.recordUsage(ctx, "embed_query", EstimateTokens(text))
+	if resp.Embedding == nil || len(resp.Embedding.Values) == 0 {
+		return nil, fmt.Errorf("%w: empty embedding", ErrInvalidResponse)
+	}
+	return resp.Embedding.Values, nil
+}
+
+// CloseClient 關閉 Gemini client.
+func (g *GeminiEmbedding) CloseClient() error {
+	return g.client.Close()
+}
+
+func (g *GeminiEmbedding) checkBudget(ctx context.Context) error {
+	if g.budget == nil {
+		return nil
+	}
+	return g.budget.CheckBudget(ctx)
+}
+
+func (g *GeminiEmbedding) recordUsage(ctx context.Context, operation string, tokens int) {
+	if g.recorder == nil {
+		return
+	}
+	g.recorder.RecordUsage(ctx, dto.Usage{
+		Model:        g.modelName,
+		Operation:    operation,
+		PromptTokens: int64(tokens),
+		TotalTokens:  int64(tokens),
+	})
+}
+
+var _ EmbeddingModel = &LocalEmbedding{}
+
+// LocalEmbedding 不需呼叫 AI 的本機向量: 將相鄰兩字 (bigram) 雜湊至固定維度後正規化,
+// 只反映用字重疊, 無法辨識同義詞, 適合離線環境或作為基準.
+type LocalEmbedding struct {
+	dimensions int
+}
+
+// NewLocalEmbedding 建立本機向量模型, dimensions 需大於 0.
+func NewLocalEmbedding(dimensions int) *LocalEmbedding {
+	return &LocalEmbedding{dimensions: dimensions}
+}
+
+func (l *LocalEmbedding) Name() string {
+	return fmt.Sprintf("local-bigram-%d", l.dimensions)
+}
+
+func (l *LocalEmbedding) EmbedDocuments(_ context.Context, docs []EmbeddingDocument) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(docs))
+	for _, doc := range docs {
+		vector := make([]float64, l.dimensions)
+		// 標題加權, 與 Gemini 以標題輔助向量相近
+		l.add(vector, doc.Title, 2)
+		l.add(vector, truncateRunes(doc.Content, embeddingContentRunes), 1)
+		vectors = append(vectors, normalize32(vector))
+	}
+	return vectors, nil
+}
+
+func (l *LocalEmbedding) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	vector := make([]float64, l.dimensions)
+	l.add(vector, text, 1)
+	return normalize32(vector), nil
+}
+
+// add 將 text 的 bigram 以 FNV 雜湊累加至 vector, 以雜湊的最高位決定正負減少碰撞偏差.
+func (l *LocalEmbedding) add(vector []float64, text string, weight float64) {
+	var prev rune
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			prev = 0
+			continue
+		}
+		r = unicode.ToLower(r)
+		if prev != 0 {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(string([]rune{prev, r})))
+			sum := h.Sum32()
+			sign := 1.0
+			if sum&(1<<31) != 0 {
+				sign = -1
+			}
+			vector[int(sum%uint32(l.dimensions))] += sign * weight
+		}
+		prev = r
+	}
+}
+
+func normalize32(vector []float64) []float32 {
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+
+	result := make([]float32, len(vector))
+	if norm == 0 {
+		return result
+	}
+	for i, v := range vector {
+		result[i] = float32(v / norm)
+	}
+	return result
+}
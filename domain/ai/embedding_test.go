@@ -0,0 +1,49 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"itmrchow/tw-media-analytics-service/domain/embedding"
+)
+
+func TestLocalEmbedding(t *testing.T) {
+	model := NewLocalEmbedding(256)
+	assert.Equal(t, "local-bigram-256", model.Name())
+
+	vectors, err := model.EmbedDocuments(context.Background(), []EmbeddingDocument{
+		{Title: "颱風山陀兒逼近 南部縣市宣布停班停課", Content: "中央氣象署發布陸上颱風警報, 高雄, 屏東宣布明天停班停課."},
+		{Title: "山陀兒颱風來襲 高雄屏東明停班停課", Content: "颱風山陀兒持續接近, 高雄市與屏東縣宣布停班停課."},
+		{Title: "台積電法說會 上修全年營收展望", Content: "台積電今天舉行法說會, 受惠 AI 需求上修全年營收成長."},
+	})
+	require.NoError(t, err)
+	require.Len(t, vectors, 3)
+
+	for _, vector := range vectors {
+		assert.Len(t, vector, 256)
+
+		// 已正規化為單位向量
+		var norm float64
+		for _, v := range vector {
+			norm += float64(v) * float64(v)
+		}
+		assert.InDelta(t, 1, math.Sqrt(norm), 1e-6)
+	}
+
+	query, err := model.EmbedQuery(context.Background(), "高雄 停班停課")
+	require.NoError(t, err)
+
+	// 同一事件的報導比不同主題的報導相似
+	assert.Greater(t, embedding.Cosine(vectors[0], vectors[1]), embedding.Cosine(vectors[0], vectors[2]))
+	assert.Greater(t, embedding.Cosine(query, vectors[1]), embedding.Cosine(query, vectors[2]))
+}
+
+func TestLocalEmbeddingEmptyText(t *testing.T) {
+	vector, err := NewLocalEmbedding(8).EmbedQuery(context.Background(), "!?")
+	require.NoError(t, err)
+	assert.Equal(t, make([]float32, 8), vector)
+}
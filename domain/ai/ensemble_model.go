@@ -0,0 +1,273 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+// Aggregate 多模型評分的合併方式.
+type Aggregate string
+
+const (
+	AggregateMean   Aggregate = "mean"
+	AggregateMedian Aggregate = "median"
+)
+
+// EnsembleConfig 多模型評分設定.
+type EnsembleConfig struct {
+	Models    []string  // 參與評分的模型, 空白代表只使用 AI_MODEL
+	Runs      int       // 每個模型評分次數
+	Aggregate Aggregate // 合併方式
+}
+
+// NewEnsembleConfig 從 viper 讀取多模型評分設定.
+// 模型名稱作為 analysis_runs 的唯一鍵之一, 因此不可重複; 同一模型多次評分請設定 AI_ENSEMBLE_RUNS.
+func NewEnsembleConfig() (EnsembleConfig, error) {
+	cfg := EnsembleConfig{
+		Models:    viper.GetStringSlice("AI_ENSEMBLE_MODELS"),
+		Runs:      viper.GetInt("AI_ENSEMBLE_RUNS"),
+		Aggregate: Aggregate(viper.GetString("AI_ENSEMBLE_AGGREGATE")),
+	}
+	if cfg.Runs <= 0 {
+		cfg.Runs = 1
+	}
+	seen := make(map[string]struct{}, len(cfg.Models))
+	for _, name := range cfg.Models {
+		if _, ok := seen[name]; ok {
+			return cfg, fmt.Errorf("duplicate model in AI_ENSEMBLE_MODELS: %s", name)
+		}
+		seen[name] = struct{}{}
+	}
+	switch cfg.Aggregate {
+	case "":
+		cfg.Aggregate = AggregateMedian
+	case AggregateMean, AggregateMedian:
+	default:
+		return cfg, fmt.Errorf("unknown AI_ENSEMBLE_AGGREGATE: %s", cfg.Aggregate)
+	}
+	return cfg, nil
+}
+
+// Enabled 是否超過一次評分.
+func (c EnsembleConfig) Enabled() bool {
+	return len(c.Models) > 1 || c.Runs > 1
+}
+
+// Name 多模型評分的名稱, 作為快取 key 的模型名稱.
+func (c EnsembleConfig) Name() string {
+	return fmt.Sprintf("ensemble(%s;runs=%d;%s)", strings.Join(c.Models, ","), c.Runs, c.Aggregate)
+}
+
+// EnsembleMember 參與評分的模型.
+type EnsembleMember struct {
+	Name  string
+	Model AiModel
+}
+
+var _ AiModel = &EnsembleModel{}
+
+// EnsembleModel 以多個模型 (或同一模型多次) 評分後合併, 降低單次評分的雜訊.
+// 各次結果保留於 RunList, 各指標記錄評分的變異數, 部分評分失敗時以成功的結果合併.
+type EnsembleModel struct {
+	logger    *zerolog.Logger
+	members   []EnsembleMember
+	runs      int
+	aggregate Aggregate
+}
+
+// NewEnsembleModel 建立多模型評分模型.
+func NewEnsembleModel(logger *zerolog.Logger, members []EnsembleMember, runs int, aggregate Aggregate) *EnsembleModel {
+	return &EnsembleModel{
+		logger:    logger,
+		members:   members,
+		runs:      max(runs, 1),
+		aggregate: aggregate,
+	}
+}
+
+func (m *EnsembleModel) AnalyzeNews(ctx context.Context, title string, content string) (*dto.NewsAnalytics, error) {
+	// 不同模型並行, 同一模型依序執行; 每次評分都是獨立請求, 不共用對話歷史
+	results := make([][]*dto.NewsAnalytics, len(m.members))
+	errs := make([][]error, len(m.members))
+	var wg sync.WaitGroup
+	for i, member := range m.members {
+		results[i] = make([]*dto.NewsAnalytics, m.runs)
+		errs[i] = make([]error, m.runs)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for run := range m.runs {
+				results[i][run], errs[i][run] = member.Model.AnalyzeNews(ctx, title, content)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var runList []dto.ModelRun
+	var failures []error
+	for i, member := range m.members {
+		for run := range m.runs {
+			if err := errs[i][run]; err != nil {
+				failures = append(failures, fmt.Errorf("%s run %d: %w", member.Name, run+1, err))
+				continue
+			}
+			runList = append(runList, dto.ModelRun{
+				Model:            member.Name,
+				Run:              run + 1,
+				TitleAnalytics:   results[i][run].TitleAnalytics,
+				ContentAnalytics: results[i][run].ContentAnalytics,
+			})
+		}
+	}
+
+	if len(runList) == 0 {
+		return nil, fmt.Errorf("all ensemble runs failed: %w", errors.Join(failures...))
+	}
+	if len(failures) > 0 {
+		m.logger.Warn().Ctx(ctx).
+			Err(errors.Join(failures...)).
+			Int("succeeded", len(runList)).
+			Int("failed", len(failures)).
+			Msg("EnsembleModel: some runs failed, combine the rest")
+	}
+
+	titleList := make([]dto.Analytics, 0, len(runList))
+	contentList := make([]dto.Analytics, 0, len(runList))
+	for _, run := range runList {
+		titleList = append(titleList, run.TitleAnalytics)
+		contentList = append(contentList, run.ContentAnalytics)
+	}
+
+	return &dto.NewsAnalytics{
+		TitleAnalytics:   CombineAnalytics(titleList, m.aggregate),
+		ContentAnalytics: CombineAnalytics(contentList, m.aggregate),
+		RunList:          runList,
+	}, nil
+}
+
+func (m *EnsembleModel) Ping(ctx context.Context) error {
+	var err error
+	for _, member := range m.members {
+		err = errors.Join(err, member.Model.Ping(ctx))
+	}
+	return err
+}
+
+func (m *EnsembleModel) CloseClient() error {
+	var err error
+	for _, member := range m.members {
+		err = errors.Join(err, member.Model.CloseClient())
+	}
+	return err
+}
+
+// CombineAnalytics 以平均數或中位數合併多次評分 (四捨五入至小數點下一位), 並計算各指標的變異數.
+// 評語採用分數最接近合併結果的那次評分.
+func CombineAnalytics(list []dto.Analytics, aggregate Aggregate) dto.Analytics {
+	if len(list) == 1 {
+		return list[0]
+	}
+
+	type metricRuns struct {
+		scores  []float64
+		reasons []string
+	}
+
+	var metricKeys []string
+	metrics := map[string]*metricRuns{}
+	scores := make([]float64, 0, len(list))
+	reasons := make([]string, 0, len(list))
+
+	for _, analytics := range list {
+		scores = append(scores, analytics.Score)
+		reasons = append(reasons, analytics.Reason)
+
+		for _, metric := range analytics.MetricList {
+			runs, ok := metrics[metric.MetricKey]
+			if !ok {
+				runs = &metricRuns{}
+				metrics[metric.MetricKey] = runs
+				metricKeys = append(metricKeys, metric.MetricKey)
+			}
+			runs.scores = append(runs.scores, metric.Score)
+			runs.reasons = append(runs.reasons, metric.Reason)
+		}
+	}
+
+	score := aggregateScores(scores, aggregate)
+	combined := dto.Analytics{
+		Score:  roundScore(score),
+		Reason: reasons[nearest(scores, score)],
+	}
+
+	for _, key := range metricKeys {
+		runs := metrics[key]
+		score := aggregateScores(runs.scores, aggregate)
+		combined.MetricList = append(combined.MetricList, dto.Metric{
+			MetricKey: key,
+			Score:     roundScore(score),
+			Reason:    runs.reasons[nearest(runs.scores, score)],
+			Variance:  math.Round(variance(runs.scores)*100) / 100,
+		})
+	}
+
+	return combined
+}
+
+func aggregateScores(scores []float64, aggregate Aggregate) float64 {
+	if aggregate == AggregateMedian {
+		return median(scores)
+	}
+	return mean(scores)
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// variance 母體變異數.
+func variance(values []float64) float64 {
+	avg := mean(values)
+	var sum float64
+	for _, v := range values {
+		sum += (v - avg) * (v - avg)
+	}
+	return sum / float64(len(values))
+}
+
+// nearest 回傳最接近 target 的索引, 相同時取較前者.
+func nearest(values []float64, target float64) int {
+	best := 0
+	for i, v := range values {
+		if math.Abs(v-target) < math.Abs(values[best]-target) {
+			best = i
+		}
+	}
+	return best
+}
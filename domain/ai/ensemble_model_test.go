@@ -0,0 +1,174 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+// sequenceModel 依呼叫順序回傳指定分數, 分數小於 0 時回傳錯誤.
+type sequenceModel struct {
+	scores []float64
+	calls  int
+}
+
+func (m *sequenceModel) AnalyzeNews(context.Context, string, string) (*dto.NewsAnalytics, error) {
+	score := m.scores[m.calls%len(m.scores)]
+	m.calls++
+	if score < 0 {
+		return nil, errors.New("model error")
+	}
+
+	analytics := dto.Analytics{
+		Score:  score,
+		Reason: fmt.Sprintf("reason %.1f", score),
+		MetricList: []dto.Metric{
+			{MetricKey: "accuracy", Score: score, Reason: fmt.Sprintf("accuracy %.1f", score)},
+		},
+	}
+	return &dto.NewsAnalytics{TitleAnalytics: analytics, ContentAnalytics: analytics}, nil
+}
+
+func (m *sequenceModel) Ping(context.Context) error { return nil }
+
+func (m *sequenceModel) CloseClient() error { return nil }
+
+func TestCombineAnalytics(t *testing.T) {
+	list := []dto.Analytics{
+		{Score: 1, Reason: "a", MetricList: []dto.Metric{{MetricKey: "accuracy", Score: 1, Reason: "a1"}}},
+		{Score: 4, Reason: "b", MetricList: []dto.Metric{{MetricKey: "accuracy", Score: 4, Reason: "b1"}}},
+		{Score: 5, Reason: "c", MetricList: []dto.Metric{
+			{MetricKey: "accuracy", Score: 5, Reason: "c1"},
+			{MetricKey: "clarity", Score: 3, Reason: "c2"},
+		}},
+	}
+
+	t.Run("中位數", func(t *testing.T) {
+		got := CombineAnalytics(list, AggregateMedian)
+		assert.Equal(t, 4.0, got.Score)
+		assert.Equal(t, "b", got.Reason)
+		assert.Equal(t, []dto.Metric{
+			// 變異數 ((1-10/3)² + (4-10/3)² + (5-10/3)²) / 3 = 26/9
+			{MetricKey: "accuracy", Score: 4, Reason: "b1", Variance: 2.89},
+			// 只有一次評分
+			{MetricKey: "clarity", Score: 3, Reason: "c2", Variance: 0},
+		}, got.MetricList)
+	})
+
+	t.Run("平均數", func(t *testing.T) {
+		got := CombineAnalytics(list, AggregateMean)
+		assert.Equal(t, 3.3, got.Score)
+		assert.Equal(t, "b", got.Reason)
+		assert.Equal(t, 3.3, got.MetricList[0].Score)
+	})
+
+	t.Run("偶數筆中位數", func(t *testing.T) {
+		got := CombineAnalytics(list[:2], AggregateMedian)
+		assert.Equal(t, 2.5, got.Score)
+		assert.Equal(t, "a", got.Reason)
+		assert.Equal(t, 2.25, got.MetricList[0].Variance)
+	})
+
+	t.Run("單次評分不變", func(t *testing.T) {
+		assert.Equal(t, list[0], CombineAnalytics(list[:1], AggregateMedian))
+	})
+}
+
+func TestEnsembleModel_AnalyzeNews(t *testing.T) {
+	logger := zerolog.Nop()
+
+	t.Run("多模型多次評分", func(t *testing.T) {
+		flash := &sequenceModel{scores: []float64{4, 5}}
+		pro := &sequenceModel{scores: []float64{2, 3}}
+		model := NewEnsembleModel(&logger, []EnsembleMember{
+			{Name: "flash", Model: flash},
+			{Name: "pro", Model: pro},
+		}, 2, AggregateMean)
+
+		result, err := model.AnalyzeNews(context.Background(), "標題", "內容")
+		require.NoError(t, err)
+		assert.Equal(t, 2, flash.calls)
+		assert.Equal(t, 2, pro.calls)
+
+		require.Len(t, result.RunList, 4)
+		assert.Equal(t, "flash", result.RunList[0].Model)
+		assert.Equal(t, 1, result.RunList[0].Run)
+		assert.Equal(t, "pro", result.RunList[3].Model)
+		assert.Equal(t, 2, result.RunList[3].Run)
+		assert.Equal(t, 3.0, result.RunList[3].ContentAnalytics.Score)
+
+		assert.Equal(t, 3.5, result.ContentAnalytics.Score)
+		assert.Equal(t, 3.5, result.TitleAnalytics.Score)
+		assert.Equal(t, 1.25, result.ContentAnalytics.MetricList[0].Variance)
+	})
+
+	t.Run("部分失敗以成功的結果合併", func(t *testing.T) {
+		model := NewEnsembleModel(&logger, []EnsembleMember{
+			{Name: "ok", Model: &sequenceModel{scores: []float64{4}}},
+			{Name: "broken", Model: &sequenceModel{scores: []float64{-1}}},
+		}, 1, AggregateMedian)
+
+		result, err := model.AnalyzeNews(context.Background(), "標題", "內容")
+		require.NoError(t, err)
+		require.Len(t, result.RunList, 1)
+		assert.Equal(t, 4.0, result.ContentAnalytics.Score)
+	})
+
+	t.Run("全部失敗", func(t *testing.T) {
+		model := NewEnsembleModel(&logger, []EnsembleMember{
+			{Name: "broken", Model: &sequenceModel{scores: []float64{-1}}},
+		}, 3, AggregateMedian)
+
+		_, err := model.AnalyzeNews(context.Background(), "標題", "內容")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "broken run 3")
+	})
+}
+
+func TestEnsembleModel_RunsDoNotShareHistory(t *testing.T) {
+	server := newFakeGeminiServer(t)
+	logger := zerolog.Nop()
+	model := NewEnsembleModel(&logger, []EnsembleMember{
+		{Name: "test-model", Model: newTestGemini(t, server)},
+	}, 3, AggregateMedian)
+
+	result, err := model.AnalyzeNews(context.Background(), "標題", "內容")
+	require.NoError(t, err)
+	require.Len(t, result.RunList, 3)
+
+	// 每次評分只送出 prompt 與新聞, 不包含前幾次的問答
+	requests := server.Requests()
+	require.Len(t, requests, 3)
+	for _, req := range requests {
+		require.Len(t, req.Contents, 1)
+		assert.Equal(t, "user", req.Contents[0].Role)
+		assert.Len(t, req.Contents[0].Parts, 2)
+	}
+}
+
+func TestNewEnsembleConfig(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("AI_ENSEMBLE_MODELS", nil)
+		viper.Set("AI_ENSEMBLE_RUNS", nil)
+	})
+
+	viper.Set("AI_ENSEMBLE_MODELS", []string{"flash", "pro"})
+	viper.Set("AI_ENSEMBLE_RUNS", 2)
+	cfg, err := NewEnsembleConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"flash", "pro"}, cfg.Models)
+	assert.Equal(t, AggregateMedian, cfg.Aggregate)
+
+	// 重複的模型名稱會在 analysis_runs 的唯一鍵衝突
+	viper.Set("AI_ENSEMBLE_MODELS", []string{"flash", "flash"})
+	_, err = NewEnsembleConfig()
+	require.ErrorContains(t, err, "duplicate model in AI_ENSEMBLE_MODELS: flash")
+}
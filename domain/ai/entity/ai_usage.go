@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AiUsage 單次 AI 呼叫的 token 使用量與預估費用.
+type AiUsage struct {
+	ID               uint            `json:"id" gorm:"primaryKey"`
+	Model            string          `json:"model" gorm:"type:varchar(255);not null;index"`
+	PromptVersion    string          `json:"prompt_version" gorm:"type:varchar(64);not null"`
+	Operation        string          `json:"operation" gorm:"type:varchar(64);not null"`
+	PromptTokens     int64           `json:"prompt_tokens" gorm:"not null"`
+	CompletionTokens int64           `json:"completion_tokens" gorm:"not null"`
+	TotalTokens      int64           `json:"total_tokens" gorm:"not null"`
+	Cost             decimal.Decimal `json:"cost" gorm:"type:decimal(12,6);not null"` // USD
+	CreatedAt        time.Time       `json:"created_at" gorm:"not null;index"`
+}
+
+// AiUsageSummary 依模型彙總的使用量.
+type AiUsageSummary struct {
+	Model            string
+	Calls            int64
+	PromptTokens     int64
+	CompletionTokens int64
+	TotalTokens      int64
+	Cost             decimal.Decimal
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// AnalysisCache AI 分析結果快取, 以正規化後的標題, 內容, prompt 版本與模型的 hash 為 key.
+type AnalysisCache struct {
+	Key           string    `json:"key" gorm:"type:char(64);primaryKey"`
+	Model         string    `json:"model" gorm:"type:varchar(255);not null"`
+	PromptVersion string    `json:"prompt_version" gorm:"type:varchar(64);not null"`
+	Result        string    `json:"result" gorm:"type:text;not null"` // dto.NewsAnalytics JSON
+	HitCount      int64     `json:"hit_count" gorm:"not null;default:0"`
+	CreatedAt     time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"not null"`
+}
@@ -0,0 +1,181 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// WriteReport 將報告寫成 JSON 檔.
+func WriteReport(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return nil
+}
+
+// ReadReport 讀取 WriteReport 產生的報告.
+func ReadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// Comparison 兩個版本的評估比較.
+type Comparison struct {
+	Base      *Report
+	Candidate *Report
+	Metrics   []MetricComparison
+}
+
+// MetricComparison 單一指標的比較, MAE 越低越好, Spearman 越高越好.
+type MetricComparison struct {
+	Key       string
+	Base      *MetricReport // 該版本沒有此指標時為 nil
+	Candidate *MetricReport
+}
+
+// Compare 比較 base 與 candidate 兩個報告.
+func Compare(base *Report, candidate *Report) *Comparison {
+	c := &Comparison{Base: base, Candidate: candidate}
+
+	metrics := map[string]*MetricComparison{}
+	for i := range base.Metrics {
+		m := &base.Metrics[i]
+		metrics[m.Key] = &MetricComparison{Key: m.Key, Base: m}
+	}
+	for i := range candidate.Metrics {
+		m := &candidate.Metrics[i]
+		if metrics[m.Key] == nil {
+			metrics[m.Key] = &MetricComparison{Key: m.Key}
+		}
+		metrics[m.Key].Candidate = m
+	}
+
+	for _, m := range metrics {
+		c.Metrics = append(c.Metrics, *m)
+	}
+	sort.Slice(c.Metrics, func(i, j int) bool { return c.Metrics[i].Key < c.Metrics[j].Key })
+
+	return c
+}
+
+// WriteMarkdown 輸出 markdown 比較表.
+func (c *Comparison) WriteMarkdown(w io.Writer) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	printf("# Evaluation comparison\n\n")
+	printf("- base: %s (model %s, prompt %s)\n", c.Base.Name, c.Base.Model, c.Base.PromptVersion)
+	printf("- candidate: %s (model %s, prompt %s)\n\n", c.Candidate.Name, c.Candidate.Model, c.Candidate.PromptVersion)
+
+	printf("## Summary\n\n")
+	printf("| | base | candidate | Δ |\n| --- | --- | --- | --- |\n")
+	printf("| samples | %d | %d | %+d |\n", c.Base.Samples, c.Candidate.Samples, c.Candidate.Samples-c.Base.Samples)
+	printf("| failure rate | %.1f%% | %.1f%% | %+.1f%% |\n",
+		c.Base.FailureRate*100, c.Candidate.FailureRate*100, (c.Candidate.FailureRate-c.Base.FailureRate)*100)
+	printf("| format failure rate | %.1f%% | %.1f%% | %+.1f%% |\n",
+		c.Base.FormatFailureRate*100, c.Candidate.FormatFailureRate*100,
+		(c.Candidate.FormatFailureRate-c.Base.FormatFailureRate)*100)
+	printf("| tokens | %d | %d | %+d |\n",
+		c.Base.Usage.TotalTokens, c.Candidate.Usage.TotalTokens, c.Candidate.Usage.TotalTokens-c.Base.Usage.TotalTokens)
+	printf("| cost (USD) | %.6f | %.6f | %+.6f |\n\n",
+		c.Base.Usage.Cost, c.Candidate.Usage.Cost, c.Candidate.Usage.Cost-c.Base.Usage.Cost)
+
+	printf("## Metrics\n\n")
+	printf("MAE 越低越好, Spearman 越高越好.\n\n")
+	printf("| metric | n | base MAE | candidate MAE | Δ MAE | base ρ | candidate ρ | Δ ρ |\n")
+	printf("| --- | --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, m := range c.Metrics {
+		n := 0
+		if m.Candidate != nil {
+			n = m.Candidate.N
+		} else if m.Base != nil {
+			n = m.Base.N
+		}
+		printf("| %s | %d | %s | %s | %s | %s | %s | %s |\n", m.Key, n,
+			formatMAE(m.Base), formatMAE(m.Candidate), formatDelta(maePtr(m.Base), maePtr(m.Candidate)),
+			formatRho(m.Base), formatRho(m.Candidate), formatDelta(rhoPtr(m.Base), rhoPtr(m.Candidate)))
+	}
+
+	printf("\n## Score distribution\n\n")
+	printf("| | 0-1 | 1-2 | 2-3 | 3-4 | 4-5 | mean |\n| --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, kind := range []string{"title", "content"} {
+		if d, ok := c.Base.Distribution[kind]; ok {
+			printf("| %s labelled | %s | %.2f |\n", kind, formatBuckets(d.Labelled), d.LabelledMean)
+			printf("| %s base | %s | %.2f |\n", kind, formatBuckets(d.Predicted), d.PredictedMean)
+		}
+		if d, ok := c.Candidate.Distribution[kind]; ok {
+			printf("| %s candidate | %s | %.2f |\n", kind, formatBuckets(d.Predicted), d.PredictedMean)
+		}
+	}
+
+	return err
+}
+
+func maePtr(m *MetricReport) *float64 {
+	if m == nil {
+		return nil
+	}
+	return &m.MAE
+}
+
+func rhoPtr(m *MetricReport) *float64 {
+	if m == nil {
+		return nil
+	}
+	return m.Spearman
+}
+
+func formatMAE(m *MetricReport) string {
+	return formatFloat(maePtr(m))
+}
+
+func formatRho(m *MetricReport) string {
+	return formatFloat(rhoPtr(m))
+}
+
+func formatFloat(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", *v)
+}
+
+func formatDelta(base *float64, candidate *float64) string {
+	if base == nil || candidate == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.3f", *candidate-*base)
+}
+
+func formatBuckets(buckets [bucketCount]int) string {
+	s := ""
+	for i, n := range buckets {
+		if i > 0 {
+			s += " | "
+		}
+		s += fmt.Sprint(n)
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ScoreKey 指標分數的 key, 總分使用 "score".
+const ScoreKey = "score"
+
+// Sample 人工標註的評估資料, 每行一筆 JSON.
+//
+//	{"id":"1","title":"...","content":"...","title_scores":{"score":3.5,"accuracy":4},"content_scores":{"score":4}}
+type Sample struct {
+	ID            string             `json:"id"`
+	Title         string             `json:"title"`
+	Content       string             `json:"content"`
+	TitleScores   map[string]float64 `json:"title_scores"`
+	ContentScores map[string]float64 `json:"content_scores"`
+}
+
+// LoadDataset 讀取 JSONL 評估資料, 空行與 # 開頭的行會略過.
+func LoadDataset(path string) ([]Sample, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer f.Close()
+
+	var samples []Sample
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		var sample Sample
+		if err := json.Unmarshal([]byte(text), &sample); err != nil {
+			return nil, fmt.Errorf("dataset line %d: %w", line, err)
+		}
+		if sample.ID == "" {
+			sample.ID = fmt.Sprintf("line-%d", line)
+		}
+		samples = append(samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("dataset %s is empty", path)
+	}
+
+	return samples, nil
+}
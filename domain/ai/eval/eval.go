@@ -0,0 +1,196 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"itmrchow/tw-media-analytics-service/domain/ai"
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+// 分數分布的區間數, 0-1, 1-2, 2-3, 3-4, 4-5.
+const bucketCount = 5
+
+// Report 單一 prompt / 模型版本的評估結果.
+type Report struct {
+	Name          string    `json:"name"`
+	Model         string    `json:"model"`
+	PromptVersion string    `json:"promptVersion"`
+	Dataset       string    `json:"dataset"`
+	CreatedAt     time.Time `json:"createdAt"`
+
+	Samples           int     `json:"samples"`
+	Succeeded         int     `json:"succeeded"`
+	Failures          int     `json:"failures"`
+	FormatFailures    int     `json:"formatFailures"`
+	FailureRate       float64 `json:"failureRate"`
+	FormatFailureRate float64 `json:"formatFailureRate"`
+
+	Metrics      []MetricReport          `json:"metrics"`
+	Distribution map[string]Distribution `json:"distribution"` // title, content
+	Usage        UsageReport             `json:"usage"`
+	Errors       []SampleError           `json:"errors,omitempty"`
+}
+
+// MetricReport 單一指標與人工標註的比較, key 為 title.accuracy, content.score 等.
+type MetricReport struct {
+	Key      string   `json:"key"`
+	N        int      `json:"n"`
+	MAE      float64  `json:"mae"`
+	Spearman *float64 `json:"spearman"` // 樣本不足或無變異時為 null
+}
+
+// Distribution 總分分布.
+type Distribution struct {
+	Predicted     [bucketCount]int `json:"predicted"`
+	Labelled      [bucketCount]int `json:"labelled"`
+	PredictedMean float64          `json:"predictedMean"`
+	LabelledMean  float64          `json:"labelledMean"`
+}
+
+// UsageReport 評估期間的使用量與費用.
+type UsageReport struct {
+	Calls            int64   `json:"calls"`
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	TotalTokens      int64   `json:"totalTokens"`
+	Cost             float64 `json:"cost"` // USD
+}
+
+// SampleError 分析失敗的樣本.
+type SampleError struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// Evaluator 以任一 AiModel 分析評估資料並與人工標註比較.
+type Evaluator struct {
+	logger *zerolog.Logger
+	model  ai.AiModel
+	usage  *UsageCollector // nil 代表不統計費用
+}
+
+// NewEvaluator 建立評估器, usage 需與模型使用同一個 UsageCollector 才能統計費用.
+func NewEvaluator(logger *zerolog.Logger, model ai.AiModel, usage *UsageCollector) *Evaluator {
+	return &Evaluator{
+		logger: logger,
+		model:  model,
+		usage:  usage,
+	}
+}
+
+// Run 依序分析所有樣本並產生報告, ctx 取消時停止並回傳錯誤.
+func (e *Evaluator) Run(ctx context.Context, samples []Sample) (*Report, error) {
+	report := &Report{
+		CreatedAt:    time.Now(),
+		Samples:      len(samples),
+		Distribution: map[string]Distribution{},
+	}
+
+	pairs := map[string]*scorePairs{}
+	for i, sample := range samples {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := e.model.AnalyzeNews(ctx, sample.Title, sample.Content)
+		if err != nil {
+			e.logger.Warn().Err(err).Str("id", sample.ID).Msg("eval: failed to analyze sample")
+			report.Failures++
+			if errors.Is(err, ai.ErrInvalidResponse) {
+				report.FormatFailures++
+			}
+			report.Errors = append(report.Errors, SampleError{ID: sample.ID, Error: err.Error()})
+			continue
+		}
+		report.Succeeded++
+
+		collect(pairs, "title", result.TitleAnalytics, sample.TitleScores)
+		collect(pairs, "content", result.ContentAnalytics, sample.ContentScores)
+
+		e.logger.Debug().Int("n", i+1).Int("total", len(samples)).Str("id", sample.ID).Msg("eval: sample analyzed")
+	}
+
+	if report.Samples > 0 {
+		report.FailureRate = float64(report.Failures) / float64(report.Samples)
+		report.FormatFailureRate = float64(report.FormatFailures) / float64(report.Samples)
+	}
+
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		p := pairs[key]
+		metric := MetricReport{
+			Key: key,
+			N:   len(p.predicted),
+			MAE: meanAbsoluteError(p.predicted, p.labelled),
+		}
+		if rho := spearman(p.predicted, p.labelled); !math.IsNaN(rho) {
+			metric.Spearman = &rho
+		}
+		report.Metrics = append(report.Metrics, metric)
+	}
+
+	for _, kind := range []string{"title", "content"} {
+		if p, ok := pairs[kind+"."+ScoreKey]; ok {
+			report.Distribution[kind] = distribution(p)
+		}
+	}
+
+	if e.usage != nil {
+		report.Usage = e.usage.Usage()
+	}
+
+	return report, nil
+}
+
+// scorePairs 模型分數與人工標註分數.
+type scorePairs struct {
+	predicted []float64
+	labelled  []float64
+}
+
+// collect 收集有人工標註的指標.
+func collect(pairs map[string]*scorePairs, kind string, analytics dto.Analytics, labels map[string]float64) {
+	predicted := map[string]float64{ScoreKey: analytics.Score}
+	for _, metric := range analytics.MetricList {
+		predicted[metric.MetricKey] = metric.Score
+	}
+
+	for key, label := range labels {
+		score, ok := predicted[key]
+		if !ok {
+			continue
+		}
+
+		fullKey := kind + "." + key
+		if pairs[fullKey] == nil {
+			pairs[fullKey] = &scorePairs{}
+		}
+		pairs[fullKey].predicted = append(pairs[fullKey].predicted, score)
+		pairs[fullKey].labelled = append(pairs[fullKey].labelled, label)
+	}
+}
+
+func distribution(p *scorePairs) Distribution {
+	var d Distribution
+	for i := range p.predicted {
+		d.Predicted[bucket(p.predicted[i])]++
+		d.Labelled[bucket(p.labelled[i])]++
+	}
+	d.PredictedMean = mean(p.predicted)
+	d.LabelledMean = mean(p.labelled)
+	return d
+}
+
+func bucket(score float64) int {
+	return min(max(int(score), 0), bucketCount-1)
+}
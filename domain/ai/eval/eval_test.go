@@ -0,0 +1,150 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"itmrchow/tw-media-analytics-service/domain/ai"
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+// fakeModel 依標題回傳固定分數, 並記錄使用量.
+type fakeModel struct {
+	scores   map[string]float64
+	recorder ai.UsageRecorder
+}
+
+func (m *fakeModel) AnalyzeNews(_ context.Context, title string, _ string) (*dto.NewsAnalytics, error) {
+	m.recorder.RecordUsage(context.Background(), dto.Usage{
+		Model: "model-a", PromptTokens: 1000, CompletionTokens: 100, TotalTokens: 1100,
+	})
+
+	score, ok := m.scores[title]
+	if !ok {
+		return nil, fmt.Errorf("%w: no JSON code block found", ai.ErrInvalidResponse)
+	}
+
+	return &dto.NewsAnalytics{
+		TitleAnalytics: dto.Analytics{
+			Score: score,
+			MetricList: []dto.Metric{
+				{MetricKey: "accuracy", Score: score},
+				{MetricKey: "clarity", Score: score},
+			},
+		},
+		ContentAnalytics: dto.Analytics{Score: 3},
+	}, nil
+}
+
+func (m *fakeModel) Ping(context.Context) error { return nil }
+
+func (m *fakeModel) CloseClient() error { return nil }
+
+func TestLoadDataset(t *testing.T) {
+	samples, err := LoadDataset(filepath.Join("testdata", "dataset.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, samples, 4)
+
+	assert.Equal(t, "n1", samples[0].ID)
+	assert.Equal(t, 4.5, samples[0].TitleScores[ScoreKey])
+	assert.Equal(t, "line-6", samples[3].ID)
+
+	_, err = LoadDataset(filepath.Join("testdata", "not-found.jsonl"))
+	assert.Error(t, err)
+}
+
+func runTestEval(t *testing.T, scores map[string]float64) *Report {
+	t.Helper()
+
+	samples, err := LoadDataset(filepath.Join("testdata", "dataset.jsonl"))
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	usage := NewUsageCollector(ai.Pricing{"model-a": {Input: 1, Output: 10}})
+	model := &fakeModel{scores: scores, recorder: usage}
+
+	report, err := NewEvaluator(&logger, model, usage).Run(context.Background(), samples)
+	require.NoError(t, err)
+
+	return report
+}
+
+func TestEvaluator_Run(t *testing.T) {
+	report := runTestEval(t, map[string]float64{
+		"行政院通過明年度總預算案":  4,
+		"震驚！史上最強颱風竟然轉向": 2,
+		"無 id": 3,
+	})
+
+	assert.Equal(t, 4, report.Samples)
+	assert.Equal(t, 3, report.Succeeded)
+	assert.Equal(t, 1, report.FormatFailures)
+	assert.Equal(t, 0.25, report.FormatFailureRate)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, "n3", report.Errors[0].ID)
+
+	metrics := map[string]MetricReport{}
+	for _, m := range report.Metrics {
+		metrics[m.Key] = m
+	}
+	require.Contains(t, metrics, "title.score")
+	assert.Equal(t, 3, metrics["title.score"].N)
+	// |4-4.5| + |2-1.5| + |3-2.5| = 1.5
+	assert.InDelta(t, 0.5, metrics["title.score"].MAE, 1e-9)
+	require.NotNil(t, metrics["title.score"].Spearman)
+	assert.InDelta(t, 1, *metrics["title.score"].Spearman, 1e-9)
+	// content 分數固定, 無法計算相關係數
+	assert.Nil(t, metrics["content.score"].Spearman)
+	assert.NotContains(t, metrics, "content.accuracy")
+
+	assert.Equal(t, [bucketCount]int{0, 0, 1, 1, 1}, report.Distribution["title"].Predicted)
+	assert.Equal(t, [bucketCount]int{0, 1, 1, 0, 1}, report.Distribution["title"].Labelled)
+
+	// 每次 1000 prompt + 100 completion tokens = 0.001 + 0.001 USD
+	assert.Equal(t, int64(4), report.Usage.Calls)
+	assert.InDelta(t, 0.008, report.Usage.Cost, 1e-9)
+}
+
+func TestCompare(t *testing.T) {
+	base := runTestEval(t, map[string]float64{"行政院通過明年度總預算案": 2, "震驚！史上最強颱風竟然轉向": 4})
+	base.Name = "model-a@v1"
+
+	candidate := runTestEval(t, map[string]float64{
+		"行政院通過明年度總預算案":  4,
+		"震驚！史上最強颱風竟然轉向": 2,
+		"格式錯誤": 3,
+		"無 id": 3,
+	})
+	candidate.Name = "model-a@v2"
+
+	path := filepath.Join(t.TempDir(), "base.json")
+	require.NoError(t, WriteReport(path, base))
+	base, err := ReadReport(path)
+	require.NoError(t, err)
+
+	comparison := Compare(base, candidate)
+
+	var mc MetricComparison
+	for _, m := range comparison.Metrics {
+		if m.Key == "title.score" {
+			mc = m
+		}
+	}
+	require.NotNil(t, mc.Base)
+	require.NotNil(t, mc.Candidate)
+	assert.Less(t, mc.Candidate.MAE, mc.Base.MAE)
+
+	var buf bytes.Buffer
+	require.NoError(t, comparison.WriteMarkdown(&buf))
+	out := buf.String()
+	assert.Contains(t, out, "- base: model-a@v1")
+	assert.Contains(t, out, "| format failure rate | 50.0% | 0.0% | -50.0% |")
+	assert.Contains(t, out, "| title.score | 4 | 2.500 | 0.375 | -2.125 | -1.000 | 0.949 | +1.949 |")
+}
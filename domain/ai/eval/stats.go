@@ -0,0 +1,78 @@
+package eval
+
+import (
+	"math"
+	"sort"
+)
+
+// meanAbsoluteError 平均絕對誤差.
+func meanAbsoluteError(predicted []float64, labelled []float64) float64 {
+	if len(predicted) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for i := range predicted {
+		sum += math.Abs(predicted[i] - labelled[i])
+	}
+	return sum / float64(len(predicted))
+}
+
+// spearman Spearman 等級相關係數, 相同分數取平均等級; 樣本不足或無變異時回傳 NaN.
+func spearman(x []float64, y []float64) float64 {
+	if len(x) < 2 {
+		return math.NaN()
+	}
+	return pearson(ranks(x), ranks(y))
+}
+
+func pearson(x []float64, y []float64) float64 {
+	mx, my := mean(x), mean(y)
+
+	var cov, vx, vy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(vx*vy)
+}
+
+// ranks 回傳每個值的等級 (1 起算), 同分取平均等級.
+func ranks(values []float64) []float64 {
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return values[idx[a]] < values[idx[b]] })
+
+	result := make([]float64, len(values))
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && values[idx[j+1]] == values[idx[i]] {
+			j++
+		}
+		rank := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			result[idx[k]] = rank
+		}
+		i = j + 1
+	}
+	return result
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,41 @@
+package eval
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeanAbsoluteError(t *testing.T) {
+	assert.InDelta(t, 0.5, meanAbsoluteError([]float64{1, 2, 3}, []float64{1.5, 2.5, 2.5}), 1e-9)
+	assert.Equal(t, 0.0, meanAbsoluteError(nil, nil))
+}
+
+func TestRanks(t *testing.T) {
+	// 同分取平均等級
+	assert.Equal(t, []float64{3, 1.5, 4, 1.5}, ranks([]float64{3, 1, 4, 1}))
+}
+
+func TestSpearman(t *testing.T) {
+	tests := []struct {
+		name string
+		x    []float64
+		y    []float64
+		want float64
+	}{
+		{name: "完全一致", x: []float64{1, 2, 3, 4}, y: []float64{10, 20, 30, 40}, want: 1},
+		{name: "完全相反", x: []float64{1, 2, 3, 4}, y: []float64{4, 3, 2, 1}, want: -1},
+		{name: "單調但非線性", x: []float64{1, 2, 3, 4}, y: []float64{1, 4, 9, 100}, want: 1},
+		{name: "有同分", x: []float64{1, 2, 2, 3}, y: []float64{1, 2, 3, 4}, want: 0.9486832980505138},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, spearman(tt.x, tt.y), 1e-9)
+		})
+	}
+
+	assert.True(t, math.IsNaN(spearman([]float64{1}, []float64{1})), "樣本不足")
+	assert.True(t, math.IsNaN(spearman([]float64{2, 2, 2}, []float64{1, 2, 3})), "無變異")
+}
@@ -0,0 +1,45 @@
+package eval
+
+import (
+	"context"
+	"sync"
+
+	"itmrchow/tw-media-analytics-service/domain/ai"
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+var _ ai.UsageRecorder = &UsageCollector{}
+
+// UsageCollector 於記憶體累計評估期間的 token 使用量與費用, 不寫入資料庫.
+type UsageCollector struct {
+	pricing ai.Pricing
+
+	mu    sync.Mutex
+	usage UsageReport
+}
+
+// NewUsageCollector 建立使用量累計器.
+func NewUsageCollector(pricing ai.Pricing) *UsageCollector {
+	return &UsageCollector{pricing: pricing}
+}
+
+func (c *UsageCollector) RecordUsage(_ context.Context, usage dto.Usage) {
+	cost, _ := c.pricing.Cost(usage.Model, usage.PromptTokens, usage.CompletionTokens).Float64()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.usage.Calls++
+	c.usage.PromptTokens += usage.PromptTokens
+	c.usage.CompletionTokens += usage.CompletionTokens
+	c.usage.TotalTokens += usage.TotalTokens
+	c.usage.Cost += cost
+}
+
+// Usage 目前累計的使用量.
+func (c *UsageCollector) Usage() UsageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.usage
+}
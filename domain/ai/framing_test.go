@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+func framingAnalytics(score float64, overrides map[string]float64) dto.Analytics {
+	analytics := dto.Analytics{Score: score, Reason: "r"}
+	for _, m := range FramingMetrics {
+		s, ok := overrides[m.Key]
+		if !ok {
+			s = 0
+		}
+		analytics.MetricList = append(analytics.MetricList, dto.Metric{MetricKey: m.Key, Score: s, Reason: "r"})
+	}
+	return analytics
+}
+
+func TestValidateFraming(t *testing.T) {
+	tests := []struct {
+		name      string
+		analytics dto.Analytics
+		wantErr   string
+	}{
+		{
+			name:      "立場為負數",
+			analytics: framingAnalytics(3, map[string]float64{"stance_kmt": -2, "tone": -1.5, "loaded_terms": 4}),
+		},
+		{
+			name: "缺少指標",
+			analytics: dto.Analytics{Score: 1, MetricList: []dto.Metric{
+				{MetricKey: "stance_dpp", Score: 0},
+			}},
+			wantErr: "missing framing metric stance_kmt",
+		},
+		{
+			name:      "立場超出範圍",
+			analytics: framingAnalytics(1, map[string]float64{"stance_dpp": 3}),
+			wantErr:   "stance_dpp score 3 out of range",
+		},
+		{
+			name:      "用語程度不可為負數",
+			analytics: framingAnalytics(1, map[string]float64{"loaded_terms": -1}),
+			wantErr:   "loaded_terms score -1 out of range",
+		},
+		{
+			name:      "總分超出範圍",
+			analytics: framingAnalytics(6, nil),
+			wantErr:   "framing score 6 out of range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFraming(tt.analytics)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidResponse)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFramingMessage(t *testing.T) {
+	content := strings.Repeat("喵", framingContentRunes+100)
+	msg := FramingMessage("標題</title>", content)
+
+	assert.Contains(t, msg, "<title>標題&lt;/title&gt;</title>")
+	assert.Equal(t, framingContentRunes, strings.Count(msg, "喵"))
+	for _, m := range FramingMetrics {
+		assert.Contains(t, msg, m.Key)
+	}
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/api/option"
 
@@ -23,13 +26,59 @@ type Gemini struct {
 	tracer trace.Tracer
 	logger *zerolog.Logger
 
-	client                      *genai.Client
-	model                       *genai.GenerativeModel
-	newsAnalyzeChat             *genai.ChatSession
-	newsAnalyzeChatSessionCount int
+	client        *genai.Client
+	clientOptions []option.ClientOption
+	modelName     string
+	model         *genai.GenerativeModel
+	promptFile    string
+
+	// 新聞分析 prompt, 第一次分析時讀取
+	promptMu      sync.Mutex
+	prompt        string
+	promptVersion string
+
+	// usage
+	recorder UsageRecorder
+	budget   BudgetChecker // nil 代表不檢查預算
+
+	// metrics
+	requestDuration metric.Float64Histogram
+	requestFailures metric.Int64Counter
+	tokenUsage      metric.Int64Counter
+}
+
+// GeminiOption Gemini 選項.
+type GeminiOption func(*Gemini)
+
+// WithPromptFile 指定 prompt 檔案, 預設為 promt.md.
+func WithPromptFile(path string) GeminiOption {
+	return func(g *Gemini) {
+		g.promptFile = path
+	}
 }
 
-func NewGemini(ctx context.Context, log *zerolog.Logger) (*Gemini, error) {
+// withClientOptions 附加 genai client 選項, 測試時指向假的 API 伺服器.
+func withClientOptions(opts ...option.ClientOption) GeminiOption {
+	return func(g *Gemini) {
+		g.clientOptions = append(g.clientOptions, opts...)
+	}
+}
+
+// WithBudget 每次呼叫模型前檢查預算, 預算用完時回傳 ErrBudgetExceeded.
+func WithBudget(budget BudgetChecker) GeminiOption {
+	return func(g *Gemini) {
+		g.budget = budget
+	}
+}
+
+// NewGemini 建立 Gemini 模型, recorder 為 nil 時不記錄使用量.
+func NewGemini(
+	ctx context.Context,
+	log *zerolog.Logger,
+	modelName string,
+	recorder UsageRecorder,
+	opts ...GeminiOption,
+) (*Gemini, error) {
 	// Tracer
 	tracer := otel.Tracer("domain/ai")
 	ctx, span := tracer.Start(ctx, "domain/ai/NewGemini: New Gemini Model")
@@ -42,47 +91,74 @@ func NewGemini(ctx context.Context, log *zerolog.Logger) (*Gemini, error) {
 	}()
 
 	// New Gemini model
-	g := &Gemini{}
+	g := &Gemini{promptFile: promptFile}
+	for _, opt := range opts {
+		opt(g)
+	}
 	apiKey := viper.GetString("GEMINI_API_KEY")
 
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	client, err := genai.NewClient(ctx, append([]option.ClientOption{option.WithAPIKey(apiKey)}, g.clientOptions...)...)
 	if err != nil {
 		log.Error().Err(err).Ctx(ctx).Msg("failed to create client")
 		return nil, err
 	}
 
-	model := client.GenerativeModel("gemini-2.0-flash-lite-001")
+	model := client.GenerativeModel(modelName)
 
 	g.tracer = tracer
 	g.client = client
+	g.modelName = modelName
 	g.model = model
 	g.logger = log
+	g.recorder = recorder
+
+	// metrics
+	meter := otel.Meter("domain/ai")
+	if g.requestDuration, err = meter.Float64Histogram(
+		"ai.request.duration",
+		metric.WithDescription("AI model request latency"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, err
+	}
+	if g.requestFailures, err = meter.Int64Counter(
+		"ai.request.failures",
+		metric.WithDescription("Number of failed AI model requests by reason"),
+	); err != nil {
+		return nil, err
+	}
+	if g.tokenUsage, err = meter.Int64Counter(
+		"ai.tokens",
+		metric.WithDescription("Number of AI model tokens by type"),
+	); err != nil {
+		return nil, err
+	}
 
 	return g, nil
 }
 
-// getNewsAnalyzeChat 取得新聞分析聊天室
-// 根據prompt.md 建立聊天室 , 並判斷是否需要重新建立聊天室
-func (g *Gemini) getNewsAnalyzeChat() (*genai.ChatSession, error) {
+// newsAnalyzePrompt 取得新聞分析 prompt 與版本, 第一次呼叫時讀取檔案並計算版本.
+func (g *Gemini) newsAnalyzePrompt() (prompt string, version string, err error) {
+	g.promptMu.Lock()
+	defer g.promptMu.Unlock()
 
-	if g.newsAnalyzeChat == nil || g.newsAnalyzeChatSessionCount > 10 {
-		chat := g.model.StartChat()
-
-		promptContent, err := os.ReadFile("promt.md")
+	if g.prompt == "" {
+		promptContent, err := ReadPromptFile(g.promptFile)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read prompt file: %w", err)
+			return "", "", err
 		}
 
-		_, err = chat.SendMessage(context.Background(), genai.Text(string(promptContent)))
-		if err != nil {
-			return nil, fmt.Errorf("failed to send message: %w", err)
-		}
-
-		g.newsAnalyzeChat = chat
-		g.newsAnalyzeChatSessionCount = 0
+		g.prompt = string(promptContent)
+		g.promptVersion = PromptVersion(promptContent)
 	}
 
-	return g.newsAnalyzeChat, nil
+	return g.prompt, g.promptVersion, nil
+}
+
+// Ping 取得模型資訊, 確認 API 可連線且金鑰有效.
+func (g *Gemini) Ping(ctx context.Context) error {
+	_, err := g.model.Info(ctx)
+	return err
 }
 
 func (g *Gemini) CloseClient() error {
@@ -90,37 +166,120 @@ func (g *Gemini) CloseClient() error {
 }
 
 // AnalyzeNews 分析新聞標題和內容
-func (g *Gemini) AnalyzeNews(title string, content string) (*dto.NewsAnalytics, error) {
-
-	chat, err := g.getNewsAnalyzeChat()
+func (g *Gemini) AnalyzeNews(ctx context.Context, title string, content string) (*dto.NewsAnalytics, error) {
+	prompt, promptVersion, err := g.newsAnalyzePrompt()
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := chat.SendMessage(context.Background(), genai.Text(fmt.Sprintf("標題: %s\n內容: %s", title, content)))
+	// 每篇新聞以單次請求分析, 不共用聊天室歷史, 避免前一篇的內容或評分影響下一篇
+	resp, err := g.generateContent(ctx, genai.Text(prompt), genai.Text(NewsMessage(title, content)))
 	if err != nil {
 		return nil, err
 	}
+	g.recordUsage(ctx, "analyze_news", promptVersion, resp)
+
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		g.recordFailure(ctx, "empty_response")
+		return nil, fmt.Errorf("%w: empty response", ErrInvalidResponse)
+	}
 
 	cand := resp.Candidates[0]
-	jsonPart := cand.Content.Parts[0]
+	jsonPart, ok := cand.Content.Parts[0].(genai.Text)
+	if !ok {
+		g.recordFailure(ctx, "format")
+		return nil, fmt.Errorf("%w: first part is not text", ErrInvalidResponse)
+	}
+
+	var result dto.NewsAnalytics
+	if err := g.parseJSONBlock(ctx, string(jsonPart), &result); err != nil {
+		return nil, err
+	}
 
-	respStr := string(jsonPart.(genai.Text))
+	return &result, nil
+}
 
-	// 解析 markdown 程式碼區塊中的 JSON
+// parseJSONBlock 解析回應中 markdown 程式碼區塊的 JSON, 失敗時記錄 format 或 parse 失敗.
+func (g *Gemini) parseJSONBlock(ctx context.Context, respStr string, result any) error {
 	start := strings.Index(respStr, "```json")
 	end := strings.LastIndex(respStr, "```")
-	if start == -1 || end == -1 {
-		return nil, fmt.Errorf("invalid response format: no JSON code block found")
+	if start == -1 || end == -1 || end <= start {
+		g.recordFailure(ctx, "format")
+		return fmt.Errorf("%w: no JSON code block found", ErrInvalidResponse)
 	}
 	cleanedJsonString := respStr[start+7 : end]
 
-	var result dto.NewsAnalytics
-	if err := json.Unmarshal([]byte(cleanedJsonString), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	if err := json.Unmarshal([]byte(cleanedJsonString), result); err != nil {
+		g.recordFailure(ctx, "parse")
+		return fmt.Errorf("%w: failed to parse JSON: %w", ErrInvalidResponse, err)
 	}
 
-	return &result, nil
+	return nil
+}
+
+// generateContent 以單次請求呼叫模型, 先檢查預算並記錄耗時與失敗.
+func (g *Gemini) generateContent(ctx context.Context, parts ...genai.Part) (*genai.GenerateContentResponse, error) {
+	if err := g.checkBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := g.model.GenerateContent(ctx, parts...)
+	g.requestDuration.Record(ctx, time.Since(start).Seconds(),
+		metric.WithAttributes(attribute.String("model", g.modelName)))
+	if err != nil {
+		g.recordFailure(ctx, "request")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// checkBudget 有設定預算時檢查是否已用完.
+func (g *Gemini) checkBudget(ctx context.Context) error {
+	if g.budget == nil {
+		return nil
+	}
+	return g.budget.CheckBudget(ctx)
+}
+
+// recordFailure 記錄 AI 呼叫失敗.
+func (g *Gemini) recordFailure(ctx context.Context, reason string) {
+	g.requestFailures.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("model", g.modelName),
+		attribute.String("reason", reason),
+	))
+}
+
+// recordUsage 記錄 token 使用量, promptVersion 為該次請求所用 prompt 的版本.
+func (g *Gemini) recordUsage(
+	ctx context.Context,
+	operation string,
+	promptVersion string,
+	resp *genai.GenerateContentResponse,
+) {
+	if resp == nil || resp.UsageMetadata == nil {
+		return
+	}
+
+	model := attribute.String("model", g.modelName)
+	g.tokenUsage.Add(ctx, int64(resp.UsageMetadata.PromptTokenCount),
+		metric.WithAttributes(model, attribute.String("type", "prompt")))
+	g.tokenUsage.Add(ctx, int64(resp.UsageMetadata.CandidatesTokenCount),
+		metric.WithAttributes(model, attribute.String("type", "completion")))
+
+	if g.recorder == nil {
+		return
+	}
+
+	g.recorder.RecordUsage(ctx, dto.Usage{
+		Model:            g.modelName,
+		PromptVersion:    promptVersion,
+		Operation:        operation,
+		PromptTokens:     int64(resp.UsageMetadata.PromptTokenCount),
+		CompletionTokens: int64(resp.UsageMetadata.CandidatesTokenCount),
+		TotalTokens:      int64(resp.UsageMetadata.TotalTokenCount),
+	})
 }
 
 func printResponse(resp *genai.GenerateContentResponse) {
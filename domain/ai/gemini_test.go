@@ -0,0 +1,168 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+// fakeGeminiRequest generateContent 請求中的對話內容.
+type fakeGeminiRequest struct {
+	Contents []struct {
+		Role  string `json:"role"`
+		Parts []struct {
+			Text string `json:"text"`
+		} `json:"parts"`
+	} `json:"contents"`
+}
+
+// fakeGeminiServer 記錄 generateContent 請求, 固定回傳 3 分的分析結果.
+type fakeGeminiServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []fakeGeminiRequest
+}
+
+func newFakeGeminiServer(t *testing.T) *fakeGeminiServer {
+	t.Helper()
+
+	s := &fakeGeminiServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req fakeGeminiRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		s.mu.Unlock()
+
+		result := "```json\n" + `{"titleAnalytics":{"score":3},"contentAnalytics":{"score":3}}` + "\n```"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []any{map[string]any{
+				"content": map[string]any{"role": "model", "parts": []any{map[string]any{"text": result}}},
+			}},
+			"usageMetadata": map[string]any{"promptTokenCount": 10, "candidatesTokenCount": 5, "totalTokenCount": 15},
+		})
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *fakeGeminiServer) Requests() []fakeGeminiRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]fakeGeminiRequest(nil), s.requests...)
+}
+
+// newTestGemini 建立連線到假 API 伺服器的 Gemini.
+func newTestGemini(t *testing.T, server *fakeGeminiServer) *Gemini {
+	t.Helper()
+
+	prompt := filepath.Join(t.TempDir(), "prompt.md")
+	require.NoError(t, os.WriteFile(prompt, []byte("test prompt"), 0o644))
+
+	viper.Set("GEMINI_API_KEY", "test")
+	t.Cleanup(func() { viper.Set("GEMINI_API_KEY", nil) })
+
+	logger := zerolog.Nop()
+	gemini, err := NewGemini(context.Background(), &logger, "test-model", nil,
+		WithPromptFile(prompt),
+		withClientOptions(option.WithEndpoint(server.URL)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = gemini.CloseClient() })
+
+	return gemini
+}
+
+func TestGemini_AnalyzeNews_NoSharedHistory(t *testing.T) {
+	server := newFakeGeminiServer(t)
+	gemini := newTestGemini(t, server)
+
+	titles := []string{"第一篇", "第二篇", "第三篇"}
+	for _, title := range titles {
+		result, err := gemini.AnalyzeNews(context.Background(), title, "內容")
+		require.NoError(t, err)
+		assert.Equal(t, 3.0, result.TitleAnalytics.Score)
+	}
+
+	// 每篇新聞都是只有 prompt 與該篇新聞的單次請求
+	requests := server.Requests()
+	require.Len(t, requests, len(titles))
+	for i, req := range requests {
+		require.Len(t, req.Contents, 1)
+		require.Len(t, req.Contents[0].Parts, 2)
+		assert.Equal(t, "test prompt", req.Contents[0].Parts[0].Text)
+
+		text := req.Contents[0].Parts[1].Text
+		for j, title := range titles {
+			assert.Equal(t, i == j, strings.Contains(text, title))
+		}
+	}
+}
+
+// fakeUsageRecorder 記錄 Gemini 回報的用量.
+type fakeUsageRecorder struct {
+	mu        sync.Mutex
+	usageList []dto.Usage
+}
+
+func (r *fakeUsageRecorder) RecordUsage(_ context.Context, usage dto.Usage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.usageList = append(r.usageList, usage)
+}
+
+// promptVersions 各 operation 記錄的 prompt 版本.
+func (r *fakeUsageRecorder) promptVersions() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions := make(map[string]string, len(r.usageList))
+	for _, usage := range r.usageList {
+		versions[usage.Operation] = usage.PromptVersion
+	}
+	return versions
+}
+
+func TestGemini_RecordUsage_PromptVersion(t *testing.T) {
+	server := newFakeGeminiServer(t)
+	gemini := newTestGemini(t, server)
+	recorder := &fakeUsageRecorder{}
+	gemini.recorder = recorder
+
+	// 新聞分析前呼叫, 版本不受新聞分析 prompt 影響; 回應格式不符只影響解析
+	_, _ = gemini.AnalyzeFraming(context.Background(), "標題", "內容")
+	_, _ = gemini.SummarizeNews(context.Background(), "標題", "內容")
+	categories := map[string]string{"politics": "政治", "society": "社會"}
+	_, _ = gemini.ClassifyCategory(context.Background(), "標題", "內容", categories)
+	_, err := gemini.AnalyzeNews(context.Background(), "標題", "內容")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"analyze_framing":   PromptVersion([]byte(framingPrompt)),
+		"summarize_news":    PromptVersion([]byte(summaryPrompt)),
+		"classify_category": PromptVersion([]byte(categoryPrompt(categories))),
+		"analyze_news":      PromptVersion([]byte("test prompt")),
+	}, recorder.promptVersions())
+}
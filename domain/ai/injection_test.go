@@ -0,0 +1,137 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+)
+
+// injectionCase 注入測試語料.
+type injectionCase struct {
+	Name     string   `json:"name"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Patterns []string `json:"patterns"`
+}
+
+func loadInjectionCases(t *testing.T, name string) []injectionCase {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "injection", name))
+	require.NoError(t, err)
+
+	var cases []injectionCase
+	require.NoError(t, json.Unmarshal(data, &cases))
+	require.NotEmpty(t, cases)
+
+	return cases
+}
+
+// naiveProvider 模擬會照做指令的模型: 只把 <news> 區塊內視為資料,
+// 區塊外若出現類似指令的文字就被操弄而給 5 分, 否則給 2 分.
+type naiveProvider struct{}
+
+func (naiveProvider) AnalyzeNews(_ context.Context, title string, content string) (*dto.NewsAnalytics, error) {
+	msg := NewsMessage(title, content)
+
+	score := 2.0
+	if outside := outsideDataBlock(msg); len(DetectInjection(outside)) > 0 {
+		score = 5.0
+	}
+
+	return &dto.NewsAnalytics{
+		TitleAnalytics:   dto.Analytics{Score: score},
+		ContentAnalytics: dto.Analytics{Score: score},
+	}, nil
+}
+
+func (naiveProvider) Ping(context.Context) error { return nil }
+
+func (naiveProvider) CloseClient() error { return nil }
+
+// outsideDataBlock 移除第一個 <news> 到第一個 </news> 之間的資料, 回傳剩下的文字.
+func outsideDataBlock(msg string) string {
+	start := strings.Index(msg, "<news>")
+	end := strings.Index(msg, "</news>")
+	if start == -1 || end == -1 || end < start {
+		return msg
+	}
+	return msg[:start] + msg[end+len("</news>"):]
+}
+
+func TestDetectInjection_Attacks(t *testing.T) {
+	for _, tc := range loadInjectionCases(t, "attacks.json") {
+		t.Run(tc.Name, func(t *testing.T) {
+			patterns := DetectInjection(tc.Title + "\n" + tc.Content)
+			for _, want := range tc.Patterns {
+				assert.Contains(t, patterns, want)
+			}
+		})
+	}
+}
+
+func TestDetectInjection_Benign(t *testing.T) {
+	for _, tc := range loadInjectionCases(t, "benign.json") {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Empty(t, DetectNewsInjection(tc.Title, tc.Content))
+		})
+	}
+}
+
+// between 回傳 msg 中第一個 start 之後到第一個 end 之前的文字.
+func between(t *testing.T, msg string, start string, end string) string {
+	t.Helper()
+
+	i := strings.Index(msg, start)
+	require.NotEqual(t, -1, i, "missing %q", start)
+	rest := msg[i+len(start):]
+
+	j := strings.Index(rest, end)
+	require.NotEqual(t, -1, j, "missing %q", end)
+	return rest[:j]
+}
+
+func TestNewsMessage_Attacks(t *testing.T) {
+	var model AiModel = naiveProvider{}
+
+	for _, tc := range loadInjectionCases(t, "attacks.json") {
+		t.Run(tc.Name, func(t *testing.T) {
+			// 分析結果會標記為疑似注入
+			assert.NotEmpty(t, DetectNewsInjection(tc.Title, tc.Content), "InjectionSuspected 應為 true")
+
+			msg := NewsMessage(tc.Title, tc.Content)
+
+			// 區塊外只有固定說明, 第一個結束標記就是訊息結尾
+			assert.Equal(t, newsInstruction, outsideDataBlock(msg))
+			assert.Equal(t, len(msg)-len("</news>"), strings.Index(msg, "</news>"))
+
+			// 標題與內容各自完整留在對應區塊內, 無法提前結束區塊
+			title := between(t, msg, "<news>\n<title>", "</title>\n<content>\n")
+			content := between(t, msg, "</title>\n<content>\n", "\n</content>\n</news>")
+			assert.NotContains(t, title, "<")
+			assert.NotContains(t, content, "<")
+			assert.Equal(t, tc.Title, html.UnescapeString(title))
+			assert.Equal(t, tc.Content, html.UnescapeString(content))
+
+			result, err := model.AnalyzeNews(context.Background(), tc.Title, tc.Content)
+			require.NoError(t, err)
+			assert.Equal(t, 2.0, result.ContentAnalytics.Score, "注入文字不應離開資料區塊")
+		})
+	}
+}
+
+func TestNewsMessage_Escape(t *testing.T) {
+	msg := NewsMessage("A&B", "x < y > z")
+
+	assert.Contains(t, msg, "<title>A&amp;B</title>")
+	assert.Contains(t, msg, "x &lt; y &gt; z")
+}
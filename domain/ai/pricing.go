@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// ModelPrice 模型每百萬 token 的價格 (USD).
+type ModelPrice struct {
+	Input  float64 `mapstructure:"input"`
+	Output float64 `mapstructure:"output"`
+}
+
+// defaultPricing 預設價格, 可由 AI_MODEL_PRICING 覆寫或新增.
+var defaultPricing = map[string]ModelPrice{
+	"gemini-2.0-flash-001":      {Input: 0.10, Output: 0.40},
+	"gemini-2.0-flash-lite-001": {Input: 0.075, Output: 0.30},
+	"gemini-1.5-flash-8b":       {Input: 0.0375, Output: 0.15},
+}
+
+// Pricing 各模型的價格表.
+type Pricing map[string]ModelPrice
+
+// NewPricing 以預設價格與 AI_MODEL_PRICING 建立價格表.
+func NewPricing() (Pricing, error) {
+	pricing := make(Pricing, len(defaultPricing))
+	for model, price := range defaultPricing {
+		pricing[model] = price
+	}
+
+	var override map[string]ModelPrice
+	if err := viper.UnmarshalKey("AI_MODEL_PRICING", &override); err != nil {
+		return nil, err
+	}
+	for model, price := range override {
+		pricing[model] = price
+	}
+
+	return pricing, nil
+}
+
+// Cost 估算費用 (USD), 未知模型回傳 0.
+func (p Pricing) Cost(model string, promptTokens int64, completionTokens int64) decimal.Decimal {
+	price, ok := p[model]
+	if !ok {
+		return decimal.Zero
+	}
+
+	million := decimal.NewFromInt(1_000_000)
+	input := decimal.NewFromInt(promptTokens).Mul(decimal.NewFromFloat(price.Input)).Div(million)
+	output := decimal.NewFromInt(completionTokens).Mul(decimal.NewFromFloat(price.Output)).Div(million)
+
+	return input.Add(output).Round(6)
+}
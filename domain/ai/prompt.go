@@ -0,0 +1,32 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+)
+
+// promptFile 新聞分析 prompt 檔案.
+const promptFile = "promt.md"
+
+// ReadPrompt 讀取新聞分析 prompt.
+func ReadPrompt() ([]byte, error) {
+	return ReadPromptFile(promptFile)
+}
+
+// ReadPromptFile 讀取指定的 prompt 檔案.
+func ReadPromptFile(path string) ([]byte, error) {
+	promptContent, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt file: %w", err)
+	}
+
+	return promptContent, nil
+}
+
+// PromptVersion 以 prompt 內容的 sha256 前 12 碼作為版本, prompt 修改後版本自動改變.
+func PromptVersion(prompt []byte) string {
+	sum := sha256.Sum256(prompt)
+	return hex.EncodeToString(sum[:])[:12]
+}
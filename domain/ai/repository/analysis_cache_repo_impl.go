@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/entity"
+)
+
+var _ AnalysisCacheRepository = &AnalysisCacheRepositoryImpl{}
+
+type AnalysisCacheRepositoryImpl struct {
+	logger *zerolog.Logger
+	db     *gorm.DB
+}
+
+func NewAnalysisCacheRepositoryImpl(logger *zerolog.Logger, db *gorm.DB) *AnalysisCacheRepositoryImpl {
+	return &AnalysisCacheRepositoryImpl{logger: logger, db: db}
+}
+
+func (r *AnalysisCacheRepositoryImpl) GetAnalysisCache(ctx context.Context, key string) (*entity.AnalysisCache, error) {
+	var cache entity.AnalysisCache
+
+	err := r.db.WithContext(ctx).Where(&entity.AnalysisCache{Key: key}).First(&cache).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis cache: %w", err)
+	}
+
+	return &cache, nil
+}
+
+func (r *AnalysisCacheRepositoryImpl) SaveAnalysisCache(ctx context.Context, cache *entity.AnalysisCache) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"result", "updated_at"}),
+		}).
+		Create(cache).Error
+	if err != nil {
+		return fmt.Errorf("failed to save analysis cache: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AnalysisCacheRepositoryImpl) IncrHitCount(ctx context.Context, key string) error {
+	err := r.db.WithContext(ctx).
+		Model(&entity.AnalysisCache{}).
+		Where(&entity.AnalysisCache{Key: key}).
+		UpdateColumn("hit_count", gorm.Expr("hit_count + ?", 1)).Error
+	if err != nil {
+		return fmt.Errorf("failed to increase analysis cache hit count: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/entity"
+)
+
+type AnalysisCacheRepository interface {
+	// GetAnalysisCache 取得分析快取, 不存在時回傳 nil
+	GetAnalysisCache(ctx context.Context, key string) (*entity.AnalysisCache, error)
+	// SaveAnalysisCache 新增或覆寫分析快取
+	SaveAnalysisCache(ctx context.Context, cache *entity.AnalysisCache) error
+	// IncrHitCount 快取命中次數 +1
+	IncrHitCount(ctx context.Context, key string) error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/entity"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
+)
+
+func TestAnalysisCacheRepoSuite(t *testing.T) {
+	suite.Run(t, new(AnalysisCacheTestSuite))
+}
+
+type AnalysisCacheTestSuite struct {
+	suite.Suite
+	cacheRepo AnalysisCacheRepository
+}
+
+func (s *AnalysisCacheTestSuite) SetupTest() {
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	logger := zerolog.New(os.Stdout).Level(zerolog.DebugLevel)
+
+	db := testdb.New(context.Background(), &logger, tracer)
+
+	// 清空測試資料
+	s.Require().NoError(db.Exec("DELETE FROM analysis_caches").Error)
+
+	s.cacheRepo = NewAnalysisCacheRepositoryImpl(&logger, db)
+}
+
+func (s *AnalysisCacheTestSuite) TestGetAnalysisCache_NotFound() {
+	cache, err := s.cacheRepo.GetAnalysisCache(context.Background(), "not-found")
+	s.NoError(err)
+	s.Nil(cache)
+}
+
+func (s *AnalysisCacheTestSuite) TestSaveAnalysisCache() {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	s.Require().NoError(s.cacheRepo.SaveAnalysisCache(ctx, &entity.AnalysisCache{
+		Key: "key-1", Model: "model-a", PromptVersion: "v1", Result: `{"v":1}`, CreatedAt: now, UpdatedAt: now,
+	}))
+	s.Require().NoError(s.cacheRepo.IncrHitCount(ctx, "key-1"))
+
+	// 相同 key 覆寫結果
+	s.Require().NoError(s.cacheRepo.SaveAnalysisCache(ctx, &entity.AnalysisCache{
+		Key: "key-1", Model: "model-a", PromptVersion: "v1", Result: `{"v":2}`, CreatedAt: now, UpdatedAt: now.Add(time.Hour),
+	}))
+
+	cache, err := s.cacheRepo.GetAnalysisCache(ctx, "key-1")
+	s.Require().NoError(err)
+	s.Require().NotNil(cache)
+	s.Equal(`{"v":2}`, cache.Result)
+	s.Equal(int64(1), cache.HitCount)
+	s.Equal("model-a", cache.Model)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/entity"
+)
+
+var _ UsageRepository = &UsageRepositoryImpl{}
+
+type UsageRepositoryImpl struct {
+	logger *zerolog.Logger
+	db     *gorm.DB
+}
+
+func NewUsageRepositoryImpl(logger *zerolog.Logger, db *gorm.DB) *UsageRepositoryImpl {
+	return &UsageRepositoryImpl{logger: logger, db: db}
+}
+
+func (r *UsageRepositoryImpl) CreateUsage(ctx context.Context, usage *entity.AiUsage) error {
+	if err := r.db.WithContext(ctx).Create(usage).Error; err != nil {
+		return fmt.Errorf("failed to create ai usage: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UsageRepositoryImpl) SumUsage(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) ([]*entity.AiUsageSummary, error) {
+	var summaries []*entity.AiUsageSummary
+
+	result := r.db.WithContext(ctx).
+		Model(&entity.AiUsage{}).
+		Select("model, COUNT(*) AS calls, "+
+			"SUM(prompt_tokens) AS prompt_tokens, "+
+			"SUM(completion_tokens) AS completion_tokens, "+
+			"SUM(total_tokens) AS total_tokens, "+
+			"SUM(cost) AS cost").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("model").
+		Order("model").
+		Scan(&summaries)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to sum ai usage: %w", result.Error)
+	}
+
+	return summaries, nil
+}
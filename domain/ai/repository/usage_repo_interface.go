@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/entity"
+)
+
+type UsageRepository interface {
+	// CreateUsage 新增一筆 AI 使用紀錄
+	CreateUsage(ctx context.Context, usage *entity.AiUsage) error
+	// SumUsage 依模型彙總 [from, to) 期間的使用量
+	SumUsage(ctx context.Context, from time.Time, to time.Time) ([]*entity.AiUsageSummary, error)
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/entity"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
+)
+
+func TestUsageRepoSuite(t *testing.T) {
+	suite.Run(t, new(UsageTestSuite))
+}
+
+type UsageTestSuite struct {
+	suite.Suite
+	usageRepo UsageRepository
+}
+
+func (s *UsageTestSuite) SetupTest() {
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	logger := zerolog.New(os.Stdout).Level(zerolog.DebugLevel)
+
+	db := testdb.New(context.Background(), &logger, tracer)
+
+	// 清空測試資料
+	s.Require().NoError(db.Exec("DELETE FROM ai_usages").Error)
+
+	s.usageRepo = NewUsageRepositoryImpl(&logger, db)
+}
+
+func (s *UsageTestSuite) TestSumUsage() {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	usageList := []*entity.AiUsage{
+		{Model: "model-a", PromptTokens: 100, CompletionTokens: 10, TotalTokens: 110, Cost: decimal.NewFromFloat(0.01), CreatedAt: now},
+		{Model: "model-a", PromptTokens: 200, CompletionTokens: 20, TotalTokens: 220, Cost: decimal.NewFromFloat(0.02), CreatedAt: now.Add(time.Hour)},
+		{Model: "model-b", PromptTokens: 50, CompletionTokens: 5, TotalTokens: 55, Cost: decimal.NewFromFloat(0.005), CreatedAt: now},
+		// 期間外
+		{Model: "model-a", PromptTokens: 999, CompletionTokens: 99, TotalTokens: 1098, Cost: decimal.NewFromFloat(1), CreatedAt: now.Add(-24 * time.Hour)},
+	}
+	for _, usage := range usageList {
+		usage.PromptVersion = "v1"
+		usage.Operation = "analyze_news"
+		s.Require().NoError(s.usageRepo.CreateUsage(ctx, usage))
+	}
+
+	summaries, err := s.usageRepo.SumUsage(ctx, now.Truncate(24*time.Hour), now.Truncate(24*time.Hour).Add(24*time.Hour))
+	s.Require().NoError(err)
+	s.Require().Len(summaries, 2)
+
+	s.Equal("model-a", summaries[0].Model)
+	s.Equal(int64(2), summaries[0].Calls)
+	s.Equal(int64(300), summaries[0].PromptTokens)
+	s.Equal(int64(30), summaries[0].CompletionTokens)
+	s.Equal(int64(330), summaries[0].TotalTokens)
+	s.True(decimal.NewFromFloat(0.03).Equal(summaries[0].Cost), summaries[0].Cost.String())
+
+	s.Equal("model-b", summaries[1].Model)
+	s.Equal(int64(1), summaries[1].Calls)
+}
+
+func (s *UsageTestSuite) TestSumUsage_Empty() {
+	summaries, err := s.usageRepo.SumUsage(context.Background(), time.Now().Add(-time.Hour), time.Now())
+	s.NoError(err)
+	s.Empty(summaries)
+}
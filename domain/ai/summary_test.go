@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocateQuote(t *testing.T) {
+	content := "行政院今天表示, 明年度總預算為 3 兆元. 立委質疑「預算編列浮濫」."
+
+	tests := []struct {
+		name      string
+		quote     string
+		wantStart int
+		wantEnd   int
+		wantOK    bool
+	}{
+		{
+			name:      "中文以字元計算位置",
+			quote:     "明年度總預算為 3 兆元",
+			wantStart: 9,
+			wantEnd:   21,
+			wantOK:    true,
+		},
+		{
+			name:      "忽略前後引號與空白",
+			quote:     " 「預算編列浮濫」 ",
+			wantStart: 28,
+			wantEnd:   34,
+			wantOK:    true,
+		},
+		{
+			name:   "模型改寫的引文",
+			quote:  "總預算三兆元",
+			wantOK: false,
+		},
+		{
+			name:   "空白引文",
+			quote:  "「」",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			located, start, end, ok := LocateQuote(content, tt.quote)
+			assert.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+			assert.Equal(t, strings.Trim(strings.TrimSpace(tt.quote), "「」"), located)
+			assert.Equal(t, located, string([]rune(content)[start:end]))
+		})
+	}
+}
+
+func TestSummaryMessage(t *testing.T) {
+	msg := SummaryMessage("標題", strings.Repeat("喵", summaryContentRunes+100))
+
+	assert.Equal(t, summaryContentRunes, strings.Count(msg, "喵"))
+	assert.True(t, strings.HasSuffix(msg, "</content>\n</news>"))
+}
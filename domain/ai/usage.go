@@ -0,0 +1,265 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+	"itmrchow/tw-media-analytics-service/domain/ai/entity"
+	"itmrchow/tw-media-analytics-service/domain/ai/repository"
+)
+
+// UsageRecorder 記錄 AI 呼叫的 token 使用量.
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, usage dto.Usage)
+}
+
+// BudgetChecker 於呼叫 AI 前檢查預算, 預算用完時回傳 ErrBudgetExceeded.
+type BudgetChecker interface {
+	CheckBudget(ctx context.Context) error
+}
+
+// budgetCacheTTL 預算檢查使用的彙總結果快取時間, 期間內記錄的費用直接累加.
+const budgetCacheTTL = 30 * time.Second
+
+// BudgetConfig AI 預算設定, 0 代表不限制.
+type BudgetConfig struct {
+	Daily   float64 // USD
+	Monthly float64 // USD
+}
+
+// NewBudgetConfig 從 viper 讀取 AI 預算設定.
+func NewBudgetConfig() BudgetConfig {
+	return BudgetConfig{
+		Daily:   viper.GetFloat64("AI_BUDGET_DAILY_USD"),
+		Monthly: viper.GetFloat64("AI_BUDGET_MONTHLY_USD"),
+	}
+}
+
+var (
+	_ UsageRecorder = &UsageService{}
+	_ BudgetChecker = &UsageService{}
+)
+
+// UsageService 記錄 AI 使用量與費用, 並計算預算使用狀況.
+type UsageService struct {
+	logger  *zerolog.Logger
+	repo    repository.UsageRepository
+	pricing Pricing
+	budget  BudgetConfig
+	now     func() time.Time
+
+	// 預算檢查用的彙總快取
+	mu         sync.Mutex
+	budgetAt   time.Time
+	budgetSums *dto.UsageTotals
+
+	costCounter metric.Float64Counter
+}
+
+// NewUsageService 以 viper 設定建立 AI 使用量服務.
+func NewUsageService(logger *zerolog.Logger, repo repository.UsageRepository) *UsageService {
+	pricing, err := NewPricing()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to load AI_MODEL_PRICING")
+	}
+
+	return NewUsageServiceWithConfig(logger, repo, otel.Meter("domain/ai"), pricing, NewBudgetConfig())
+}
+
+// NewUsageServiceWithConfig 以指定 meter, 價格表與預算建立 AI 使用量服務.
+func NewUsageServiceWithConfig(
+	logger *zerolog.Logger,
+	repo repository.UsageRepository,
+	meter metric.Meter,
+	pricing Pricing,
+	budget BudgetConfig,
+) *UsageService {
+	s := &UsageService{
+		logger:  logger,
+		repo:    repo,
+		pricing: pricing,
+		budget:  budget,
+		now:     time.Now,
+	}
+
+	var err error
+	if s.costCounter, err = meter.Float64Counter(
+		"ai.cost",
+		metric.WithDescription("Estimated AI cost"),
+		metric.WithUnit("USD"),
+	); err != nil {
+		logger.Fatal().Err(err).Msg("failed to create ai.cost counter")
+	}
+
+	_, err = meter.Float64ObservableGauge(
+		"ai.budget.spent",
+		metric.WithDescription("Estimated AI cost of the current budget period"),
+		metric.WithUnit("USD"),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			totals, err := s.Totals(ctx)
+			if err != nil {
+				return err
+			}
+			o.Observe(totals.Daily.Cost, metric.WithAttributes(attribute.String("period", "daily")))
+			o.Observe(totals.Monthly.Cost, metric.WithAttributes(attribute.String("period", "monthly")))
+			return nil
+		}),
+	)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create ai.budget.spent gauge")
+	}
+
+	_, err = meter.Float64ObservableGauge(
+		"ai.budget.limit",
+		metric.WithDescription("AI budget of the current period, 0 means unlimited"),
+		metric.WithUnit("USD"),
+		metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+			o.Observe(s.budget.Daily, metric.WithAttributes(attribute.String("period", "daily")))
+			o.Observe(s.budget.Monthly, metric.WithAttributes(attribute.String("period", "monthly")))
+			return nil
+		}),
+	)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create ai.budget.limit gauge")
+	}
+
+	return s
+}
+
+// RecordUsage 計算費用並寫入 ai_usage, 失敗只記錄 log.
+func (s *UsageService) RecordUsage(ctx context.Context, usage dto.Usage) {
+	cost := s.pricing.Cost(usage.Model, usage.PromptTokens, usage.CompletionTokens)
+
+	costFloat, _ := cost.Float64()
+	s.costCounter.Add(ctx, costFloat, metric.WithAttributes(
+		attribute.String("model", usage.Model),
+		attribute.String("prompt_version", usage.PromptVersion),
+	))
+
+	err := s.repo.CreateUsage(ctx, &entity.AiUsage{
+		Model:            usage.Model,
+		PromptVersion:    usage.PromptVersion,
+		Operation:        usage.Operation,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		Cost:             cost,
+		CreatedAt:        s.now(),
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Ctx(ctx).Str("model", usage.Model).Msg("failed to record ai usage")
+	}
+
+	s.addBudgetCost(costFloat)
+}
+
+// CheckBudget 預算用完時回傳 ErrBudgetExceeded, 使用量查詢失敗時只記錄 log 不阻擋呼叫.
+func (s *UsageService) CheckBudget(ctx context.Context) error {
+	totals, err := s.BudgetTotals(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Ctx(ctx).Msg("failed to check ai budget")
+		return nil
+	}
+	if totals.Exceeded() {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
+
+// BudgetTotals 與 Totals 相同, 但結果快取 budgetCacheTTL, 避免每次呼叫 AI 都彙總使用量.
+func (s *UsageService) BudgetTotals(ctx context.Context) (*dto.UsageTotals, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	if s.budgetSums != nil && now.Sub(s.budgetAt) < budgetCacheTTL && now.Before(s.budgetSums.Daily.To) {
+		totals := *s.budgetSums
+		s.mu.Unlock()
+		return &totals, nil
+	}
+	s.mu.Unlock()
+
+	totals, err := s.Totals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	cached := *totals
+	s.budgetSums, s.budgetAt = &cached, now
+	s.mu.Unlock()
+
+	return totals, nil
+}
+
+// addBudgetCost 將剛記錄的費用累加至預算快取, 快取過期前也能及時停止呼叫.
+func (s *UsageService) addBudgetCost(cost float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.budgetSums == nil {
+		return
+	}
+	for _, period := range []*dto.UsagePeriod{&s.budgetSums.Daily, &s.budgetSums.Monthly} {
+		period.Cost += cost
+		period.Exceeded = period.Budget > 0 && period.Cost >= period.Budget
+	}
+}
+
+// Totals 回傳當日與當月的使用量與預算狀態.
+func (s *UsageService) Totals(ctx context.Context) (*dto.UsageTotals, error) {
+	now := s.now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	daily, err := s.period(ctx, dayStart, dayStart.AddDate(0, 0, 1), s.budget.Daily)
+	if err != nil {
+		return nil, err
+	}
+
+	monthly, err := s.period(ctx, monthStart, monthStart.AddDate(0, 1, 0), s.budget.Monthly)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UsageTotals{Daily: *daily, Monthly: *monthly}, nil
+}
+
+func (s *UsageService) period(ctx context.Context, from time.Time, to time.Time, budget float64) (*dto.UsagePeriod, error) {
+	summaries, err := s.repo.SumUsage(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum ai usage: %w", err)
+	}
+
+	period := &dto.UsagePeriod{
+		From:   from,
+		To:     to,
+		Budget: budget,
+		Models: make([]dto.ModelUsage, 0, len(summaries)),
+	}
+	for _, summary := range summaries {
+		cost, _ := summary.Cost.Float64()
+		period.Models = append(period.Models, dto.ModelUsage{
+			Model:            summary.Model,
+			Calls:            summary.Calls,
+			PromptTokens:     summary.PromptTokens,
+			CompletionTokens: summary.CompletionTokens,
+			TotalTokens:      summary.TotalTokens,
+			Cost:             cost,
+		})
+		period.Cost += cost
+		period.Tokens += summary.TotalTokens
+		period.Calls += summary.Calls
+	}
+	period.Exceeded = budget > 0 && period.Cost >= budget
+
+	return period, nil
+}
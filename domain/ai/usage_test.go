@@ -0,0 +1,218 @@
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+	"itmrchow/tw-media-analytics-service/domain/ai/entity"
+)
+
+// fakeUsageRepo 以記憶體儲存使用紀錄.
+type fakeUsageRepo struct {
+	usageList []*entity.AiUsage
+	sumCalls  int
+}
+
+func (r *fakeUsageRepo) CreateUsage(_ context.Context, usage *entity.AiUsage) error {
+	r.usageList = append(r.usageList, usage)
+	return nil
+}
+
+func (r *fakeUsageRepo) SumUsage(_ context.Context, from time.Time, to time.Time) ([]*entity.AiUsageSummary, error) {
+	r.sumCalls++
+	m := map[string]*entity.AiUsageSummary{}
+	var summaries []*entity.AiUsageSummary
+	for _, usage := range r.usageList {
+		if usage.CreatedAt.Before(from) || !usage.CreatedAt.Before(to) {
+			continue
+		}
+		summary, ok := m[usage.Model]
+		if !ok {
+			summary = &entity.AiUsageSummary{Model: usage.Model}
+			m[usage.Model] = summary
+			summaries = append(summaries, summary)
+		}
+		summary.Calls++
+		summary.PromptTokens += usage.PromptTokens
+		summary.CompletionTokens += usage.CompletionTokens
+		summary.TotalTokens += usage.TotalTokens
+		summary.Cost = summary.Cost.Add(usage.Cost)
+	}
+	return summaries, nil
+}
+
+// fakeModel 記錄呼叫次數的 AiModel, 設定 budget 時與 Gemini 相同先檢查預算.
+type fakeModel struct {
+	calls  int
+	budget BudgetChecker
+}
+
+func (m *fakeModel) AnalyzeNews(ctx context.Context, _ string, _ string) (*dto.NewsAnalytics, error) {
+	if m.budget != nil {
+		if err := m.budget.CheckBudget(ctx); err != nil {
+			return nil, err
+		}
+	}
+	m.calls++
+	return &dto.NewsAnalytics{}, nil
+}
+
+func (m *fakeModel) Ping(context.Context) error { return nil }
+
+func (m *fakeModel) CloseClient() error { return nil }
+
+func newTestUsageService(repo *fakeUsageRepo, budget BudgetConfig, now time.Time) *UsageService {
+	logger := zerolog.Nop()
+	s := NewUsageServiceWithConfig(&logger, repo, otel.Meter("test"), Pricing{
+		"model-a": {Input: 1, Output: 2},
+	}, budget)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestPricing_Cost(t *testing.T) {
+	pricing := Pricing{"model-a": {Input: 0.075, Output: 0.30}}
+
+	assert.True(t, decimal.RequireFromString("0.000105").Equal(pricing.Cost("model-a", 1000, 100)))
+	assert.True(t, decimal.Zero.Equal(pricing.Cost("unknown", 1000, 100)))
+}
+
+func TestUsageService_Totals(t *testing.T) {
+	now := time.Date(2025, 5, 15, 12, 0, 0, 0, time.UTC)
+	repo := &fakeUsageRepo{}
+	s := newTestUsageService(repo, BudgetConfig{Daily: 1, Monthly: 10}, now)
+
+	// 1M prompt tokens = 1 USD
+	s.RecordUsage(context.Background(), dto.Usage{Model: "model-a", PromptVersion: "v1", PromptTokens: 500_000, TotalTokens: 500_000})
+	require.Len(t, repo.usageList, 1)
+	assert.Equal(t, "v1", repo.usageList[0].PromptVersion)
+	assert.True(t, decimal.RequireFromString("0.5").Equal(repo.usageList[0].Cost))
+
+	// 上個月的紀錄不列入
+	repo.usageList = append(repo.usageList, &entity.AiUsage{
+		Model: "model-a", Cost: decimal.NewFromInt(100), CreatedAt: now.AddDate(0, -1, 0),
+	})
+
+	totals, err := s.Totals(context.Background())
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, totals.Daily.Cost, 0.0001)
+	assert.InDelta(t, 0.5, totals.Monthly.Cost, 0.0001)
+	assert.False(t, totals.Exceeded())
+
+	s.RecordUsage(context.Background(), dto.Usage{Model: "model-a", PromptTokens: 500_000, TotalTokens: 500_000})
+	totals, err = s.Totals(context.Background())
+	require.NoError(t, err)
+	assert.True(t, totals.Daily.Exceeded)
+	assert.False(t, totals.Monthly.Exceeded)
+	assert.Equal(t, int64(2), totals.Daily.Calls)
+}
+
+func TestBudgetedModel_AnalyzeNews(t *testing.T) {
+	now := time.Date(2025, 5, 15, 12, 0, 0, 0, time.UTC)
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name         string
+		spent        int64 // prompt tokens
+		withFallback bool
+		wantErr      error
+		wantPrimary  int
+		wantFallback int
+	}{
+		{name: "預算內使用主要模型", spent: 100_000, wantPrimary: 1},
+		{name: "超過預算暫停", spent: 2_000_000, wantErr: ErrBudgetExceeded},
+		{name: "超過預算改用 fallback", spent: 2_000_000, withFallback: true, wantFallback: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUsageRepo{}
+			usage := newTestUsageService(repo, BudgetConfig{Daily: 1}, now)
+			usage.RecordUsage(context.Background(), dto.Usage{Model: "model-a", PromptTokens: tt.spent})
+
+			primary := &fakeModel{budget: usage}
+			fallback := &fakeModel{}
+			var fallbackModel AiModel
+			if tt.withFallback {
+				fallbackModel = fallback
+			}
+
+			_, err := NewBudgetedModel(&logger, primary, fallbackModel).AnalyzeNews(context.Background(), "標題", "內容")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantPrimary, primary.calls)
+			assert.Equal(t, tt.wantFallback, fallback.calls)
+		})
+	}
+}
+
+// ctxBudget 以呼叫端的 context 檢查預算, 用於確認 context 傳遞至模型.
+type ctxBudget struct{}
+
+func (ctxBudget) CheckBudget(ctx context.Context) error { return ctx.Err() }
+
+func TestBudgetedModel_PassesContext(t *testing.T) {
+	logger := zerolog.Nop()
+	primary := &fakeModel{budget: ctxBudget{}}
+	model := NewChunkedModel(&logger, NewBudgetedModel(&logger, primary, nil), NewChunkConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// 取消的 context 經 chunked 與 budgeted 傳至模型, 不會送出請求
+	_, err := model.AnalyzeNews(ctx, "標題", "內容")
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, primary.calls)
+}
+
+func TestUsageService_CheckBudget(t *testing.T) {
+	now := time.Date(2025, 5, 15, 12, 0, 0, 0, time.UTC)
+	repo := &fakeUsageRepo{}
+	s := newTestUsageService(repo, BudgetConfig{Daily: 1}, now)
+
+	require.NoError(t, s.CheckBudget(context.Background()))
+	require.NoError(t, s.CheckBudget(context.Background()))
+	assert.Equal(t, 2, repo.sumCalls, "totals are cached within the ttl")
+
+	// 快取期間記錄的費用直接累加
+	s.RecordUsage(context.Background(), dto.Usage{Model: "model-a", PromptTokens: 1_000_000})
+	assert.ErrorIs(t, s.CheckBudget(context.Background()), ErrBudgetExceeded)
+	assert.Equal(t, 2, repo.sumCalls)
+
+	// 過期後重新彙總
+	s.now = func() time.Time { return now.Add(budgetCacheTTL) }
+	assert.ErrorIs(t, s.CheckBudget(context.Background()), ErrBudgetExceeded)
+	assert.Equal(t, 4, repo.sumCalls)
+}
+
+func TestBudgetedModel_CacheHitOverBudget(t *testing.T) {
+	now := time.Date(2025, 5, 15, 12, 0, 0, 0, time.UTC)
+	logger := zerolog.Nop()
+	usage := newTestUsageService(&fakeUsageRepo{}, BudgetConfig{Daily: 1}, now)
+
+	primary := &fakeModel{budget: usage}
+	cacheRepo := &fakeCacheRepo{caches: map[string]*entity.AnalysisCache{}}
+	model := NewBudgetedModel(&logger, NewCachedModel(&logger, otel.Meter("test"), cacheRepo, primary, "model-a", "v1"), nil)
+
+	_, err := model.AnalyzeNews(context.Background(), "標題", "內容")
+	require.NoError(t, err)
+
+	// 超過預算後, 已快取的內容仍可取得, 未快取的內容暫停分析
+	usage.RecordUsage(context.Background(), dto.Usage{Model: "model-a", PromptTokens: 2_000_000})
+	_, err = model.AnalyzeNews(context.Background(), "標題", "內容")
+	require.NoError(t, err)
+	_, err = model.AnalyzeNews(context.Background(), "另一則標題", "內容")
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Equal(t, 1, primary.calls)
+}
@@ -0,0 +1,205 @@
+package category
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+)
+
+const (
+	// minKeywordScore 關鍵字分數至少達此值才採用, 標題命中 2 分, 內容命中 1 分.
+	minKeywordScore = 2
+	// defaultLLMTimeout AI 模型分類的逾時, 不受呼叫端儲存新聞的逾時影響.
+	defaultLLMTimeout = 30 * time.Second
+)
+
+// Source 分類來源.
+type Source string
+
+const (
+	SourceMapping Source = "mapping" // 媒體版面對應表
+	SourceAlias   Source = "alias"   // 版面名稱含有分類別名
+	SourceKeyword Source = "keyword" // 標題與內容關鍵字
+	SourceLLM     Source = "llm"     // AI 模型
+	SourceDefault Source = "default" // 無法判斷, 歸類為 other
+)
+
+// Result 分類結果.
+type Result struct {
+	Key    string
+	Source Source
+}
+
+// LLM 以 AI 模型分類, categories 為分類 key 與名稱, 回傳分類 key.
+type LLM interface {
+	ClassifyCategory(ctx context.Context, title string, content string, categories map[string]string) (string, error)
+}
+
+// Classifier 將各媒體的版面名稱對應至統一分類.
+// 依序使用媒體對應表, 版面別名, 關鍵字, 最後才呼叫 AI 模型 (若有設定).
+type Classifier struct {
+	logger     *zerolog.Logger
+	llm        LLM
+	llmTimeout time.Duration
+	taxonomy   Taxonomy
+
+	keys     map[string]bool
+	sections map[uint]map[string]string
+}
+
+// NewClassifier 建立分類器, 設定 CATEGORY_TAXONOMY_FILE 時使用自訂分類, llm 可為 nil.
+func NewClassifier(logger *zerolog.Logger, llm LLM) *Classifier {
+	var taxonomy Taxonomy
+	var err error
+	if path := viper.GetString("CATEGORY_TAXONOMY_FILE"); path != "" {
+		taxonomy, err = LoadTaxonomy(path)
+	} else {
+		taxonomy, err = DefaultTaxonomy()
+	}
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to load category taxonomy")
+	}
+
+	c := NewClassifierWithTaxonomy(logger, taxonomy, llm)
+	if timeout := viper.GetDuration("CATEGORY_LLM_TIMEOUT"); timeout > 0 {
+		c.llmTimeout = timeout
+	}
+
+	return c
+}
+
+// NewClassifierWithTaxonomy 以指定分類建立分類器, llm 可為 nil.
+func NewClassifierWithTaxonomy(logger *zerolog.Logger, taxonomy Taxonomy, llm LLM) *Classifier {
+	c := &Classifier{
+		logger:     logger,
+		llm:        llm,
+		llmTimeout: defaultLLMTimeout,
+		taxonomy:   taxonomy,
+		keys:       map[string]bool{},
+		sections:   map[uint]map[string]string{},
+	}
+
+	for _, category := range taxonomy.Categories {
+		c.keys[category.Key] = true
+	}
+	for _, m := range taxonomy.Media {
+		sections := map[string]string{}
+		for section, key := range m.Sections {
+			sections[normalize(section)] = key
+		}
+		c.sections[m.MediaID] = sections
+	}
+
+	return c
+}
+
+// Taxonomy 回傳使用中的分類.
+func (c *Classifier) Taxonomy() Taxonomy {
+	return c.taxonomy
+}
+
+// Classify 依媒體版面, 標題與內容分類.
+func (c *Classifier) Classify(ctx context.Context, mediaID uint, section string, title string, content string) Result {
+	section = normalize(section)
+
+	if section != "" {
+		if key, ok := c.sections[mediaID][section]; ok {
+			return Result{Key: key, Source: SourceMapping}
+		}
+		if key, ok := c.matchAlias(section); ok {
+			return Result{Key: key, Source: SourceAlias}
+		}
+	}
+
+	if key, ok := c.matchKeywords(title, content); ok {
+		return Result{Key: key, Source: SourceKeyword}
+	}
+
+	if c.llm != nil {
+		key, err := c.classifyByLLM(ctx, title, content)
+		if err == nil {
+			return Result{Key: key, Source: SourceLLM}
+		}
+		c.logger.Error().Err(err).Ctx(ctx).Str("title", title).Msg("failed to classify category by llm")
+	}
+
+	return Result{Key: KeyOther, Source: SourceDefault}
+}
+
+// matchAlias 版面名稱只含有單一分類的別名時採用.
+func (c *Classifier) matchAlias(section string) (string, bool) {
+	var matched string
+	for _, category := range c.taxonomy.Categories {
+		for _, alias := range category.Aliases {
+			if !strings.Contains(section, normalize(alias)) {
+				continue
+			}
+			if matched != "" && matched != category.Key {
+				return "", false
+			}
+			matched = category.Key
+		}
+	}
+
+	return matched, matched != ""
+}
+
+// matchKeywords 計算各分類命中的關鍵字, 最高分需達 minKeywordScore 且不與其他分類同分.
+func (c *Classifier) matchKeywords(title string, content string) (string, bool) {
+	title = normalize(title)
+	content = normalize(content)
+
+	var best string
+	var bestScore, secondScore int
+	for _, category := range c.taxonomy.Categories {
+		score := 0
+		for _, keyword := range category.Keywords {
+			keyword = normalize(keyword)
+			if strings.Contains(title, keyword) {
+				score += 2
+			} else if strings.Contains(content, keyword) {
+				score++
+			}
+		}
+
+		switch {
+		case score > bestScore:
+			best, bestScore, secondScore = category.Key, score, bestScore
+		case score > secondScore:
+			secondScore = score
+		}
+	}
+
+	if bestScore < minKeywordScore || bestScore == secondScore {
+		return "", false
+	}
+	return best, true
+}
+
+func (c *Classifier) classifyByLLM(ctx context.Context, title string, content string) (string, error) {
+	categories := make(map[string]string, len(c.taxonomy.Categories))
+	for _, category := range c.taxonomy.Categories {
+		categories[category.Key] = category.Name
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.llmTimeout)
+	defer cancel()
+
+	key, err := c.llm.ClassifyCategory(ctx, title, content, categories)
+	if err != nil {
+		return "", err
+	}
+	if !c.keys[key] {
+		return "", fmt.Errorf("unknown category %q", key)
+	}
+
+	return key, nil
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,179 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLLM 回傳指定分類, 並記錄呼叫次數.
+type fakeLLM struct {
+	key      string
+	err      error
+	calls    int
+	deadline time.Time
+}
+
+func (m *fakeLLM) ClassifyCategory(ctx context.Context, _ string, _ string, _ map[string]string) (string, error) {
+	m.calls++
+	m.deadline, _ = ctx.Deadline()
+	return m.key, m.err
+}
+
+func newTestClassifier(t *testing.T, llm LLM) *Classifier {
+	t.Helper()
+
+	taxonomy, err := DefaultTaxonomy()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	return NewClassifierWithTaxonomy(&logger, taxonomy, llm)
+}
+
+func TestDefaultTaxonomy(t *testing.T) {
+	taxonomy, err := DefaultTaxonomy()
+	require.NoError(t, err)
+
+	keys := map[string]bool{}
+	for _, c := range taxonomy.Categories {
+		keys[c.Key] = true
+	}
+	assert.True(t, keys["politics"])
+	assert.True(t, keys[KeyOther])
+	require.Len(t, taxonomy.Media, 2)
+}
+
+func TestTaxonomy_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		taxonomy Taxonomy
+		wantErr  string
+	}{
+		{
+			name:     "缺少 other",
+			taxonomy: Taxonomy{Categories: []Category{{Key: "politics"}}},
+			wantErr:  `no "other" category`,
+		},
+		{
+			name:     "重複分類",
+			taxonomy: Taxonomy{Categories: []Category{{Key: "other"}, {Key: "other"}}},
+			wantErr:  "duplicate category",
+		},
+		{
+			name: "對應至未定義的分類",
+			taxonomy: Taxonomy{
+				Categories: []Category{{Key: "other"}},
+				Media:      []MediaMapping{{MediaID: 1, Sections: map[string]string{"政治": "politics"}}},
+			},
+			wantErr: `unknown category "politics"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.taxonomy.validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestClassifier_Classify(t *testing.T) {
+	tests := []struct {
+		name    string
+		mediaID uint
+		section string
+		title   string
+		content string
+		want    Result
+	}{
+		{
+			name:    "中天版面對應",
+			mediaID: 1,
+			section: "政治",
+			want:    Result{Key: "politics", Source: SourceMapping},
+		},
+		{
+			name:    "三立版面對應",
+			mediaID: 2,
+			section: " 政治新聞 ",
+			want:    Result{Key: "politics", Source: SourceMapping},
+		},
+		{
+			name:    "未定義版面以別名判斷",
+			mediaID: 1,
+			section: "財經焦點",
+			want:    Result{Key: "finance", Source: SourceAlias},
+		},
+		{
+			name:    "版面含多個分類別名改以關鍵字判斷",
+			mediaID: 1,
+			section: "國際財經",
+			title:   "台股加權指數大漲 外資買超",
+			want:    Result{Key: "finance", Source: SourceKeyword},
+		},
+		{
+			name:    "缺少版面以關鍵字判斷",
+			mediaID: 2,
+			title:   "颱風接近 氣象署發布海上警報",
+			content: "各地降雨明顯",
+			want:    Result{Key: "life", Source: SourceKeyword},
+		},
+		{
+			name:    "關鍵字大小寫不敏感",
+			title:   "nba 季後賽開打",
+			content: "球隊積極備戰",
+			want:    Result{Key: "sports", Source: SourceKeyword},
+		},
+		{
+			name:    "只有內容命中一個關鍵字不足以判斷",
+			content: "醫師提醒民眾",
+			want:    Result{Key: KeyOther, Source: SourceDefault},
+		},
+	}
+
+	classifier := newTestClassifier(t, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifier.Classify(context.Background(), tt.mediaID, tt.section, tt.title, tt.content)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClassifier_ClassifyByLLM(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("規則無法判斷時使用 AI", func(t *testing.T) {
+		llm := &fakeLLM{key: "technology"}
+		got := newTestClassifier(t, llm).Classify(ctx, 1, "", "新品發表會登場", "")
+		assert.Equal(t, Result{Key: "technology", Source: SourceLLM}, got)
+		assert.Equal(t, 1, llm.calls)
+	})
+
+	t.Run("規則可判斷時不呼叫 AI", func(t *testing.T) {
+		llm := &fakeLLM{key: "technology"}
+		got := newTestClassifier(t, llm).Classify(ctx, 1, "政治", "新品發表會登場", "")
+		assert.Equal(t, Result{Key: "politics", Source: SourceMapping}, got)
+		assert.Zero(t, llm.calls)
+	})
+
+	t.Run("AI 回傳未定義分類", func(t *testing.T) {
+		got := newTestClassifier(t, &fakeLLM{key: "weather"}).Classify(ctx, 1, "", "新品發表會登場", "")
+		assert.Equal(t, Result{Key: KeyOther, Source: SourceDefault}, got)
+	})
+
+	t.Run("AI 分類有自己的逾時", func(t *testing.T) {
+		llm := &fakeLLM{key: "technology"}
+		newTestClassifier(t, llm).Classify(ctx, 1, "", "新品發表會登場", "")
+		assert.WithinDuration(t, time.Now().Add(defaultLLMTimeout), llm.deadline, time.Second)
+	})
+
+	t.Run("AI 失敗", func(t *testing.T) {
+		got := newTestClassifier(t, &fakeLLM{err: errors.New("timeout")}).Classify(ctx, 1, "", "新品發表會登場", "")
+		assert.Equal(t, Result{Key: KeyOther, Source: SourceDefault}, got)
+	})
+}
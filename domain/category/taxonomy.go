@@ -0,0 +1,101 @@
+package category
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+
+	"github.com/spf13/viper"
+)
+
+//go:embed taxonomy.yaml
+var defaultTaxonomy []byte
+
+// KeyOther 無法分類時使用的分類.
+const KeyOther = "other"
+
+// Category 統一分類.
+type Category struct {
+	Key      string   `mapstructure:"key" json:"key"`
+	Name     string   `mapstructure:"name" json:"name"`
+	Aliases  []string `mapstructure:"aliases" json:"aliases,omitempty"`
+	Keywords []string `mapstructure:"keywords" json:"keywords,omitempty"`
+}
+
+// MediaMapping 單一媒體的版面對應表, key 為 articleSection.
+type MediaMapping struct {
+	MediaID  uint              `mapstructure:"media_id" json:"mediaId"`
+	Sections map[string]string `mapstructure:"sections" json:"sections"`
+}
+
+// Taxonomy 統一分類與各媒體的版面對應.
+type Taxonomy struct {
+	Categories []Category     `mapstructure:"categories" json:"categories"`
+	Media      []MediaMapping `mapstructure:"media" json:"media"`
+}
+
+// DefaultTaxonomy 內建分類.
+func DefaultTaxonomy() (Taxonomy, error) {
+	return readTaxonomy(bytes.NewReader(defaultTaxonomy), "yaml")
+}
+
+// LoadTaxonomy 讀取分類檔, 格式同 taxonomy.yaml, 支援 viper 可讀取的格式.
+func LoadTaxonomy(path string) (Taxonomy, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return Taxonomy{}, fmt.Errorf("failed to read taxonomy %s: %w", path, err)
+	}
+
+	return unmarshalTaxonomy(v)
+}
+
+func readTaxonomy(r io.Reader, configType string) (Taxonomy, error) {
+	v := viper.New()
+	v.SetConfigType(configType)
+	if err := v.ReadConfig(r); err != nil {
+		return Taxonomy{}, fmt.Errorf("failed to read taxonomy: %w", err)
+	}
+
+	return unmarshalTaxonomy(v)
+}
+
+func unmarshalTaxonomy(v *viper.Viper) (Taxonomy, error) {
+	var taxonomy Taxonomy
+	if err := v.Unmarshal(&taxonomy); err != nil {
+		return Taxonomy{}, fmt.Errorf("failed to unmarshal taxonomy: %w", err)
+	}
+	if err := taxonomy.validate(); err != nil {
+		return Taxonomy{}, err
+	}
+
+	return taxonomy, nil
+}
+
+// validate 檢查分類 key 不重複, 含有 other, 且對應表只指向已定義的分類.
+func (t Taxonomy) validate() error {
+	keys := map[string]bool{}
+	for _, c := range t.Categories {
+		if c.Key == "" {
+			return fmt.Errorf("taxonomy has category without key")
+		}
+		if keys[c.Key] {
+			return fmt.Errorf("taxonomy has duplicate category %q", c.Key)
+		}
+		keys[c.Key] = true
+	}
+	if !keys[KeyOther] {
+		return fmt.Errorf("taxonomy has no %q category", KeyOther)
+	}
+
+	for _, m := range t.Media {
+		for section, key := range m.Sections {
+			if !keys[key] {
+				return fmt.Errorf("media %d section %q: unknown category %q", m.MediaID, section, key)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package clickbait
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+)
+
+// MaxScore 指標滿分, 與 LLM 評分一致.
+const MaxScore = 5.0
+
+// 規則對應的 LLM 標題指標, 準確性與相關性需比對內容, 規則無法判斷.
+const (
+	MetricClarity        = "clarity"
+	MetricObjectivity    = "objectivity"
+	MetricAttractiveness = "attractiveness"
+)
+
+var metricKeys = []string{MetricClarity, MetricObjectivity, MetricAttractiveness}
+
+// Metric 單一指標的規則評分.
+type Metric struct {
+	MetricKey string
+	Score     float64
+	Evidence  []string // 命中的詞彙或標點
+}
+
+// Result 標題規則評分結果.
+type Result struct {
+	Score      float64
+	MetricList []Metric
+	Evidence   []string
+}
+
+type compiledRule struct {
+	Rule
+	patterns []*regexp.Regexp
+}
+
+// Analyzer 以詞庫與標點, 句型規則為標題評分, 不需呼叫 AI, 結果可解釋且固定.
+type Analyzer struct {
+	rules []compiledRule
+}
+
+// NewAnalyzer 建立標題分析器, 設定 CLICKBAIT_LEXICON_FILE 時使用自訂詞庫.
+func NewAnalyzer(logger *zerolog.Logger) *Analyzer {
+	var lexicon Lexicon
+	var err error
+	if path := viper.GetString("CLICKBAIT_LEXICON_FILE"); path != "" {
+		lexicon, err = LoadLexicon(path)
+	} else {
+		lexicon, err = DefaultLexicon()
+	}
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to load clickbait lexicon")
+	}
+
+	analyzer, err := NewAnalyzerWithLexicon(lexicon)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create clickbait analyzer")
+	}
+
+	return analyzer
+}
+
+// NewAnalyzerWithLexicon 以指定詞庫建立標題分析器.
+func NewAnalyzerWithLexicon(lexicon Lexicon) (*Analyzer, error) {
+	a := &Analyzer{}
+	for _, rule := range lexicon.Rules {
+		if !isMetricKey(rule.Metric) {
+			return nil, fmt.Errorf("rule %s: unknown metric %q", rule.Name, rule.Metric)
+		}
+
+		compiled := compiledRule{Rule: rule}
+		for _, pattern := range rule.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: invalid pattern %q: %w", rule.Name, pattern, err)
+			}
+			compiled.patterns = append(compiled.patterns, re)
+		}
+		a.rules = append(a.rules, compiled)
+	}
+
+	return a, nil
+}
+
+// Analyze 為標題評分: 各指標從滿分開始, 每個命中的證據扣該規則的 weight, 總分為指標平均.
+func (a *Analyzer) Analyze(title string) Result {
+	scores := map[string]float64{}
+	evidence := map[string][]string{}
+	for _, key := range metricKeys {
+		scores[key] = MaxScore
+	}
+
+	for _, rule := range a.rules {
+		matched := rule.match(title)
+		scores[rule.Metric] -= rule.Weight * float64(len(matched))
+		evidence[rule.Metric] = appendUnique(evidence[rule.Metric], matched...)
+	}
+
+	var result Result
+	var total float64
+	for _, key := range metricKeys {
+		score := roundScore(math.Max(0, scores[key]))
+		total += score
+		result.MetricList = append(result.MetricList, Metric{
+			MetricKey: key,
+			Score:     score,
+			Evidence:  evidence[key],
+		})
+		result.Evidence = appendUnique(result.Evidence, evidence[key]...)
+	}
+	result.Score = roundScore(total / float64(len(metricKeys)))
+
+	return result
+}
+
+// match 回傳命中的證據, 詞彙若被同規則內較長的命中詞彙包含則不重複計算 (例如 曝光 / 曝).
+func (r compiledRule) match(title string) []string {
+	var terms []string
+	for _, term := range r.Terms {
+		if term != "" && strings.Contains(title, term) {
+			terms = append(terms, term)
+		}
+	}
+
+	var matched []string
+	for _, term := range terms {
+		if !containedByOther(term, terms) {
+			matched = appendUnique(matched, term)
+		}
+	}
+
+	for _, re := range r.patterns {
+		matched = appendUnique(matched, re.FindAllString(title, -1)...)
+	}
+
+	return matched
+}
+
+// Reason 將證據整理成可讀的評語.
+func (m Metric) Reason() string {
+	if len(m.Evidence) == 0 {
+		return "未命中規則"
+	}
+	return "命中: " + strings.Join(m.Evidence, ", ")
+}
+
+// Reason 將證據整理成可讀的評語.
+func (r Result) Reason() string {
+	if len(r.Evidence) == 0 {
+		return "未命中規則"
+	}
+	return "命中: " + strings.Join(r.Evidence, ", ")
+}
+
+func containedByOther(term string, terms []string) bool {
+	for _, other := range terms {
+		if other != term && strings.Contains(other, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		exists := false
+		for _, s := range list {
+			if s == item {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func isMetricKey(key string) bool {
+	for _, k := range metricKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*10) / 10
+}
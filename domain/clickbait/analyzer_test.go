@@ -0,0 +1,127 @@
+package clickbait
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAnalyzer(t *testing.T) *Analyzer {
+	t.Helper()
+
+	lexicon, err := DefaultLexicon()
+	require.NoError(t, err)
+
+	analyzer, err := NewAnalyzerWithLexicon(lexicon)
+	require.NoError(t, err)
+
+	return analyzer
+}
+
+func TestAnalyzer_Analyze(t *testing.T) {
+	analyzer := newTestAnalyzer(t)
+
+	tests := []struct {
+		name         string
+		title        string
+		wantScore    float64
+		wantMetrics  map[string]float64
+		wantEvidence []string
+	}{
+		{
+			name:        "一般標題",
+			title:       "行政院通過明年度總預算案",
+			wantScore:   5.0,
+			wantMetrics: map[string]float64{MetricClarity: 5, MetricObjectivity: 5, MetricAttractiveness: 5},
+		},
+		{
+			name:         "聳動標題",
+			title:        "震驚！史上最強颱風竟然轉向…",
+			wantScore:    3.2,
+			wantMetrics:  map[string]float64{MetricClarity: 5, MetricObjectivity: 1.5, MetricAttractiveness: 3},
+			wantEvidence: []string{"震驚", "史上最", "最強", "！", "竟然", "…"},
+		},
+		{
+			name:         "曝光只計一次",
+			title:        "藝人私生活曝光",
+			wantScore:    4.7,
+			wantMetrics:  map[string]float64{MetricClarity: 5, MetricObjectivity: 5, MetricAttractiveness: 4},
+			wantEvidence: []string{"曝光"},
+		},
+		{
+			name:         "懸念問句",
+			title:        "他到底是誰？",
+			wantMetrics:  map[string]float64{MetricClarity: 5, MetricObjectivity: 5, MetricAttractiveness: 3.4},
+			wantScore:    4.5,
+			wantEvidence: []string{"？", "是誰？"},
+		},
+		{
+			name:         "絕對與重複標點",
+			title:        "這一招絕對有效！！",
+			wantMetrics:  map[string]float64{MetricClarity: 3.2, MetricObjectivity: 4.5, MetricAttractiveness: 4.5},
+			wantScore:    4.1,
+			wantEvidence: []string{"絕對", "這一招", "！", "！！"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyzer.Analyze(tt.title)
+
+			assert.Equal(t, tt.wantScore, result.Score)
+			require.Len(t, result.MetricList, len(metricKeys))
+			for _, metric := range result.MetricList {
+				assert.Equal(t, tt.wantMetrics[metric.MetricKey], metric.Score, metric.MetricKey)
+			}
+			assert.ElementsMatch(t, tt.wantEvidence, result.Evidence)
+		})
+	}
+}
+
+func TestResult_Reason(t *testing.T) {
+	analyzer := newTestAnalyzer(t)
+
+	assert.Equal(t, "未命中規則", analyzer.Analyze("立法院三讀通過法案").Reason())
+	assert.Equal(t, "命中: 震驚", analyzer.Analyze("震驚各界").Reason())
+}
+
+func TestLoadLexicon(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lexicon.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+rules:
+  - name: custom
+    metric: attractiveness
+    weight: 2
+    terms: [必看]
+`), 0o644))
+
+	lexicon, err := LoadLexicon(path)
+	require.NoError(t, err)
+
+	analyzer, err := NewAnalyzerWithLexicon(lexicon)
+	require.NoError(t, err)
+
+	result := analyzer.Analyze("今晚必看")
+	assert.Equal(t, []string{"必看"}, result.Evidence)
+	assert.Equal(t, 4.3, result.Score)
+}
+
+func TestNewAnalyzerWithLexicon_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+	}{
+		{name: "未知指標", rule: Rule{Name: "r", Metric: "accuracy", Terms: []string{"a"}}},
+		{name: "錯誤的正規表示式", rule: Rule{Name: "r", Metric: MetricClarity, Patterns: []string{"("}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAnalyzerWithLexicon(Lexicon{Rules: []Rule{tt.rule}})
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package clickbait
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+
+	"github.com/spf13/viper"
+)
+
+//go:embed lexicon.yaml
+var defaultLexicon []byte
+
+// Rule 單一規則, 命中 terms 或 patterns 即扣 weight 分.
+type Rule struct {
+	Name     string   `mapstructure:"name"`
+	Metric   string   `mapstructure:"metric"`
+	Weight   float64  `mapstructure:"weight"`
+	Terms    []string `mapstructure:"terms"`
+	Patterns []string `mapstructure:"patterns"`
+}
+
+// Lexicon 標題規則詞庫.
+type Lexicon struct {
+	Rules []Rule `mapstructure:"rules"`
+}
+
+// DefaultLexicon 內建詞庫, 依 promt.md 的標題指標整理.
+func DefaultLexicon() (Lexicon, error) {
+	return readLexicon(bytes.NewReader(defaultLexicon), "yaml")
+}
+
+// LoadLexicon 讀取詞庫檔, 格式同 lexicon.yaml, 支援 viper 可讀取的格式.
+func LoadLexicon(path string) (Lexicon, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return Lexicon{}, fmt.Errorf("failed to read lexicon %s: %w", path, err)
+	}
+
+	return unmarshalLexicon(v)
+}
+
+func readLexicon(r io.Reader, configType string) (Lexicon, error) {
+	v := viper.New()
+	v.SetConfigType(configType)
+	if err := v.ReadConfig(r); err != nil {
+		return Lexicon{}, fmt.Errorf("failed to read lexicon: %w", err)
+	}
+
+	return unmarshalLexicon(v)
+}
+
+func unmarshalLexicon(v *viper.Viper) (Lexicon, error) {
+	var lexicon Lexicon
+	if err := v.Unmarshal(&lexicon); err != nil {
+		return Lexicon{}, fmt.Errorf("failed to unmarshal lexicon: %w", err)
+	}
+	if len(lexicon.Rules) == 0 {
+		return Lexicon{}, fmt.Errorf("lexicon has no rules")
+	}
+
+	return lexicon, nil
+}
@@ -3,6 +3,12 @@ package cronjob
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
@@ -12,12 +18,18 @@ import (
 
 	"itmrchow/tw-media-analytics-service/domain/queue"
 	"itmrchow/tw-media-analytics-service/domain/utils"
+	"itmrchow/tw-media-analytics-service/domain/utils/health"
 )
 
 type CronJob struct {
 	tracer    trace.Tracer
 	logger    *zerolog.Logger
 	publisher message.Publisher
+
+	// scheduler
+	mu        sync.RWMutex
+	scheduler *cron.Cron
+	entries   map[string]cron.EntryID
 }
 
 func NewCronJob(logger *zerolog.Logger, tracer trace.Tracer, publisher message.Publisher) *CronJob {
@@ -80,6 +92,58 @@ func (c *CronJob) AnalyzeNewsJob() {
 	}
 }
 
+// EmbedNewsJob 觸發計算新聞向量 pub.
+func (c *CronJob) EmbedNewsJob() {
+	// create new context
+	ctx := context.Background()
+
+	// Tracer
+	ctx, span := c.tracer.Start(ctx, "domain/cronjob/cronjob/EmbedNewsJob:Embed News Job")
+	c.logger.Info().Ctx(ctx).Msg("EmbedNewsJob: start")
+	defer func() {
+		c.logger.Info().Ctx(ctx).Msg("EmbedNewsJob: end")
+		span.End()
+	}()
+
+	// publish
+	payload, err := json.Marshal(utils.EventNewsEmbedding{
+		BatchSize: 20, // 單次 batch 請求的新聞數
+	})
+	if err != nil {
+		c.logger.Error().Err(err).Ctx(ctx).Msg("EmbedNewsJob Marshal Error")
+		return
+	}
+	msg := message.NewMessage(watermill.NewUUID(), payload)
+	if err = c.publisher.Publish(string(queue.TopicNewsEmbedding), msg); err != nil {
+		c.logger.Error().Err(err).Ctx(ctx).Msg("EmbedNewsJob Publish Error")
+	}
+}
+
+// StoryClusteringJob 觸發跨媒體事件分群 pub.
+func (c *CronJob) StoryClusteringJob() {
+	// create new context
+	ctx := context.Background()
+
+	// Tracer
+	ctx, span := c.tracer.Start(ctx, "domain/cronjob/cronjob/StoryClusteringJob:Story Clustering Job")
+	c.logger.Info().Ctx(ctx).Msg("StoryClusteringJob: start")
+	defer func() {
+		c.logger.Info().Ctx(ctx).Msg("StoryClusteringJob: end")
+		span.End()
+	}()
+
+	// publish
+	payload, err := json.Marshal(utils.EventStoryClustering{})
+	if err != nil {
+		c.logger.Error().Err(err).Ctx(ctx).Msg("StoryClusteringJob Marshal Error")
+		return
+	}
+	msg := message.NewMessage(watermill.NewUUID(), payload)
+	if err = c.publisher.Publish(string(queue.TopicStoryClustering), msg); err != nil {
+		c.logger.Error().Err(err).Ctx(ctx).Msg("StoryClusteringJob Publish Error")
+	}
+}
+
 // InitCronJob 初始化 cron job.
 func InitCronJob(ctx context.Context, logger *zerolog.Logger, tracer trace.Tracer, cronJob *CronJob) {
 	// Tracer
@@ -91,17 +155,81 @@ func InitCronJob(ctx context.Context, logger *zerolog.Logger, tracer trace.Trace
 	}()
 
 	cr := cron.New()
+	entries := make(map[string]cron.EntryID)
+
 	// ArticleScrapingJob
-	_, err := cr.AddFunc("0 * * * *", cronJob.ArticleScrapingJob)
+	id, err := cr.AddFunc("0 * * * *", cronJob.ArticleScrapingJob)
 	if err != nil {
 		logger.Fatal().Err(err).Ctx(ctx).Str("job", "ArticleScrapingJob").Msg("failed to add cron job")
 	}
+	entries["ArticleScrapingJob"] = id
 
 	// AnalyzeNewsJob
-	_, err = cr.AddFunc("*/1 * * * *", cronJob.AnalyzeNewsJob)
+	id, err = cr.AddFunc("*/1 * * * *", cronJob.AnalyzeNewsJob)
 	if err != nil {
 		logger.Fatal().Err(err).Ctx(ctx).Str("job", "AnalyzeNewsJob").Msg("failed to add cron job")
 	}
+	entries["AnalyzeNewsJob"] = id
+
+	// EmbedNewsJob
+	id, err = cr.AddFunc("*/5 * * * *", cronJob.EmbedNewsJob)
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Str("job", "EmbedNewsJob").Msg("failed to add cron job")
+	}
+	entries["EmbedNewsJob"] = id
+
+	// StoryClusteringJob
+	id, err = cr.AddFunc("*/15 * * * *", cronJob.StoryClusteringJob)
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Str("job", "StoryClusteringJob").Msg("failed to add cron job")
+	}
+	entries["StoryClusteringJob"] = id
 
 	cr.Start()
+
+	cronJob.mu.Lock()
+	cronJob.scheduler = cr
+	cronJob.entries = entries
+	cronJob.mu.Unlock()
+}
+
+// JobStatus cron job 排程狀態.
+type JobStatus struct {
+	Prev time.Time `json:"prev"`
+	Next time.Time `json:"next"`
+}
+
+// HealthCheck cron 排程健康檢查.
+// 排程停止後 Next 不會再更新, 因此 Next 落後現在超過 1 分鐘即視為停止.
+func (c *CronJob) HealthCheck() health.Check {
+	return health.Check{
+		Name:      "cron",
+		Liveness:  true,
+		Readiness: true,
+		Func: func(_ context.Context) (any, error) {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+
+			if c.scheduler == nil {
+				return nil, errors.New("cron scheduler not started")
+			}
+
+			now := time.Now()
+			jobs := make(map[string]JobStatus, len(c.entries))
+			var stalled []string
+			for name, id := range c.entries {
+				entry := c.scheduler.Entry(id)
+				jobs[name] = JobStatus{Prev: entry.Prev, Next: entry.Next}
+				if entry.Next.IsZero() || entry.Next.Before(now.Add(-time.Minute)) {
+					stalled = append(stalled, name)
+				}
+			}
+			if len(stalled) > 0 {
+				sort.Strings(stalled)
+				return jobs, fmt.Errorf("cron job stalled: %s", strings.Join(stalled, ","))
+			}
+
+			return jobs, nil
+		},
+	}
 }
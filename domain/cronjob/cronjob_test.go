@@ -97,3 +97,50 @@ func (s *CronJobTestSuite) TestAnalyzeNewsJob() {
 	// expect
 	s.cronJob.AnalyzeNewsJob()
 }
+
+func (s *CronJobTestSuite) TestEmbedNewsJob() {
+	// mock
+	s.mockPublisher.EXPECT().
+		Publish("news_embedding", mock.MatchedBy(func(messages []*message.Message) bool {
+			var event utils.EventNewsEmbedding
+			if len(messages) == 0 || json.Unmarshal(messages[0].Payload, &event) != nil {
+				return false
+			}
+			return event.BatchSize == 20
+		})).
+		Return(nil).
+		Once()
+
+	// expect
+	s.cronJob.EmbedNewsJob()
+}
+
+func (s *CronJobTestSuite) TestStoryClusteringJob() {
+	// mock
+	s.mockPublisher.EXPECT().
+		Publish("story_clustering", mock.Anything).
+		Return(nil).
+		Once()
+
+	// expect
+	s.cronJob.StoryClusteringJob()
+}
+
+func (s *CronJobTestSuite) TestHealthCheck() {
+	check := s.cronJob.HealthCheck()
+
+	// 尚未啟動
+	_, err := check.Func(context.Background())
+	s.Error(err)
+
+	// 啟動後
+	InitCronJob(context.Background(), s.logger, s.tracer, s.cronJob)
+	defer s.cronJob.scheduler.Stop()
+
+	details, err := check.Func(context.Background())
+	s.NoError(err)
+	s.Contains(details, "ArticleScrapingJob")
+	s.Contains(details, "AnalyzeNewsJob")
+	s.Contains(details, "EmbedNewsJob")
+	s.Contains(details, "StoryClusteringJob")
+}
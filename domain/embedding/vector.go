@@ -0,0 +1,71 @@
+package embedding
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Encode 將向量編碼為 little-endian float32, 儲存於 DB.
+func Encode(vector []float32) []byte {
+	b := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(v))
+	}
+	return b
+}
+
+// Decode 解碼 Encode 的結果.
+func Decode(b []byte) ([]float32, error) {
+	if len(b)%4 != 0 {
+		return nil, fmt.Errorf("invalid vector length %d", len(b))
+	}
+	vector := make([]float32, len(b)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return vector, nil
+}
+
+// Cosine 餘弦相似度, 維度不同或任一為零向量時回傳 0.
+func Cosine(a []float32, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// Scored 搜尋結果與相似度.
+type Scored[T any] struct {
+	Item  T
+	Score float64
+}
+
+// TopK 以暴力搜尋找出與 query 最相似的 k 筆, 依相似度由高到低排序, 相同時保留原順序.
+// 數萬筆以內的向量逐筆計算即足夠快, 不需建立近似索引.
+func TopK[T any](query []float32, items []T, vector func(T) []float32, k int) []Scored[T] {
+	scored := make([]Scored[T], 0, len(items))
+	for _, item := range items {
+		scored = append(scored, Scored[T]{Item: item, Score: Cosine(query, vector(item))})
+	}
+
+	slices.SortStableFunc(scored, func(a, b Scored[T]) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	if len(scored) > k {
+		scored = scored[:k]
+	}
+	return scored
+}
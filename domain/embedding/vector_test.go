@@ -0,0 +1,50 @@
+package embedding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	vector := []float32{0.5, -1.25, 0, 3.4028235e+38}
+
+	decoded, err := Decode(Encode(vector))
+	require.NoError(t, err)
+	assert.Equal(t, vector, decoded)
+
+	_, err = Decode([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestCosine(t *testing.T) {
+	assert.InDelta(t, 1.0, Cosine([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	assert.InDelta(t, 0.0, Cosine([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	assert.InDelta(t, -1.0, Cosine([]float32{1, 0}, []float32{-1, 0}), 1e-9)
+
+	// 維度不同或零向量
+	assert.Zero(t, Cosine([]float32{1, 0}, []float32{1, 0, 0}))
+	assert.Zero(t, Cosine([]float32{0, 0}, []float32{1, 0}))
+}
+
+func TestTopK(t *testing.T) {
+	type doc struct {
+		id     string
+		vector []float32
+	}
+	docs := []doc{
+		{id: "orthogonal", vector: []float32{0, 1}},
+		{id: "same", vector: []float32{2, 0}},
+		{id: "close", vector: []float32{1, 0.2}},
+		{id: "opposite", vector: []float32{-1, 0}},
+	}
+
+	result := TopK([]float32{1, 0}, docs, func(d doc) []float32 { return d.vector }, 2)
+	require.Len(t, result, 2)
+	assert.Equal(t, "same", result[0].Item.id)
+	assert.InDelta(t, 1.0, result[0].Score, 1e-9)
+	assert.Equal(t, "close", result[1].Item.id)
+
+	assert.Len(t, TopK([]float32{1, 0}, docs, func(d doc) []float32 { return d.vector }, 10), 4)
+}
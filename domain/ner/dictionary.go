@@ -0,0 +1,110 @@
+package ner
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/spf13/viper"
+)
+
+//go:embed dictionary.yaml
+var defaultDictionary []byte
+
+// 實體類型.
+const (
+	TypePerson       = "person"
+	TypeParty        = "party"
+	TypeOrganization = "organization"
+	TypeCompany      = "company"
+)
+
+var entityTypes = []string{TypePerson, TypeParty, TypeOrganization, TypeCompany}
+
+// Entity 具名實體, ID 為正規化後的識別碼.
+type Entity struct {
+	ID      string   `mapstructure:"id" json:"id"`
+	Name    string   `mapstructure:"name" json:"name"`
+	Type    string   `mapstructure:"type" json:"type"`
+	Aliases []string `mapstructure:"aliases" json:"aliases"`
+}
+
+// Dictionary 具名實體詞典.
+type Dictionary struct {
+	Entities []Entity `mapstructure:"entities" json:"entities"`
+}
+
+// DefaultDictionary 內建詞典.
+func DefaultDictionary() (Dictionary, error) {
+	return readDictionary(bytes.NewReader(defaultDictionary), "yaml")
+}
+
+// LoadDictionary 讀取詞典檔, 格式同 dictionary.yaml, 支援 viper 可讀取的格式.
+func LoadDictionary(path string) (Dictionary, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return Dictionary{}, fmt.Errorf("failed to read dictionary %s: %w", path, err)
+	}
+
+	return unmarshalDictionary(v)
+}
+
+func readDictionary(r io.Reader, configType string) (Dictionary, error) {
+	v := viper.New()
+	v.SetConfigType(configType)
+	if err := v.ReadConfig(r); err != nil {
+		return Dictionary{}, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+
+	return unmarshalDictionary(v)
+}
+
+func unmarshalDictionary(v *viper.Viper) (Dictionary, error) {
+	var dictionary Dictionary
+	if err := v.Unmarshal(&dictionary); err != nil {
+		return Dictionary{}, fmt.Errorf("failed to unmarshal dictionary: %w", err)
+	}
+	if err := dictionary.validate(); err != nil {
+		return Dictionary{}, err
+	}
+
+	return dictionary, nil
+}
+
+// validate 檢查 ID 不重複, 類型正確, 且同一寫法不屬於多個實體.
+func (d Dictionary) validate() error {
+	if len(d.Entities) == 0 {
+		return fmt.Errorf("dictionary has no entities")
+	}
+
+	ids := map[string]bool{}
+	aliases := map[string]string{}
+	for _, e := range d.Entities {
+		if e.ID == "" || e.Name == "" {
+			return fmt.Errorf("dictionary has entity without id or name")
+		}
+		if ids[e.ID] {
+			return fmt.Errorf("dictionary has duplicate entity %q", e.ID)
+		}
+		ids[e.ID] = true
+
+		if !slices.Contains(entityTypes, e.Type) {
+			return fmt.Errorf("entity %s: unknown type %q", e.ID, e.Type)
+		}
+		if len(e.Aliases) == 0 {
+			return fmt.Errorf("entity %s: no aliases", e.ID)
+		}
+		for _, alias := range e.Aliases {
+			key := normalize(alias)
+			if other, ok := aliases[key]; ok && other != e.ID {
+				return fmt.Errorf("alias %q belongs to both %s and %s", alias, other, e.ID)
+			}
+			aliases[key] = e.ID
+		}
+	}
+
+	return nil
+}
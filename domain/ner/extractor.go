@@ -0,0 +1,169 @@
+package ner
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+)
+
+// Mention 單篇新聞提及的實體.
+type Mention struct {
+	EntityID   string
+	EntityName string
+	EntityType string
+	Count      int      // 標題與內容合計提及次數
+	InTitle    bool     // 標題是否提及
+	Aliases    []string // 新聞中出現的寫法, 依首次出現順序
+}
+
+type alias struct {
+	text   string // 正規化後的寫法
+	entity *Entity
+}
+
+type match struct {
+	start, end int
+	alias      alias
+	surface    string // 原文寫法
+}
+
+// Extractor 以詞典比對繁體中文新聞中的人物, 政黨, 機關與企業, 不需呼叫 AI.
+type Extractor struct {
+	dictionary Dictionary
+	aliases    []alias
+}
+
+// NewExtractor 建立實體擷取器, 設定 NER_DICTIONARY_FILE 時使用自訂詞典.
+func NewExtractor(logger *zerolog.Logger) *Extractor {
+	var dictionary Dictionary
+	var err error
+	if path := viper.GetString("NER_DICTIONARY_FILE"); path != "" {
+		dictionary, err = LoadDictionary(path)
+	} else {
+		dictionary, err = DefaultDictionary()
+	}
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to load ner dictionary")
+	}
+
+	return NewExtractorWithDictionary(dictionary)
+}
+
+// NewExtractorWithDictionary 以指定詞典建立實體擷取器.
+func NewExtractorWithDictionary(dictionary Dictionary) *Extractor {
+	x := &Extractor{dictionary: dictionary}
+	for i := range dictionary.Entities {
+		e := &x.dictionary.Entities[i]
+		for _, a := range e.Aliases {
+			x.aliases = append(x.aliases, alias{text: normalize(a), entity: e})
+		}
+	}
+
+	return x
+}
+
+// Dictionary 回傳使用中的詞典.
+func (x *Extractor) Dictionary() Dictionary {
+	return x.dictionary
+}
+
+// Extract 擷取標題與內容提及的實體, 依首次出現順序 (標題優先) 回傳.
+func (x *Extractor) Extract(title string, content string) []Mention {
+	var mentions []*Mention
+	byID := map[string]*Mention{}
+
+	add := func(text string, inTitle bool) {
+		for _, m := range x.matches(text) {
+			mention, ok := byID[m.alias.entity.ID]
+			if !ok {
+				mention = &Mention{
+					EntityID:   m.alias.entity.ID,
+					EntityName: m.alias.entity.Name,
+					EntityType: m.alias.entity.Type,
+				}
+				byID[mention.EntityID] = mention
+				mentions = append(mentions, mention)
+			}
+			mention.Count++
+			mention.InTitle = mention.InTitle || inTitle
+			if !slices.Contains(mention.Aliases, m.surface) {
+				mention.Aliases = append(mention.Aliases, m.surface)
+			}
+		}
+	}
+	add(title, true)
+	add(content, false)
+
+	result := make([]Mention, 0, len(mentions))
+	for _, m := range mentions {
+		result = append(result, *m)
+	}
+	return result
+}
+
+// matches 找出所有寫法的位置, 重疊時保留最先出現且最長的寫法.
+func (x *Extractor) matches(text string) []match {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// 轉小寫後長度改變 (少數 Unicode 字元), 改以原文比對避免位置錯置
+		lower = text
+	}
+
+	var all []match
+	for _, a := range x.aliases {
+		for offset := 0; ; {
+			i := strings.Index(lower[offset:], a.text)
+			if i < 0 {
+				break
+			}
+			start := offset + i
+			end := start + len(a.text)
+			offset = end
+			// 英文寫法需為完整單字, 避免 CPTPP 被當成 TPP
+			if !wordBoundary(lower, start, end) {
+				continue
+			}
+			all = append(all, match{start: start, end: end, alias: a, surface: text[start:end]})
+		}
+	}
+
+	slices.SortFunc(all, func(a, b match) int {
+		if c := cmp.Compare(a.start, b.start); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.end, a.end)
+	})
+
+	var result []match
+	end := 0
+	for _, m := range all {
+		if m.start < end {
+			continue
+		}
+		result = append(result, m)
+		end = m.end
+	}
+	return result
+}
+
+// wordBoundary 寫法頭尾為英數字時, 前後不可緊接英數字.
+func wordBoundary(text string, start int, end int) bool {
+	if isASCIIWord(text[start]) && start > 0 && isASCIIWord(text[start-1]) {
+		return false
+	}
+	if isASCIIWord(text[end-1]) && end < len(text) && isASCIIWord(text[end]) {
+		return false
+	}
+	return true
+}
+
+func isASCIIWord(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
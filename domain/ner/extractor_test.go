@@ -0,0 +1,89 @@
+package ner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExtractor(t *testing.T) *Extractor {
+	t.Helper()
+
+	dictionary, err := DefaultDictionary()
+	require.NoError(t, err)
+
+	return NewExtractorWithDictionary(dictionary)
+}
+
+func TestDictionary_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		dictionary Dictionary
+		wantErr    string
+	}{
+		{
+			name:       "空詞典",
+			dictionary: Dictionary{},
+			wantErr:    "no entities",
+		},
+		{
+			name: "未知類型",
+			dictionary: Dictionary{Entities: []Entity{
+				{ID: "a", Name: "甲", Type: "country", Aliases: []string{"甲"}},
+			}},
+			wantErr: `unknown type "country"`,
+		},
+		{
+			name: "同一寫法屬於多個實體",
+			dictionary: Dictionary{Entities: []Entity{
+				{ID: "a", Name: "甲", Type: TypePerson, Aliases: []string{"阿明"}},
+				{ID: "b", Name: "乙", Type: TypePerson, Aliases: []string{"阿明"}},
+			}},
+			wantErr: `alias "阿明" belongs to both a and b`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.dictionary.validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestExtractor_Extract(t *testing.T) {
+	x := newTestExtractor(t)
+
+	t.Run("標題與內容合併計算", func(t *testing.T) {
+		got := x.Extract(
+			"賴清德出席活動 國民黨批評",
+			"總統賴清德今日表示, 中國國民黨與民眾黨立委在立法院提案. 賴總統強調…",
+		)
+
+		assert.Equal(t, []Mention{
+			{EntityID: "lai-ching-te", EntityName: "賴清德", EntityType: TypePerson, Count: 3, InTitle: true,
+				Aliases: []string{"賴清德", "總統賴清德", "賴總統"}},
+			{EntityID: "kmt", EntityName: "國民黨", EntityType: TypeParty, Count: 2, InTitle: true,
+				Aliases: []string{"國民黨", "中國國民黨"}},
+			{EntityID: "tpp", EntityName: "民眾黨", EntityType: TypeParty, Count: 1,
+				Aliases: []string{"民眾黨"}},
+			{EntityID: "legislative-yuan", EntityName: "立法院", EntityType: TypeOrganization, Count: 1,
+				Aliases: []string{"立法院"}},
+		}, got)
+	})
+
+	t.Run("英文寫法不分大小寫且需為完整單字", func(t *testing.T) {
+		got := x.Extract("Nvidia 與 tsmc 合作", "台灣加入 CPTPP 進度, 柯p 表示")
+
+		ids := []string{}
+		for _, m := range got {
+			ids = append(ids, m.EntityID)
+		}
+		assert.Equal(t, []string{"nvidia", "tsmc", "ko-wen-je"}, ids)
+		assert.Equal(t, []string{"Nvidia"}, got[0].Aliases)
+	})
+
+	t.Run("未提及", func(t *testing.T) {
+		assert.Empty(t, x.Extract("颱風接近", "各地降雨"))
+	})
+}
@@ -0,0 +1,115 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/category"
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+)
+
+// CategoryScore 單一分類, 媒體與分析類型的平均分數.
+type CategoryScore struct {
+	Category string              `json:"category"`
+	Name     string              `json:"name"`
+	MediaID  uint                `json:"mediaId"`
+	Type     entity.AnalysisType `json:"type"`
+	Count    int64               `json:"count"`
+	AvgScore float64             `json:"avgScore"`
+}
+
+type CategoryHandler struct {
+	tracer       trace.Tracer
+	logger       *zerolog.Logger
+	classifier   *category.Classifier
+	analysisRepo repository.AnalysisRepository
+}
+
+func NewCategoryHandler(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	classifier *category.Classifier,
+	analysisRepo repository.AnalysisRepository,
+) *CategoryHandler {
+	return &CategoryHandler{
+		tracer:       tracer,
+		logger:       logger,
+		classifier:   classifier,
+		analysisRepo: analysisRepo,
+	}
+}
+
+// Register 註冊分類 API.
+func (h *CategoryHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/categories", h.GetTaxonomy)
+	mux.HandleFunc("GET /api/categories/scores", h.GetScores)
+}
+
+// GetTaxonomy 取得統一分類與各媒體的版面對應.
+// GET /api/categories
+func (h *CategoryHandler) GetTaxonomy(w http.ResponseWriter, r *http.Request) {
+	_, span := h.tracer.Start(r.Context(), "domain/news/delivery/category_handler/GetTaxonomy: Get Taxonomy")
+	defer span.End()
+
+	h.write(w, r, h.classifier.Taxonomy())
+}
+
+// GetScores 依分類比較各媒體的平均分數, 預設為最近 30 天發布的新聞.
+// GET /api/categories/scores?from=2025-05-01T00:00:00Z&to=2025-06-01T00:00:00Z
+func (h *CategoryHandler) GetScores(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/news/delivery/category_handler/GetScores: Get Category Scores")
+	defer span.End()
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	for name, t := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "invalid "+name+", use RFC3339", http.StatusBadRequest)
+			return
+		}
+		*t = parsed
+	}
+
+	summaries, err := h.analysisRepo.SumScoresByCategory(ctx, from, to)
+	if err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to sum scores by category")
+		http.Error(w, "failed to get category scores", http.StatusInternalServerError)
+		return
+	}
+
+	names := map[string]string{}
+	for _, c := range h.classifier.Taxonomy().Categories {
+		names[c.Key] = c.Name
+	}
+
+	scores := make([]CategoryScore, 0, len(summaries))
+	for _, summary := range summaries {
+		scores = append(scores, CategoryScore{
+			Category: summary.CategoryKey,
+			Name:     names[summary.CategoryKey],
+			MediaID:  summary.MediaID,
+			Type:     summary.Type,
+			Count:    summary.Count,
+			AvgScore: summary.AvgScore,
+		})
+	}
+
+	h.write(w, r, scores)
+}
+
+func (h *CategoryHandler) write(w http.ResponseWriter, r *http.Request, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error().Err(err).Ctx(r.Context()).Msg("failed to write category response")
+	}
+}
@@ -0,0 +1,126 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/ner"
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+	"itmrchow/tw-media-analytics-service/domain/news/service"
+)
+
+// EntityCoverage 實體於各媒體的報導量與客觀性.
+type EntityCoverage struct {
+	Entity   ner.Entity                    `json:"entity"`
+	Interval service.Interval              `json:"interval"`
+	Type     entity.AnalysisType           `json:"type"`
+	Points   []service.EntityCoveragePoint `json:"points"`
+}
+
+type EntityHandler struct {
+	tracer     trace.Tracer
+	logger     *zerolog.Logger
+	extractor  *ner.Extractor
+	entityRepo repository.NewsEntityRepository
+}
+
+func NewEntityHandler(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	extractor *ner.Extractor,
+	entityRepo repository.NewsEntityRepository,
+) *EntityHandler {
+	return &EntityHandler{
+		tracer:     tracer,
+		logger:     logger,
+		extractor:  extractor,
+		entityRepo: entityRepo,
+	}
+}
+
+// Register 註冊具名實體 API.
+func (h *EntityHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/entities", h.GetDictionary)
+	mux.HandleFunc("GET /api/entities/{id}/coverage", h.GetCoverage)
+}
+
+// GetDictionary 取得追蹤的實體與寫法.
+// GET /api/entities
+func (h *EntityHandler) GetDictionary(w http.ResponseWriter, r *http.Request) {
+	_, span := h.tracer.Start(r.Context(), "domain/news/delivery/entity_handler/GetDictionary: Get Entity Dictionary")
+	defer span.End()
+
+	h.write(w, r, h.extractor.Dictionary())
+}
+
+// GetCoverage 依區間與媒體統計提及實體的新聞數與平均客觀性, 預設為最近 30 天, 以 from 的時區分日.
+// GET /api/entities/{id}/coverage?from=2025-05-01T00:00:00+08:00&to=&interval=day|week|month&type=content|title
+func (h *EntityHandler) GetCoverage(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/news/delivery/entity_handler/GetCoverage: Get Entity Coverage")
+	defer span.End()
+
+	dictionary := h.extractor.Dictionary()
+	i := slices.IndexFunc(dictionary.Entities, func(e ner.Entity) bool { return e.ID == r.PathValue("id") })
+	if i < 0 {
+		http.Error(w, "entity not found", http.StatusNotFound)
+		return
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	for name, t := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "invalid "+name+", use RFC3339", http.StatusBadRequest)
+			return
+		}
+		*t = parsed
+	}
+
+	interval, err := service.ParseInterval(r.URL.Query().Get("interval"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	analysisType := entity.AnalysisType(r.URL.Query().Get("type"))
+	switch analysisType {
+	case "":
+		analysisType = entity.AnalysisTypeContent
+	case entity.AnalysisTypeContent, entity.AnalysisTypeTitle:
+	default:
+		http.Error(w, "invalid type, use content or title", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := h.entityRepo.FindEntityCoverage(ctx, dictionary.Entities[i].ID, analysisType, from, to)
+	if err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to find entity coverage")
+		http.Error(w, "failed to get entity coverage", http.StatusInternalServerError)
+		return
+	}
+
+	h.write(w, r, EntityCoverage{
+		Entity:   dictionary.Entities[i],
+		Interval: interval,
+		Type:     analysisType,
+		Points:   service.EntityCoverage(rows, interval, from.Location()),
+	})
+}
+
+func (h *EntityHandler) write(w http.ResponseWriter, r *http.Request, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error().Err(err).Ctx(r.Context()).Msg("failed to write entity response")
+	}
+}
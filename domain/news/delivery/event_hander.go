@@ -18,7 +18,9 @@ type NewsEventHandler struct {
 
 	db *gorm.DB
 
-	newsService service.NewsService
+	newsService      service.NewsService
+	storyService     service.StoryService
+	embeddingService service.EmbeddingService
 }
 
 func NewNewsEventHandler(
@@ -26,12 +28,16 @@ func NewNewsEventHandler(
 	tracer trace.Tracer,
 	db *gorm.DB,
 	newsService service.NewsService,
+	storyService service.StoryService,
+	embeddingService service.EmbeddingService,
 ) *NewsEventHandler {
 	return &NewsEventHandler{
-		tracer:      tracer,
-		logger:      logger,
-		newsService: newsService,
-		db:          db,
+		tracer:           tracer,
+		logger:           logger,
+		newsService:      newsService,
+		storyService:     storyService,
+		embeddingService: embeddingService,
+		db:               db,
 	}
 }
 
@@ -114,3 +120,59 @@ func (h *NewsEventHandler) GetAnalysisHandle(ctx context.Context, msg []byte) er
 
 	return nil
 }
+
+// StoryClusteringHandle 跨媒體事件分群.
+func (h *NewsEventHandler) StoryClusteringHandle(ctx context.Context, msg []byte) error {
+	// Tracer
+	ctx, span := h.tracer.Start(
+		ctx,
+		"domain/news/delivery/event_hander/StoryClusteringHandle: Story Clustering Handle",
+	)
+	h.logger.Info().Ctx(ctx).Msg("StoryClusteringHandle: start")
+	defer func() {
+		span.End()
+		h.logger.Info().Ctx(ctx).Msg("StoryClusteringHandle end")
+	}()
+
+	// check msg event type
+	var storyClusteringEvent utils.EventStoryClustering
+	if err := json.Unmarshal(msg, &storyClusteringEvent); err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to unmarshal message to StoryClusteringEvent")
+		return err
+	}
+
+	if err := h.storyService.ClusterStories(ctx, storyClusteringEvent); err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to cluster stories")
+		return err
+	}
+
+	return nil
+}
+
+// EmbedNewsHandle 計算新聞向量.
+func (h *NewsEventHandler) EmbedNewsHandle(ctx context.Context, msg []byte) error {
+	// Tracer
+	ctx, span := h.tracer.Start(
+		ctx,
+		"domain/news/delivery/event_hander/EmbedNewsHandle: Embed News Handle",
+	)
+	h.logger.Info().Ctx(ctx).Msg("EmbedNewsHandle: start")
+	defer func() {
+		span.End()
+		h.logger.Info().Ctx(ctx).Msg("EmbedNewsHandle end")
+	}()
+
+	// check msg event type
+	var newsEmbeddingEvent utils.EventNewsEmbedding
+	if err := json.Unmarshal(msg, &newsEmbeddingEvent); err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to unmarshal message to NewsEmbeddingEvent")
+		return err
+	}
+
+	if err := h.embeddingService.EmbedNews(ctx, newsEmbeddingEvent); err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to embed news")
+		return err
+	}
+
+	return nil
+}
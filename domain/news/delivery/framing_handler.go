@@ -0,0 +1,97 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+)
+
+// MediaFraming 單一媒體各框架指標的平均分數.
+type MediaFraming struct {
+	MediaID uint                    `json:"mediaId"`
+	Metrics map[string]FramingScore `json:"metrics"`
+}
+
+// FramingScore 框架指標的新聞數與平均分數.
+type FramingScore struct {
+	Count    int64   `json:"count"`
+	AvgScore float64 `json:"avgScore"`
+}
+
+type FramingHandler struct {
+	tracer       trace.Tracer
+	logger       *zerolog.Logger
+	analysisRepo repository.AnalysisRepository
+}
+
+func NewFramingHandler(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	analysisRepo repository.AnalysisRepository,
+) *FramingHandler {
+	return &FramingHandler{
+		tracer:       tracer,
+		logger:       logger,
+		analysisRepo: analysisRepo,
+	}
+}
+
+// Register 註冊框架分析 API.
+func (h *FramingHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/framing", h.GetFraming)
+}
+
+// GetFraming 比較各媒體的政治立場, 情緒語氣與價值判斷用語, 預設為最近 30 天發布的新聞.
+// GET /api/framing?from=2025-05-01T00:00:00Z&to=2025-06-01T00:00:00Z
+func (h *FramingHandler) GetFraming(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/news/delivery/framing_handler/GetFraming: Get Framing")
+	defer span.End()
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	for name, t := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "invalid "+name+", use RFC3339", http.StatusBadRequest)
+			return
+		}
+		*t = parsed
+	}
+
+	summaries, err := h.analysisRepo.SumFramingByMedia(ctx, from, to)
+	if err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to sum framing by media")
+		http.Error(w, "failed to get framing", http.StatusInternalServerError)
+		return
+	}
+
+	// summaries 依媒體排序
+	result := []MediaFraming{}
+	for _, summary := range summaries {
+		if len(result) == 0 || result[len(result)-1].MediaID != summary.MediaID {
+			result = append(result, MediaFraming{MediaID: summary.MediaID, Metrics: map[string]FramingScore{}})
+		}
+		result[len(result)-1].Metrics[summary.MetricKey] = FramingScore{
+			Count:    summary.Count,
+			AvgScore: summary.AvgScore,
+		}
+	}
+
+	h.write(w, r, result)
+}
+
+func (h *FramingHandler) write(w http.ResponseWriter, r *http.Request, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error().Err(err).Ctx(r.Context()).Msg("failed to write framing response")
+	}
+}
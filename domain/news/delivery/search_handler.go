@@ -0,0 +1,207 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+	snippetRunes       = 80
+)
+
+// NewsSearchResult 新聞檢索結果.
+type NewsSearchResult struct {
+	Total int64            `json:"total"`
+	Items []NewsSearchItem `json:"items"`
+}
+
+// NewsSearchItem 符合條件的新聞與關鍵字所在的內容片段.
+type NewsSearchItem struct {
+	NewsID      string           `json:"newsId"`
+	MediaID     uint             `json:"mediaId"`
+	Title       string           `json:"title"`
+	URL         string           `json:"url"`
+	AuthorID    uint             `json:"authorId"`
+	CategoryKey string           `json:"categoryKey"`
+	PublishedAt time.Time        `json:"publishedAt"`
+	Score       *decimal.Decimal `json:"score,omitempty"` // 有分數條件時為比對的分數
+	Snippet     string           `json:"snippet"`
+}
+
+type SearchHandler struct {
+	tracer     trace.Tracer
+	logger     *zerolog.Logger
+	searchRepo repository.NewsSearchRepository
+}
+
+func NewSearchHandler(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	searchRepo repository.NewsSearchRepository,
+) *SearchHandler {
+	return &SearchHandler{
+		tracer:     tracer,
+		logger:     logger,
+		searchRepo: searchRepo,
+	}
+}
+
+// Register 註冊新聞檢索 API.
+func (h *SearchHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/search/news", h.SearchNews)
+}
+
+// SearchNews 以關鍵字與媒體, 作者, 分類, 日期及分數範圍檢索新聞, 預設為最近 30 天.
+// GET /api/search/news?q=颱風&media=1,2&author=&category=&from=&to=&type=title&metric=clarity&max_score=2&limit=50&offset=0
+func (h *SearchHandler) SearchNews(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/news/delivery/search_handler/SearchNews: Search News")
+	defer span.End()
+
+	params := r.URL.Query()
+	query := entity.NewsSearchQuery{
+		Keyword:     strings.TrimSpace(params.Get("q")),
+		CategoryKey: params.Get("category"),
+		ScoreType:   entity.AnalysisType(params.Get("type")),
+		MetricKey:   params.Get("metric"),
+		To:          time.Now(),
+		Limit:       defaultSearchLimit,
+	}
+	query.From = query.To.AddDate(0, 0, -30)
+
+	for name, t := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "invalid "+name+", use RFC3339", http.StatusBadRequest)
+			return
+		}
+		*t = parsed
+	}
+
+	if value := params.Get("media"); value != "" {
+		for _, s := range strings.Split(value, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+			if err != nil {
+				http.Error(w, "invalid media", http.StatusBadRequest)
+				return
+			}
+			query.MediaIDList = append(query.MediaIDList, uint(id))
+		}
+	}
+
+	if value := params.Get("author"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid author", http.StatusBadRequest)
+			return
+		}
+		query.AuthorID = uint(id)
+	}
+
+	for name, score := range map[string]*decimal.NullDecimal{"min_score": &query.MinScore, "max_score": &query.MaxScore} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := decimal.NewFromString(value)
+		if err != nil {
+			http.Error(w, "invalid "+name, http.StatusBadRequest)
+			return
+		}
+		*score = decimal.NewNullDecimal(parsed)
+	}
+	validType := query.ScoreType == entity.AnalysisTypeFraming ||
+		slices.Contains(entity.QualityAnalysisTypes, query.ScoreType)
+	if query.HasScoreRange() && !validType {
+		http.Error(w, "invalid type, required with min_score or max_score", http.StatusBadRequest)
+		return
+	}
+
+	for name, n := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || (name == "limit" && parsed == 0) {
+			http.Error(w, "invalid "+name, http.StatusBadRequest)
+			return
+		}
+		*n = parsed
+	}
+	query.Limit = min(query.Limit, maxSearchLimit)
+
+	rows, total, err := h.searchRepo.SearchNews(ctx, query)
+	if err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to search news")
+		http.Error(w, "failed to search news", http.StatusInternalServerError)
+		return
+	}
+
+	result := NewsSearchResult{Total: total, Items: make([]NewsSearchItem, 0, len(rows))}
+	terms := strings.Fields(query.Keyword)
+	for _, row := range rows {
+		item := NewsSearchItem{
+			NewsID:      row.NewsID,
+			MediaID:     row.MediaID,
+			Title:       row.Title,
+			URL:         row.URL,
+			AuthorID:    row.AuthorID,
+			CategoryKey: row.CategoryKey,
+			PublishedAt: row.PublishedAt,
+			Snippet:     snippet(row.Content, terms, snippetRunes),
+		}
+		if row.Score.Valid {
+			item.Score = &row.Score.Decimal
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	h.write(w, r, result)
+}
+
+// snippet 擷取內容中第一個出現的關鍵字前後共 size 字, 未出現時取開頭.
+func snippet(content string, terms []string, size int) string {
+	runes := []rune(content)
+	start := 0
+	for _, term := range terms {
+		if i := strings.Index(content, term); i >= 0 {
+			// 關鍵字前保留四分之一的長度
+			start = max(len([]rune(content[:i]))-size/4, 0)
+			break
+		}
+	}
+	end := min(start+size, len(runes))
+
+	result := string(runes[start:end])
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(runes) {
+		result += "…"
+	}
+	return result
+}
+
+func (h *SearchHandler) write(w http.ResponseWriter, r *http.Request, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error().Err(err).Ctx(r.Context()).Msg("failed to write search response")
+	}
+}
@@ -0,0 +1,217 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+
+	"itmrchow/tw-media-analytics-service/domain/ai"
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/news/service"
+	"itmrchow/tw-media-analytics-service/domain/utils/server"
+)
+
+const (
+	defaultSimilarLimit    = 10
+	maxSimilarLimit        = 100
+	defaultQueryRatePerMin = 10
+	// limiterSweepInterval 移除閒置使用者 limiter 的間隔
+	limiterSweepInterval = time.Minute
+)
+
+type SimilarHandler struct {
+	tracer           trace.Tracer
+	logger           *zerolog.Logger
+	embeddingService service.EmbeddingService
+
+	// 查詢文字需呼叫付費的 embedding API, 限定驗證過的使用者並限制每人的頻率
+	queryAuth      *server.Authenticator
+	queryRate      rate.Limit
+	queryBurst     int
+	limiterMu      sync.Mutex
+	limiters       map[string]*rate.Limiter
+	limiterSweptAt time.Time
+	now            func() time.Time
+}
+
+func NewSimilarHandler(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	embeddingService service.EmbeddingService,
+) *SimilarHandler {
+	perMinute := viper.GetInt("SIMILAR_QUERY_RATE_PER_MINUTE")
+	if perMinute <= 0 {
+		perMinute = defaultQueryRatePerMin
+	}
+
+	return &SimilarHandler{
+		tracer:           tracer,
+		logger:           logger,
+		embeddingService: embeddingService,
+		queryAuth:        server.NewAuthenticator("SIMILAR_QUERY_AUTH_HEADER"),
+		queryRate:        rate.Limit(float64(perMinute) / 60),
+		queryBurst:       perMinute,
+		limiters:         map[string]*rate.Limiter{},
+		now:              time.Now,
+	}
+}
+
+// Register 註冊相似新聞 API.
+func (h *SimilarHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/news/{media_id}/{news_id}/similar", h.SimilarToNews)
+	mux.Handle("GET /api/search/similar", h.queryAuth.Authenticate(h.SearchSimilar))
+}
+
+// SimilarToNews 與指定新聞語意最相似的新聞, 預設比對最近 30 天.
+// GET /api/news/{media_id}/{news_id}/similar?media=1,2&from=2025-05-01T00:00:00+08:00&to=&limit=10
+func (h *SimilarHandler) SimilarToNews(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/news/delivery/similar_handler/SimilarToNews: Similar To News")
+	defer span.End()
+
+	mediaID, err := strconv.ParseUint(r.PathValue("media_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid media id", http.StatusBadRequest)
+		return
+	}
+
+	filter, limit, ok := h.parseQuery(w, r)
+	if !ok {
+		return
+	}
+
+	similar, err := h.embeddingService.SimilarToNews(ctx, r.PathValue("news_id"), uint(mediaID), filter, limit)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.write(w, r, similar)
+}
+
+// SearchSimilar 與查詢文字語意最相似的新聞, 預設比對最近 30 天.
+// 查詢文字會呼叫 embedding API (計入 AI 用量與預算), 需驗證 header 且每位使用者限制頻率.
+// GET /api/search/similar?q=颱風停班停課&media=1,2&from=&to=&limit=10
+func (h *SimilarHandler) SearchSimilar(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/news/delivery/similar_handler/SearchSimilar: Search Similar")
+	defer span.End()
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		http.Error(w, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	filter, limit, ok := h.parseQuery(w, r)
+	if !ok {
+		return
+	}
+
+	// 請求格式正確才計入頻率
+	if !h.allow(server.AuthenticatedUser(ctx)) {
+		http.Error(w, "too many similar queries", http.StatusTooManyRequests)
+		return
+	}
+
+	similar, err := h.embeddingService.SearchSimilar(ctx, query, filter, limit)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.write(w, r, similar)
+}
+
+// allow 使用者的查詢是否在頻率限制內, 並定期移除閒置的 limiter 避免無限增長.
+func (h *SimilarHandler) allow(user string) bool {
+	h.limiterMu.Lock()
+	defer h.limiterMu.Unlock()
+
+	now := h.now()
+	if now.Sub(h.limiterSweptAt) >= limiterSweepInterval {
+		for u, limiter := range h.limiters {
+			// 額度已回滿的 limiter 與新建立的相同, 移除不影響限制
+			if limiter.TokensAt(now) >= float64(h.queryBurst) {
+				delete(h.limiters, u)
+			}
+		}
+		h.limiterSweptAt = now
+	}
+
+	limiter, ok := h.limiters[user]
+	if !ok {
+		limiter = rate.NewLimiter(h.queryRate, h.queryBurst)
+		h.limiters[user] = limiter
+	}
+	return limiter.AllowN(now, 1)
+}
+
+// parseQuery 解析 media, from, to 與 limit, 格式錯誤時回應 400 並回傳 false.
+func (h *SimilarHandler) parseQuery(w http.ResponseWriter, r *http.Request) (entity.EmbeddingFilter, int, bool) {
+	filter := entity.EmbeddingFilter{To: time.Now()}
+	filter.From = filter.To.AddDate(0, 0, -30)
+	for name, t := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "invalid "+name+", use RFC3339", http.StatusBadRequest)
+			return filter, 0, false
+		}
+		*t = parsed
+	}
+
+	if value := r.URL.Query().Get("media"); value != "" {
+		for _, s := range strings.Split(value, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+			if err != nil {
+				http.Error(w, "invalid media", http.StatusBadRequest)
+				return filter, 0, false
+			}
+			filter.MediaIDList = append(filter.MediaIDList, uint(id))
+		}
+	}
+
+	limit := defaultSimilarLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return filter, 0, false
+		}
+		limit = min(parsed, maxSimilarLimit)
+	}
+
+	return filter, limit, true
+}
+
+func (h *SimilarHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, service.ErrEmbeddingDisabled):
+		http.Error(w, "embedding disabled", http.StatusServiceUnavailable)
+	case errors.Is(err, service.ErrEmbeddingNotFound):
+		http.Error(w, "news embedding not found", http.StatusNotFound)
+	case errors.Is(err, ai.ErrBudgetExceeded):
+		http.Error(w, "ai budget exceeded", http.StatusTooManyRequests)
+	default:
+		h.logger.Error().Err(err).Ctx(r.Context()).Msg("failed to search similar news")
+		http.Error(w, "failed to search similar news", http.StatusInternalServerError)
+	}
+}
+
+func (h *SimilarHandler) write(w http.ResponseWriter, r *http.Request, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error().Err(err).Ctx(r.Context()).Msg("failed to write similar news response")
+	}
+}
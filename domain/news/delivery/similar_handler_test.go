@@ -0,0 +1,102 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/ai"
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/news/service"
+	"itmrchow/tw-media-analytics-service/domain/utils/server"
+)
+
+// fakeEmbeddingService 只實作 SearchSimilar, 記錄呼叫次數.
+type fakeEmbeddingService struct {
+	service.EmbeddingService
+
+	err   error
+	calls int
+}
+
+func (s *fakeEmbeddingService) SearchSimilar(context.Context, string, entity.EmbeddingFilter, int) ([]service.SimilarNews, error) {
+	s.calls++
+	return []service.SimilarNews{}, s.err
+}
+
+func TestSimilarHandler_SearchSimilar(t *testing.T) {
+	viper.Set("SIMILAR_QUERY_RATE_PER_MINUTE", 2)
+	t.Cleanup(func() { viper.Set("SIMILAR_QUERY_RATE_PER_MINUTE", nil) })
+
+	logger := zerolog.Nop()
+	svc := &fakeEmbeddingService{}
+	mux := http.NewServeMux()
+	NewSimilarHandler(&logger, otel.Tracer("tw-media-analytics-service_test"), svc).Register(mux)
+
+	search := func(user string) int {
+		return searchSimilar(mux, user, "颱風")
+	}
+
+	// 未驗證不呼叫 embedding
+	assert.Equal(t, http.StatusUnauthorized, search(""))
+	assert.Zero(t, svc.calls)
+
+	// 格式錯誤的請求不計入頻率
+	assert.Equal(t, http.StatusBadRequest, searchSimilar(mux, "alice", ""))
+	assert.Equal(t, http.StatusBadRequest, searchSimilar(mux, "alice", ""))
+	assert.Equal(t, http.StatusBadRequest, searchSimilar(mux, "alice", ""))
+
+	// 每位使用者各自限制頻率
+	assert.Equal(t, http.StatusOK, search("alice"))
+	assert.Equal(t, http.StatusOK, search("alice"))
+	assert.Equal(t, http.StatusTooManyRequests, search("alice"))
+	assert.Equal(t, http.StatusOK, search("bob"))
+	assert.Equal(t, 3, svc.calls)
+
+	// 預算用完
+	svc.err = ai.ErrBudgetExceeded
+	assert.Equal(t, http.StatusTooManyRequests, search("carol"))
+}
+
+func TestSimilarHandler_SweepIdleLimiters(t *testing.T) {
+	viper.Set("SIMILAR_QUERY_RATE_PER_MINUTE", 2)
+	t.Cleanup(func() { viper.Set("SIMILAR_QUERY_RATE_PER_MINUTE", nil) })
+
+	logger := zerolog.Nop()
+	h := NewSimilarHandler(&logger, otel.Tracer("tw-media-analytics-service_test"), &fakeEmbeddingService{})
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	assert.True(t, h.allow("bob"))
+	assert.True(t, h.allow("carol"))
+	now = now.Add(50 * time.Second)
+	assert.True(t, h.allow("alice"))
+	assert.True(t, h.allow("alice"))
+	assert.False(t, h.allow("alice"))
+	assert.Len(t, h.limiters, 3)
+
+	// bob 與 carol 額度已回滿而移除, alice 仍在限制中而保留
+	now = now.Add(10 * time.Second)
+	assert.True(t, h.allow("dave"))
+	assert.Len(t, h.limiters, 2)
+	assert.Contains(t, h.limiters, "alice")
+	assert.Contains(t, h.limiters, "dave")
+}
+
+func searchSimilar(mux *http.ServeMux, user string, query string) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/search/similar?q="+url.QueryEscape(query), nil)
+	if user != "" {
+		req.Header.Set(server.DefaultAuthHeader, user)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec.Code
+}
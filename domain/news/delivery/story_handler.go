@@ -0,0 +1,122 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/news/service"
+)
+
+const (
+	defaultStoryLimit = 50
+	maxStoryLimit     = 200
+)
+
+type StoryHandler struct {
+	tracer       trace.Tracer
+	logger       *zerolog.Logger
+	storyService service.StoryService
+}
+
+func NewStoryHandler(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	storyService service.StoryService,
+) *StoryHandler {
+	return &StoryHandler{
+		tracer:       tracer,
+		logger:       logger,
+		storyService: storyService,
+	}
+}
+
+// Register 註冊跨媒體事件 API.
+func (h *StoryHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/stories", h.ListStories)
+	mux.HandleFunc("GET /api/stories/{id}", h.GetStory)
+}
+
+// ListStories 列出期間內首次報導的事件與各媒體的報導, 預設為最近 30 天且至少兩家媒體報導.
+// GET /api/stories?from=2025-05-01T00:00:00+08:00&to=&min_media=2&limit=50
+func (h *StoryHandler) ListStories(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/news/delivery/story_handler/ListStories: List Stories")
+	defer span.End()
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	for name, t := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "invalid "+name+", use RFC3339", http.StatusBadRequest)
+			return
+		}
+		*t = parsed
+	}
+
+	minMedia := 2
+	limit := defaultStoryLimit
+	for name, n := range map[string]*int{"min_media": &minMedia, "limit": &limit} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "invalid "+name, http.StatusBadRequest)
+			return
+		}
+		*n = parsed
+	}
+	limit = min(limit, maxStoryLimit)
+
+	stories, err := h.storyService.ListStories(ctx, from, to, minMedia, limit)
+	if err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to list stories")
+		http.Error(w, "failed to list stories", http.StatusInternalServerError)
+		return
+	}
+
+	h.write(w, r, stories)
+}
+
+// GetStory 取得單一事件各媒體的標題, 分數與發布時間差.
+// GET /api/stories/{id}
+func (h *StoryHandler) GetStory(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/news/delivery/story_handler/GetStory: Get Story")
+	defer span.End()
+
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid story id", http.StatusBadRequest)
+		return
+	}
+
+	story, err := h.storyService.GetStory(ctx, uint(id))
+	if err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to get story")
+		http.Error(w, "failed to get story", http.StatusInternalServerError)
+		return
+	}
+	if story == nil {
+		http.Error(w, "story not found", http.StatusNotFound)
+		return
+	}
+
+	h.write(w, r, story)
+}
+
+func (h *StoryHandler) write(w http.ResponseWriter, r *http.Request, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error().Err(err).Ctx(r.Context()).Msg("failed to write story response")
+	}
+}
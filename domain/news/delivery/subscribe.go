@@ -4,12 +4,12 @@ import (
 	"context"
 
 	"github.com/ThreeDotsLabs/watermill-googlecloud/pkg/googlecloud"
-	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 
 	"itmrchow/tw-media-analytics-service/domain/queue"
+	"itmrchow/tw-media-analytics-service/domain/utils/mq"
 )
 
 // InitNewsSubscribe 初始化新聞相關訂閱.
@@ -38,7 +38,7 @@ func InitNewsSubscribe(
 			logger.Error().Ctx(ctx).Err(err).Msg("failed to subscribe news check")
 			return err
 		}
-		go process(logger, newsCheckMsg, handler.CheckNewsExistHandle)
+		go mq.Process(logger, string(queue.TopicNewsCheck), newsCheckMsg, handler.CheckNewsExistHandle)
 		return nil
 	})
 
@@ -49,7 +49,7 @@ func InitNewsSubscribe(
 			logger.Error().Ctx(ctx).Err(err).Msg("failed to subscribe news save")
 			return err
 		}
-		go process(logger, newsSaveMsg, handler.SaveNewsHandle)
+		go mq.Process(logger, string(queue.TopicNewsSave), newsSaveMsg, handler.SaveNewsHandle)
 		return nil
 	})
 
@@ -60,22 +60,29 @@ func InitNewsSubscribe(
 			logger.Error().Ctx(ctx).Err(err).Msg("failed to subscribe get analysis")
 			return err
 		}
-		go process(logger, getAnalysisMsg, handler.GetAnalysisHandle)
+		go mq.Process(logger, string(queue.TopicGetAnalysis), getAnalysisMsg, handler.GetAnalysisHandle)
 		return nil
 	})
-}
 
-func process(
-	logger *zerolog.Logger,
-	messages <-chan *message.Message,
-	handler func(ctx context.Context, msg []byte) error,
-) {
-	for msg := range messages {
-		// call handler
-		if err := handler(msg.Context(), msg.Payload); err != nil {
-			logger.Error().Ctx(msg.Context()).Err(err).Bytes("payload", msg.Payload).Msg("failed to process message")
-			return
+	// - NewsEmbedding
+	group.Go(func() error {
+		newsEmbeddingMsg, err := subscriber.Subscribe(ctx, string(queue.TopicNewsEmbedding))
+		if err != nil {
+			logger.Error().Ctx(ctx).Err(err).Msg("failed to subscribe news embedding")
+			return err
 		}
-		msg.Ack()
-	}
+		go mq.Process(logger, string(queue.TopicNewsEmbedding), newsEmbeddingMsg, handler.EmbedNewsHandle)
+		return nil
+	})
+
+	// - StoryClustering
+	group.Go(func() error {
+		storyClusteringMsg, err := subscriber.Subscribe(ctx, string(queue.TopicStoryClustering))
+		if err != nil {
+			logger.Error().Ctx(ctx).Err(err).Msg("failed to subscribe story clustering")
+			return err
+		}
+		go mq.Process(logger, string(queue.TopicStoryClustering), storyClusteringMsg, handler.StoryClusteringHandle)
+		return nil
+	})
 }
@@ -0,0 +1,102 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+)
+
+// NewsSummary 新聞的中立摘要與可查核的事實陳述.
+type NewsSummary struct {
+	NewsID    string      `json:"newsId"`
+	MediaID   uint        `json:"mediaId"`
+	Summary   string      `json:"summary"`
+	Claims    []NewsClaim `json:"claims"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
+
+// NewsClaim 事實陳述與原文位置, 找不到原文時 quoteStart, quoteEnd 為 null.
+type NewsClaim struct {
+	Claim      string `json:"claim"`
+	Quote      string `json:"quote"`
+	QuoteStart *int   `json:"quoteStart"`
+	QuoteEnd   *int   `json:"quoteEnd"`
+}
+
+type SummaryHandler struct {
+	tracer      trace.Tracer
+	logger      *zerolog.Logger
+	summaryRepo repository.NewsSummaryRepository
+}
+
+func NewSummaryHandler(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	summaryRepo repository.NewsSummaryRepository,
+) *SummaryHandler {
+	return &SummaryHandler{
+		tracer:      tracer,
+		logger:      logger,
+		summaryRepo: summaryRepo,
+	}
+}
+
+// Register 註冊新聞摘要 API.
+func (h *SummaryHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/news/{media_id}/{news_id}/summary", h.GetSummary)
+}
+
+// GetSummary 取得新聞摘要與事實陳述.
+// GET /api/news/{media_id}/{news_id}/summary
+func (h *SummaryHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/news/delivery/summary_handler/GetSummary: Get News Summary")
+	defer span.End()
+
+	mediaID, err := strconv.ParseUint(r.PathValue("media_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid media id", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.summaryRepo.GetNewsSummary(ctx, r.PathValue("news_id"), uint(mediaID))
+	if err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to get news summary")
+		http.Error(w, "failed to get news summary", http.StatusInternalServerError)
+		return
+	}
+	if summary == nil {
+		http.Error(w, "news summary not found", http.StatusNotFound)
+		return
+	}
+
+	result := NewsSummary{
+		NewsID:    summary.NewsID,
+		MediaID:   summary.MediaID,
+		Summary:   summary.Summary,
+		Claims:    make([]NewsClaim, 0, len(summary.ClaimList)),
+		UpdatedAt: summary.UpdatedAt,
+	}
+	for _, claim := range summary.ClaimList {
+		result.Claims = append(result.Claims, NewsClaim{
+			Claim:      claim.Claim,
+			Quote:      claim.Quote,
+			QuoteStart: claim.QuoteStart,
+			QuoteEnd:   claim.QuoteEnd,
+		})
+	}
+
+	h.write(w, r, result)
+}
+
+func (h *SummaryHandler) write(w http.ResponseWriter, r *http.Request, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error().Err(err).Ctx(r.Context()).Msg("failed to write summary response")
+	}
+}
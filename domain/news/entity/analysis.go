@@ -13,9 +13,30 @@ type Analysis struct {
 	Score   decimal.Decimal `json:"score" gorm:"type:decimal(10,2);not null"`
 	Reason  string          `json:"reason" gorm:"type:text;not null"`
 
+	// InjectionSuspected 新聞內容含有類似對 AI 下指令的文字, 評分可能被操弄
+	InjectionSuspected bool `json:"injection_suspected" gorm:"not null;default:false"`
+
 	// Relations
 	News                News             `gorm:"foreignKey:NewsID,MediaID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	AnalysisMetricsList []AnalysisMetric `gorm:"foreignKey:AnalysisID"`
+	RunList             []AnalysisRun    `gorm:"foreignKey:AnalysisID"`
+}
+
+// FramingSummary 依媒體與框架指標彙總的平均分數.
+type FramingSummary struct {
+	MediaID   uint
+	MetricKey string
+	Count     int64
+	AvgScore  float64
+}
+
+// CategoryScoreSummary 依分類, 媒體與分析類型彙總的分數.
+type CategoryScoreSummary struct {
+	CategoryKey string
+	MediaID     uint
+	Type        AnalysisType
+	Count       int64
+	AvgScore    float64
 }
 
 type AnalysisType string
@@ -23,4 +44,11 @@ type AnalysisType string
 const (
 	AnalysisTypeContent AnalysisType = "content"
 	AnalysisTypeTitle   AnalysisType = "title"
+	// AnalysisTypeTitleRule 規則式標題評分, 與 LLM 的 title 分析並存, 作為可解釋的基準
+	AnalysisTypeTitleRule AnalysisType = "title_rule"
+	// AnalysisTypeFraming 政治立場與框架, 指標範圍與品質評分不同, 不可併入品質分數
+	AnalysisTypeFraming AnalysisType = "framing"
 )
+
+// QualityAnalysisTypes 新聞品質評分的分析類型.
+var QualityAnalysisTypes = []AnalysisType{AnalysisTypeContent, AnalysisTypeTitle, AnalysisTypeTitleRule}
@@ -8,10 +8,12 @@ import (
 
 type AnalysisMetric struct {
 	utils.TimeModel
-	AnalysisID string          `json:"analysis_id" gorm:"primaryKey;type:char(36);not null;index"`
+	AnalysisID uint            `json:"analysis_id" gorm:"primaryKey;autoIncrement:false;not null;index"`
 	MetricKey  string          `json:"metric_key" gorm:"primaryKey;type:varchar(255);not null;index"`
 	Score      decimal.Decimal `json:"score" gorm:"type:decimal(10,2);not null"`
 	Reason     string          `json:"reason" gorm:"type:text;not null"`
+	// Variance 多模型評分的變異數, 單一模型時為 0
+	Variance decimal.Decimal `json:"variance" gorm:"type:decimal(10,2);not null;default:0"`
 
 	Analysis Analysis `gorm:"foreignKey:AnalysisID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 }
@@ -30,4 +32,10 @@ const (
 	MetricKeyContentTimeliness   AnalysisMetricKey = "timeliness"   // 內容即時性
 	MetricKeyContentImportance   AnalysisMetricKey = "importance"   // 內容重要性
 	MetricKeyContentPresentation AnalysisMetricKey = "presentation" // 內容呈現性
+
+	MetricKeyFramingStanceDPP   AnalysisMetricKey = "stance_dpp"   // 對民進黨的立場 (-2~2)
+	MetricKeyFramingStanceKMT   AnalysisMetricKey = "stance_kmt"   // 對國民黨的立場 (-2~2)
+	MetricKeyFramingStanceTPP   AnalysisMetricKey = "stance_tpp"   // 對民眾黨的立場 (-2~2)
+	MetricKeyFramingTone        AnalysisMetricKey = "tone"         // 情緒語氣 (-2~2)
+	MetricKeyFramingLoadedTerms AnalysisMetricKey = "loaded_terms" // 帶有價值判斷的用語 (0~5)
 )
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"github.com/shopspring/decimal"
+
+	"itmrchow/tw-media-analytics-service/domain/utils"
+)
+
+// AnalysisRun 多模型評分時, 單一模型單次的原始評分.
+type AnalysisRun struct {
+	utils.TimeModel
+	ID         uint            `json:"id" gorm:"primaryKey"`
+	AnalysisID uint            `json:"analysis_id" gorm:"not null;uniqueIndex:idx_analysis_run"`
+	Model      string          `json:"model" gorm:"type:varchar(255);not null;uniqueIndex:idx_analysis_run"`
+	Run        int             `json:"run" gorm:"not null;uniqueIndex:idx_analysis_run"`
+	Score      decimal.Decimal `json:"score" gorm:"type:decimal(10,2);not null"`
+	Reason     string          `json:"reason" gorm:"type:text;not null"`
+	// MetricList 各指標分數與評語, JSON 格式
+	MetricList string `json:"metric_list" gorm:"type:text;not null"`
+
+	Analysis Analysis `gorm:"foreignKey:AnalysisID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
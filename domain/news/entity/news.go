@@ -18,6 +18,10 @@ type News struct {
 	Category    string `gorm:"type:varchar(255);not null;default:''"`
 	PublishedAt time.Time
 
+	// CategoryKey 統一分類 (domain/category), Category 為各媒體原始的版面名稱
+	CategoryKey    string `gorm:"type:varchar(64);not null;default:'';index"`
+	CategorySource string `gorm:"type:varchar(32);not null;default:''"`
+
 	// Relations
 	Media        Media      `gorm:"foreignKey:MediaID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	Author       Author     `gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"itmrchow/tw-media-analytics-service/domain/utils"
+)
+
+// NewsEmbedding 新聞的語意向量, 以 little-endian float32 儲存 (domain/embedding), 不同模型的向量分開保存.
+type NewsEmbedding struct {
+	utils.TimeModel
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	NewsID     string `json:"news_id" gorm:"type:char(36);not null;uniqueIndex:idx_news_embedding"`
+	MediaID    uint   `json:"media_id" gorm:"not null;uniqueIndex:idx_news_embedding"`
+	Model      string `json:"model" gorm:"type:varchar(64);not null;uniqueIndex:idx_news_embedding"`
+	Dimensions int    `json:"dimensions" gorm:"not null"`
+	Vector     []byte `json:"-" gorm:"not null"`
+
+	News News `json:"-" gorm:"foreignKey:NewsID,MediaID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// NewsEmbeddingRow 搜尋候選的新聞與向量.
+type NewsEmbeddingRow struct {
+	NewsID      string
+	MediaID     uint
+	Title       string
+	URL         string
+	PublishedAt time.Time
+	Vector      []byte
+}
+
+// EmbeddingFilter 相似新聞的篩選條件, 零值代表不限制.
+type EmbeddingFilter struct {
+	MediaIDList []uint
+	From        time.Time // 含
+	To          time.Time // 不含
+}
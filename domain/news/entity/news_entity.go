@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"itmrchow/tw-media-analytics-service/domain/utils"
+)
+
+// NewsEntity 新聞提及的人物, 政黨, 機關或企業, EntityID 為詞典 (domain/ner) 的正規化 ID.
+type NewsEntity struct {
+	utils.TimeModel
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	NewsID     string `json:"news_id" gorm:"type:char(36);not null;uniqueIndex:idx_news_entity"`
+	MediaID    uint   `json:"media_id" gorm:"not null;uniqueIndex:idx_news_entity"`
+	EntityID   string `json:"entity_id" gorm:"type:varchar(64);not null;uniqueIndex:idx_news_entity;index"`
+	EntityName string `json:"entity_name" gorm:"type:varchar(255);not null"`
+	EntityType string `json:"entity_type" gorm:"type:varchar(32);not null"`
+	Mentions   int    `json:"mentions" gorm:"not null"`
+	InTitle    bool   `json:"in_title" gorm:"not null;default:false"`
+	// Aliases 新聞中出現的寫法, 以逗號分隔
+	Aliases string `json:"aliases" gorm:"type:varchar(512);not null;default:''"`
+
+	News News `gorm:"foreignKey:NewsID,MediaID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// EntityCoverageRow 提及實體的單篇新聞與其客觀性分數.
+type EntityCoverageRow struct {
+	NewsID      string
+	MediaID     uint
+	PublishedAt time.Time
+	Objectivity decimal.NullDecimal // 尚未分析時為 null
+}
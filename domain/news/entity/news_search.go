@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// NewsSearchQuery 新聞全文檢索與結構化條件, 零值代表不限制.
+type NewsSearchQuery struct {
+	Keyword     string // 以空白分隔, 需同時符合所有詞
+	MediaIDList []uint
+	AuthorID    uint
+	CategoryKey string
+	From        time.Time // 含
+	To          time.Time // 不含
+
+	// 分數範圍, 設定 MetricKey 時比對 ScoreType 分析的指標分數, 否則比對分析總分
+	ScoreType AnalysisType
+	MetricKey string
+	MinScore  decimal.NullDecimal // 含
+	MaxScore  decimal.NullDecimal // 含
+
+	Limit  int
+	Offset int
+}
+
+// HasScoreRange 是否有分數條件.
+func (q NewsSearchQuery) HasScoreRange() bool {
+	return q.MinScore.Valid || q.MaxScore.Valid
+}
+
+// NewsSearchRow 檢索結果, Score 為分數條件比對的分數.
+type NewsSearchRow struct {
+	NewsID      string
+	MediaID     uint
+	Title       string
+	Content     string
+	URL         string
+	AuthorID    uint
+	CategoryKey string
+	PublishedAt time.Time
+	Score       decimal.NullDecimal
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"itmrchow/tw-media-analytics-service/domain/utils"
+)
+
+// NewsSummary 新聞的中立摘要, 每篇新聞一筆, 重新產生時取代.
+type NewsSummary struct {
+	utils.TimeModel
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	NewsID  string `json:"news_id" gorm:"type:char(36);not null;uniqueIndex:idx_news_summary"`
+	MediaID uint   `json:"media_id" gorm:"not null;uniqueIndex:idx_news_summary"`
+	Summary string `json:"summary" gorm:"type:text;not null"`
+
+	News      News        `json:"-" gorm:"foreignKey:NewsID,MediaID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	ClaimList []NewsClaim `json:"claim_list" gorm:"foreignKey:SummaryID"`
+}
+
+// NewsClaim 新聞中可查核的事實陳述與內容中的原文.
+type NewsClaim struct {
+	utils.TimeModel
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	SummaryID uint   `json:"summary_id" gorm:"not null;index"`
+	NewsID    string `json:"news_id" gorm:"type:char(36);not null;index:idx_news_claim"`
+	MediaID   uint   `json:"media_id" gorm:"not null;index:idx_news_claim"`
+	Seq       int    `json:"seq" gorm:"not null"` // 於新聞中的順序, 從 1 開始
+	Claim     string `json:"claim" gorm:"type:text;not null"`
+	Quote     string `json:"quote" gorm:"type:text;not null"`
+	// QuoteStart, QuoteEnd 引文在 news.content 的字元位置 (end 不含), 模型改寫引文而找不到時為 null
+	QuoteStart *int `json:"quote_start"`
+	QuoteEnd   *int `json:"quote_end"`
+
+	Summary NewsSummary `json:"-" gorm:"foreignKey:SummaryID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	News    News        `json:"-" gorm:"foreignKey:NewsID,MediaID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"itmrchow/tw-media-analytics-service/domain/utils"
+)
+
+// Story 多家媒體對同一事件的報導, 由分群排程依標題與內容相似度產生.
+type Story struct {
+	utils.TimeModel
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	Title            string    `json:"title" gorm:"type:varchar(255);not null"` // 最早報導的標題
+	FirstPublishedAt time.Time `json:"first_published_at" gorm:"not null;index"`
+	LastPublishedAt  time.Time `json:"last_published_at" gorm:"not null"`
+	ArticleCount     int       `json:"article_count" gorm:"not null"`
+	MediaCount       int       `json:"media_count" gorm:"not null;index"`
+
+	ArticleList []StoryArticle `json:"-" gorm:"foreignKey:StoryID"`
+}
+
+// StoryArticle 事件包含的新聞, 每篇新聞只屬於一個事件.
+type StoryArticle struct {
+	utils.TimeModel
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	StoryID uint   `json:"story_id" gorm:"not null;index"`
+	NewsID  string `json:"news_id" gorm:"type:char(36);not null;uniqueIndex:idx_story_article_news"`
+	MediaID uint   `json:"media_id" gorm:"not null;uniqueIndex:idx_story_article_news"`
+	// Similarity 加入事件時與最相似新聞的相似度, 事件的第一篇為 1
+	Similarity float64 `json:"similarity" gorm:"not null"`
+
+	Story Story `json:"-" gorm:"foreignKey:StoryID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	News  News  `json:"-" gorm:"foreignKey:NewsID,MediaID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// StoryArticleRow 事件內單篇新聞與其標題, 內容分析的總分.
+type StoryArticleRow struct {
+	StoryID      uint
+	NewsID       string
+	MediaID      uint
+	MediaName    string
+	Title        string
+	URL          string
+	PublishedAt  time.Time
+	Similarity   float64
+	TitleScore   decimal.NullDecimal // 尚未分析時為 null
+	ContentScore decimal.NullDecimal // 尚未分析時為 null
+}
+
+// StoryCandidateRow 待分群的新聞與已歸入的事件.
+type StoryCandidateRow struct {
+	NewsID      string
+	MediaID     uint
+	Title       string
+	Content     string
+	PublishedAt time.Time
+	StoryID     uint // 尚未分群時為 0
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
@@ -33,16 +35,31 @@ func NewAnalysisRepositoryImpl(logger *zerolog.Logger, db *gorm.DB) *AnalysisRep
 	}
 }
 
-// SaveAnalysisList saves a list of analysis results in a transaction
+// WithTransaction returns a copy of the repository that uses tx
 //
 // Args:
 //
+//	tx: gorm transaction
+//
+// Returns:
+//
+//	AnalysisRepository: transaction scoped repository
+func (r *AnalysisRepositoryImpl) WithTransaction(tx *gorm.DB) AnalysisRepository {
+	return NewAnalysisRepositoryImpl(r.logger, tx)
+}
+
+// SaveAnalysisList saves a list of analysis results in a transaction,
+// the generated IDs are written back to analysisList
+//
+// Args:
+//
+//	ctx: context for the query
 //	analysisList: slice of Analysis entities to save
 //
 // Returns:
 //
 //	error: error if any occurred during the save operation
-func (r *AnalysisRepositoryImpl) SaveAnalysisList(analysisList []entity.Analysis) error {
+func (r *AnalysisRepositoryImpl) SaveAnalysisList(ctx context.Context, analysisList []entity.Analysis) error {
 	r.logger.Debug().Int("count", len(analysisList)).Msg("saving analysis list")
 
 	if len(analysisList) == 0 {
@@ -50,15 +67,18 @@ func (r *AnalysisRepositoryImpl) SaveAnalysisList(analysisList []entity.Analysis
 	}
 
 	// Use transaction to ensure data consistency
-	err := r.db.Transaction(func(tx *gorm.DB) error {
-		for _, analysis := range analysisList {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range analysisList {
+			// 以指標存取, 讓呼叫端取得新增後的 ID
+			analysis := &analysisList[i]
+
 			// Save analysis with its associations
-			if err := tx.Create(&analysis).Error; err != nil {
+			if err := tx.Create(analysis).Error; err != nil {
 				return fmt.Errorf("failed to create analysis: %w", err)
 			}
 
 			// Save metrics using associations
-			if err := tx.Model(&analysis).Association("AnalysisMetricsList").Replace(analysis.AnalysisMetricsList); err != nil {
+			if err := tx.Model(analysis).Association("AnalysisMetricsList").Replace(analysis.AnalysisMetricsList); err != nil {
 				return fmt.Errorf("failed to save analysis metrics: %w", err)
 			}
 		}
@@ -66,10 +86,87 @@ func (r *AnalysisRepositoryImpl) SaveAnalysisList(analysisList []entity.Analysis
 	})
 
 	if err != nil {
-		r.logger.Error().Err(err).Msg("failed to save analysis list")
+		r.logger.Error().Err(err).Ctx(ctx).Msg("failed to save analysis list")
 		return fmt.Errorf("failed to save analysis list: %w", err)
 	}
 
 	r.logger.Debug().Msg("successfully saved analysis list")
 	return nil
 }
+
+// SumScoresByCategory sums analysis scores of news published in [from, to)
+// grouped by unified category, media and analysis type, only quality analysis types are included
+//
+// Args:
+//
+//	ctx: context for the query
+//	from: inclusive start of published time
+//	to: exclusive end of published time
+//
+// Returns:
+//
+//	[]*entity.CategoryScoreSummary: summaries ordered by category, media and type
+//	error: error if any occurred during the query
+func (r *AnalysisRepositoryImpl) SumScoresByCategory(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) ([]*entity.CategoryScoreSummary, error) {
+	var summaries []*entity.CategoryScoreSummary
+
+	result := r.db.WithContext(ctx).
+		Model(&entity.Analysis{}).
+		Select("news.category_key AS category_key, analyses.media_id AS media_id, analyses.type AS type, "+
+			"COUNT(*) AS count, AVG(analyses.score) AS avg_score").
+		Joins("JOIN news ON news.news_id = analyses.news_id AND news.media_id = analyses.media_id").
+		Where("news.published_at >= ? AND news.published_at < ?", from, to).
+		Where("analyses.type IN ?", entity.QualityAnalysisTypes).
+		Group("news.category_key, analyses.media_id, analyses.type").
+		Order("news.category_key, analyses.media_id, analyses.type").
+		Scan(&summaries)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to sum scores by category: %w", result.Error)
+	}
+
+	return summaries, nil
+}
+
+// SumFramingByMedia averages framing metric scores of news published in [from, to)
+// grouped by media and metric key
+//
+// Args:
+//
+//	ctx: context for the query
+//	from: inclusive start of published time
+//	to: exclusive end of published time
+//
+// Returns:
+//
+//	[]*entity.FramingSummary: summaries ordered by media and metric key
+//	error: error if any occurred during the query
+func (r *AnalysisRepositoryImpl) SumFramingByMedia(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) ([]*entity.FramingSummary, error) {
+	var summaries []*entity.FramingSummary
+
+	result := r.db.WithContext(ctx).
+		Model(&entity.AnalysisMetric{}).
+		Select("analyses.media_id AS media_id, analysis_metrics.metric_key AS metric_key, "+
+			"COUNT(*) AS count, AVG(analysis_metrics.score) AS avg_score").
+		Joins("JOIN analyses ON analyses.id = analysis_metrics.analysis_id AND analyses.deleted_at IS NULL").
+		Joins("JOIN news ON news.news_id = analyses.news_id AND news.media_id = analyses.media_id").
+		Where("analyses.type = ?", entity.AnalysisTypeFraming).
+		Where("news.published_at >= ? AND news.published_at < ?", from, to).
+		Group("analyses.media_id, analysis_metrics.metric_key").
+		Order("analyses.media_id, analysis_metrics.metric_key").
+		Scan(&summaries)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to sum framing by media: %w", result.Error)
+	}
+
+	return summaries, nil
+}
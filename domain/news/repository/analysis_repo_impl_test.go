@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/go-testfixtures/testfixtures/v3"
 	"github.com/rs/zerolog"
@@ -13,8 +14,8 @@ import (
 	"gorm.io/gorm"
 
 	"itmrchow/tw-media-analytics-service/domain/news/entity"
-	"itmrchow/tw-media-analytics-service/domain/utils/db"
 	"itmrchow/tw-media-analytics-service/infra"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
 )
 
 func TestAnalysisRepoSuite(t *testing.T) {
@@ -36,7 +37,7 @@ func (s *AnalysisTestSuite) SetupTest() {
 	infra.SetInfraLogger(&logger)
 
 	s.log = &logger
-	s.db = db.NewSqliteDB(context.Background(), &logger, tracer)
+	s.db = testdb.New(context.Background(), &logger, tracer)
 
 	sqlDB, err := s.db.DB()
 	s.Require().NoError(err)
@@ -44,7 +45,7 @@ func (s *AnalysisTestSuite) SetupTest() {
 	// init test data
 	fixtures, err := testfixtures.New(
 		testfixtures.Database(sqlDB),
-		testfixtures.Dialect("sqlite"),
+		testfixtures.Dialect(s.db.Dialector.Name()),
 		testfixtures.Directory("testdata"),
 		testfixtures.DangerousSkipTestDatabaseCheck(),
 	)
@@ -109,7 +110,7 @@ func (s *AnalysisTestSuite) TestSaveAnalysisList() {
 
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			err := s.analysisRepo.SaveAnalysisList(tt.analysisList)
+			err := s.analysisRepo.SaveAnalysisList(context.Background(), tt.analysisList)
 			if tt.wantErr {
 				s.Error(err)
 				return
@@ -150,3 +151,80 @@ func (s *AnalysisTestSuite) TestSaveAnalysisList() {
 		})
 	}
 }
+
+func (s *AnalysisTestSuite) TestSumScoresByCategory() {
+	// 三立的另一篇政治新聞與一篇未發布於期間內的新聞
+	s.Require().NoError(s.db.Create(&entity.News{
+		NewsID: "2", MediaID: 2, Title: "test news 2", Content: "test content 2", URL: "https://test.com/news/2",
+		AuthorID: 1, CategoryKey: "politics", PublishedAt: time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC),
+	}).Error)
+	s.Require().NoError(s.db.Create(&entity.News{
+		NewsID: "3", MediaID: 2, Title: "test news 3", Content: "test content 3", URL: "https://test.com/news/3",
+		AuthorID: 1, CategoryKey: "politics", PublishedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+	}).Error)
+
+	s.Require().NoError(s.analysisRepo.SaveAnalysisList(context.Background(), []entity.Analysis{
+		{NewsID: "1", MediaID: 1, Type: entity.AnalysisTypeTitle, Score: decimal.NewFromFloat(4), Reason: "r"},
+		{NewsID: "1", MediaID: 1, Type: entity.AnalysisTypeContent, Score: decimal.NewFromFloat(3), Reason: "r"},
+		{NewsID: "2", MediaID: 2, Type: entity.AnalysisTypeTitle, Score: decimal.NewFromFloat(2), Reason: "r"},
+		{NewsID: "3", MediaID: 2, Type: entity.AnalysisTypeTitle, Score: decimal.NewFromFloat(5), Reason: "r"},
+		// 框架分析不計入品質分數
+		{NewsID: "1", MediaID: 1, Type: entity.AnalysisTypeFraming, Score: decimal.NewFromFloat(1), Reason: "r"},
+	}))
+
+	summaries, err := s.analysisRepo.SumScoresByCategory(
+		context.Background(),
+		time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+	)
+	s.Require().NoError(err)
+	s.Equal([]*entity.CategoryScoreSummary{
+		{CategoryKey: "politics", MediaID: 1, Type: entity.AnalysisTypeContent, Count: 1, AvgScore: 3},
+		{CategoryKey: "politics", MediaID: 1, Type: entity.AnalysisTypeTitle, Count: 1, AvgScore: 4},
+		{CategoryKey: "politics", MediaID: 2, Type: entity.AnalysisTypeTitle, Count: 1, AvgScore: 2},
+	}, summaries)
+}
+
+func (s *AnalysisTestSuite) TestSumFramingByMedia() {
+	s.Require().NoError(s.db.Create(&entity.News{
+		NewsID: "2", MediaID: 2, Title: "test news 2", Content: "test content 2", URL: "https://test.com/news/2",
+		AuthorID: 1, PublishedAt: time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC),
+	}).Error)
+	s.Require().NoError(s.db.Create(&entity.News{
+		NewsID: "3", MediaID: 2, Title: "test news 3", Content: "test content 3", URL: "https://test.com/news/3",
+		AuthorID: 1, PublishedAt: time.Date(2021, 1, 1, 18, 0, 0, 0, time.UTC),
+	}).Error)
+
+	framing := func(newsID string, mediaID uint, stance float64, tone float64) entity.Analysis {
+		return entity.Analysis{
+			NewsID: newsID, MediaID: mediaID, Type: entity.AnalysisTypeFraming, Score: decimal.NewFromFloat(2), Reason: "r",
+			AnalysisMetricsList: []entity.AnalysisMetric{
+				{MetricKey: string(entity.MetricKeyFramingStanceKMT), Score: decimal.NewFromFloat(stance), Reason: "r"},
+				{MetricKey: string(entity.MetricKeyFramingTone), Score: decimal.NewFromFloat(tone), Reason: "r"},
+			},
+		}
+	}
+	s.Require().NoError(s.analysisRepo.SaveAnalysisList(context.Background(), []entity.Analysis{
+		framing("1", 1, 1.5, -1),
+		framing("2", 2, -2, 0),
+		framing("3", 2, -1, -1),
+		// 品質評分的指標不計入
+		{NewsID: "1", MediaID: 1, Type: entity.AnalysisTypeContent, Score: decimal.NewFromFloat(3), Reason: "r",
+			AnalysisMetricsList: []entity.AnalysisMetric{
+				{MetricKey: string(entity.MetricKeyContentObjectivity), Score: decimal.NewFromFloat(3), Reason: "r"},
+			}},
+	}))
+
+	summaries, err := s.analysisRepo.SumFramingByMedia(
+		context.Background(),
+		time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+	)
+	s.Require().NoError(err)
+	s.Equal([]*entity.FramingSummary{
+		{MediaID: 1, MetricKey: "stance_kmt", Count: 1, AvgScore: 1.5},
+		{MediaID: 1, MetricKey: "tone", Count: 1, AvgScore: -1},
+		{MediaID: 2, MetricKey: "stance_kmt", Count: 2, AvgScore: -1.5},
+		{MediaID: 2, MetricKey: "tone", Count: 2, AvgScore: -0.5},
+	}, summaries)
+}
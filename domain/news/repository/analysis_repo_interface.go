@@ -1,9 +1,21 @@
 package repository
 
-import "itmrchow/tw-media-analytics-service/domain/news/entity"
+import (
+	"context"
+	"time"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
 
 type AnalysisRepository interface {
+	BaseRepository[AnalysisRepository]
 	// SaveAnalysis(analysis *entity.Analysis) error
 
-	SaveAnalysisList(analysisList []entity.Analysis) error
+	SaveAnalysisList(ctx context.Context, analysisList []entity.Analysis) error
+
+	// SumScoresByCategory 依統一分類, 媒體與分析類型彙總 [from, to) 期間發布的新聞分數
+	SumScoresByCategory(ctx context.Context, from time.Time, to time.Time) ([]*entity.CategoryScoreSummary, error)
+
+	// SumFramingByMedia 依媒體與框架指標彙總 [from, to) 期間發布的新聞框架分析
+	SumFramingByMedia(ctx context.Context, from time.Time, to time.Time) ([]*entity.FramingSummary, error)
 }
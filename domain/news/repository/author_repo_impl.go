@@ -23,8 +23,7 @@ func NewAuthorRepositoryImpl(logger *zerolog.Logger, db *gorm.DB) *AuthorReposit
 }
 
 func (r *AuthorRepositoryImpl) WithTransaction(tx *gorm.DB) AuthorRepository {
-	r.db = tx
-	return r
+	return NewAuthorRepositoryImpl(r.logger, tx)
 }
 
 func (r *AuthorRepositoryImpl) FirstOrCreate(ctx context.Context, author *entity.Author) error {
@@ -11,8 +11,8 @@ import (
 	"go.opentelemetry.io/otel"
 
 	"itmrchow/tw-media-analytics-service/domain/news/entity"
-	"itmrchow/tw-media-analytics-service/domain/utils/db"
 	"itmrchow/tw-media-analytics-service/infra"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
 )
 
 func TestAuthorRepoSuite(t *testing.T) {
@@ -31,7 +31,7 @@ func (s *AuthorTestSuite) SetupTest() {
 	logger := zerolog.New(os.Stdout).Level(zerolog.DebugLevel)
 	infra.SetInfraLogger(&logger)
 
-	db := db.NewSqliteDB(context.Background(), &logger, tracer)
+	db := testdb.New(context.Background(), &logger, tracer)
 
 	sqlDB, err := db.DB()
 	s.Require().NoError(err)
@@ -39,7 +39,7 @@ func (s *AuthorTestSuite) SetupTest() {
 	// 初始化測試資料
 	fixtures, _ := testfixtures.New(
 		testfixtures.Database(sqlDB),
-		testfixtures.Dialect("sqlite"),
+		testfixtures.Dialect(db.Dialector.Name()),
 		testfixtures.Directory("testdata"),
 		testfixtures.DangerousSkipTestDatabaseCheck(),
 	)
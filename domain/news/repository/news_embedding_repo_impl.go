@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+var _ NewsEmbeddingRepository = &NewsEmbeddingRepositoryImpl{}
+
+type NewsEmbeddingRepositoryImpl struct {
+	logger *zerolog.Logger
+	db     *gorm.DB
+}
+
+func NewNewsEmbeddingRepositoryImpl(logger *zerolog.Logger, db *gorm.DB) *NewsEmbeddingRepositoryImpl {
+	return &NewsEmbeddingRepositoryImpl{
+		logger: logger,
+		db:     db,
+	}
+}
+
+func (r *NewsEmbeddingRepositoryImpl) FindNewsWithoutEmbedding(
+	ctx context.Context,
+	model string,
+	limit int,
+) ([]*entity.News, error) {
+	var news []*entity.News
+
+	err := r.db.WithContext(ctx).
+		Model(&entity.News{}).
+		Joins("LEFT JOIN news_embeddings ON news_embeddings.news_id = news.news_id "+
+			"AND news_embeddings.media_id = news.media_id AND news_embeddings.model = ? "+
+			"AND news_embeddings.deleted_at IS NULL", model).
+		Where("news_embeddings.id IS NULL").
+		Order("news.published_at DESC").
+		Limit(limit).
+		Find(&news).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find news without embedding: %w", err)
+	}
+
+	return news, nil
+}
+
+func (r *NewsEmbeddingRepositoryImpl) SaveEmbeddings(ctx context.Context, embeddingList []entity.NewsEmbedding) error {
+	if len(embeddingList) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "news_id"}, {Name: "media_id"}, {Name: "model"}},
+			DoUpdates: clause.AssignmentColumns([]string{"dimensions", "vector", "updated_at"}),
+		}).
+		Create(&embeddingList).Error
+	if err != nil {
+		return fmt.Errorf("failed to save news embeddings: %w", err)
+	}
+
+	return nil
+}
+
+func (r *NewsEmbeddingRepositoryImpl) GetEmbedding(
+	ctx context.Context,
+	newsID string,
+	mediaID uint,
+	model string,
+) (*entity.NewsEmbedding, error) {
+	var e entity.NewsEmbedding
+
+	err := r.db.WithContext(ctx).
+		Where(&entity.NewsEmbedding{NewsID: newsID, MediaID: mediaID, Model: model}).
+		First(&e).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get news embedding: %w", err)
+	}
+
+	return &e, nil
+}
+
+func (r *NewsEmbeddingRepositoryImpl) FindEmbeddings(
+	ctx context.Context,
+	model string,
+	filter entity.EmbeddingFilter,
+) ([]*entity.NewsEmbeddingRow, error) {
+	var rows []*entity.NewsEmbeddingRow
+
+	query := r.db.WithContext(ctx).
+		Model(&entity.NewsEmbedding{}).
+		Select("news_embeddings.news_id AS news_id, news_embeddings.media_id AS media_id, news.title AS title, "+
+			"news.url AS url, news.published_at AS published_at, news_embeddings.vector AS vector").
+		Joins("JOIN news ON news.news_id = news_embeddings.news_id AND news.media_id = news_embeddings.media_id").
+		Where("news_embeddings.model = ?", model)
+	if len(filter.MediaIDList) > 0 {
+		query = query.Where("news_embeddings.media_id IN ?", filter.MediaIDList)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("news.published_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("news.published_at < ?", filter.To)
+	}
+
+	if err := query.Order("news.published_at DESC").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to find news embeddings: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+type NewsEmbeddingRepository interface {
+	// FindNewsWithoutEmbedding 找出尚未以 model 計算向量的新聞, 依發布時間新到舊
+	FindNewsWithoutEmbedding(ctx context.Context, model string, limit int) ([]*entity.News, error)
+	// SaveEmbeddings 儲存向量, 同一新聞與模型已存在時覆寫
+	SaveEmbeddings(ctx context.Context, embeddingList []entity.NewsEmbedding) error
+	// GetEmbedding 取得新聞以 model 計算的向量, 不存在時回傳 nil
+	GetEmbedding(ctx context.Context, newsID string, mediaID uint, model string) (*entity.NewsEmbedding, error)
+	// FindEmbeddings 找出符合條件的新聞與 model 計算的向量
+	FindEmbeddings(ctx context.Context, model string, filter entity.EmbeddingFilter) ([]*entity.NewsEmbeddingRow, error)
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-testfixtures/testfixtures/v3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
+)
+
+func TestNewsEmbeddingRepoSuite(t *testing.T) {
+	suite.Run(t, new(NewsEmbeddingTestSuite))
+}
+
+type NewsEmbeddingTestSuite struct {
+	suite.Suite
+	embeddingRepo NewsEmbeddingRepository
+	db            *gorm.DB
+}
+
+func (s *NewsEmbeddingTestSuite) SetupTest() {
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	logger := zerolog.New(os.Stdout).Level(zerolog.InfoLevel)
+
+	s.db = testdb.New(context.Background(), &logger, tracer)
+
+	sqlDB, err := s.db.DB()
+	s.Require().NoError(err)
+
+	fixtures, err := testfixtures.New(
+		testfixtures.Database(sqlDB),
+		testfixtures.Dialect(s.db.Dialector.Name()),
+		testfixtures.Directory("testdata"),
+		testfixtures.DangerousSkipTestDatabaseCheck(),
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(fixtures.Load())
+
+	s.embeddingRepo = NewNewsEmbeddingRepositoryImpl(&logger, s.db)
+
+	// 新聞 1 (中天) 發布於 2021-01-01 00:00, 新聞 2 (三立) 發布於 2021-01-02
+	s.Require().NoError(s.db.Create(&entity.News{
+		NewsID: "2", MediaID: 2, Title: "test news 2", Content: "test content 2", URL: "https://test.com/news/2",
+		AuthorID: 1, PublishedAt: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+	}).Error)
+}
+
+func (s *NewsEmbeddingTestSuite) TestSaveAndFind() {
+	ctx := context.Background()
+
+	news, err := s.embeddingRepo.FindNewsWithoutEmbedding(ctx, "m1", 10)
+	s.Require().NoError(err)
+	s.Require().Len(news, 2)
+	s.Equal("2", news[0].NewsID)
+
+	s.Require().NoError(s.embeddingRepo.SaveEmbeddings(ctx, []entity.NewsEmbedding{
+		{NewsID: "1", MediaID: 1, Model: "m1", Dimensions: 1, Vector: []byte{1, 2, 3, 4}},
+		{NewsID: "2", MediaID: 2, Model: "m1", Dimensions: 1, Vector: []byte{5, 6, 7, 8}},
+		{NewsID: "1", MediaID: 1, Model: "m2", Dimensions: 1, Vector: []byte{9, 9, 9, 9}},
+	}))
+
+	// 重新計算時覆寫
+	s.Require().NoError(s.embeddingRepo.SaveEmbeddings(ctx, []entity.NewsEmbedding{
+		{NewsID: "1", MediaID: 1, Model: "m1", Dimensions: 2, Vector: []byte{0, 0, 0, 0, 1, 1, 1, 1}},
+	}))
+
+	news, err = s.embeddingRepo.FindNewsWithoutEmbedding(ctx, "m1", 10)
+	s.Require().NoError(err)
+	s.Empty(news)
+	news, err = s.embeddingRepo.FindNewsWithoutEmbedding(ctx, "m2", 10)
+	s.Require().NoError(err)
+	s.Require().Len(news, 1)
+	s.Equal("2", news[0].NewsID)
+
+	e, err := s.embeddingRepo.GetEmbedding(ctx, "1", 1, "m1")
+	s.Require().NoError(err)
+	s.Require().NotNil(e)
+	s.Equal(2, e.Dimensions)
+	s.Equal([]byte{0, 0, 0, 0, 1, 1, 1, 1}, e.Vector)
+
+	e, err = s.embeddingRepo.GetEmbedding(ctx, "2", 2, "m2")
+	s.Require().NoError(err)
+	s.Nil(e)
+}
+
+func (s *NewsEmbeddingTestSuite) TestFindEmbeddings() {
+	ctx := context.Background()
+
+	s.Require().NoError(s.embeddingRepo.SaveEmbeddings(ctx, []entity.NewsEmbedding{
+		{NewsID: "1", MediaID: 1, Model: "m1", Dimensions: 1, Vector: []byte{1, 2, 3, 4}},
+		{NewsID: "2", MediaID: 2, Model: "m1", Dimensions: 1, Vector: []byte{5, 6, 7, 8}},
+		{NewsID: "2", MediaID: 2, Model: "m2", Dimensions: 1, Vector: []byte{9, 9, 9, 9}},
+	}))
+
+	rows, err := s.embeddingRepo.FindEmbeddings(ctx, "m1", entity.EmbeddingFilter{})
+	s.Require().NoError(err)
+	s.Require().Len(rows, 2)
+	s.Equal("2", rows[0].NewsID)
+	s.Equal("https://test.com/news/2", rows[0].URL)
+	s.Equal([]byte{5, 6, 7, 8}, rows[0].Vector)
+
+	rows, err = s.embeddingRepo.FindEmbeddings(ctx, "m1", entity.EmbeddingFilter{MediaIDList: []uint{1}})
+	s.Require().NoError(err)
+	s.Require().Len(rows, 1)
+	s.Equal("1", rows[0].NewsID)
+
+	rows, err = s.embeddingRepo.FindEmbeddings(ctx, "m1", entity.EmbeddingFilter{
+		From: time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC),
+		To:   time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+	})
+	s.Require().NoError(err)
+	s.Require().Len(rows, 1)
+	s.Equal("2", rows[0].NewsID)
+}
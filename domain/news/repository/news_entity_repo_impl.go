@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+var _ NewsEntityRepository = &NewsEntityRepositoryImpl{}
+
+type NewsEntityRepositoryImpl struct {
+	logger *zerolog.Logger
+	db     *gorm.DB
+}
+
+func NewNewsEntityRepositoryImpl(logger *zerolog.Logger, db *gorm.DB) *NewsEntityRepositoryImpl {
+	return &NewsEntityRepositoryImpl{
+		logger: logger,
+		db:     db,
+	}
+}
+
+func (r *NewsEntityRepositoryImpl) ReplaceNewsEntities(
+	ctx context.Context,
+	newsID string,
+	mediaID uint,
+	entityList []entity.NewsEntity,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Where(&entity.NewsEntity{NewsID: newsID, MediaID: mediaID}).
+			Delete(&entity.NewsEntity{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete news entities: %w", err)
+		}
+
+		if len(entityList) == 0 {
+			return nil
+		}
+
+		for i := range entityList {
+			entityList[i].NewsID = newsID
+			entityList[i].MediaID = mediaID
+		}
+		if err = tx.Create(&entityList).Error; err != nil {
+			return fmt.Errorf("failed to create news entities: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *NewsEntityRepositoryImpl) FindEntityCoverage(
+	ctx context.Context,
+	entityID string,
+	analysisType entity.AnalysisType,
+	from time.Time,
+	to time.Time,
+) ([]*entity.EntityCoverageRow, error) {
+	var rows []*entity.EntityCoverageRow
+
+	result := r.db.WithContext(ctx).
+		Model(&entity.NewsEntity{}).
+		Select("news_entities.news_id AS news_id, news_entities.media_id AS media_id, "+
+			"news.published_at AS published_at, analysis_metrics.score AS objectivity").
+		Joins("JOIN news ON news.news_id = news_entities.news_id AND news.media_id = news_entities.media_id").
+		Joins("LEFT JOIN analyses ON analyses.news_id = news.news_id AND analyses.media_id = news.media_id "+
+			"AND analyses.type = ? AND analyses.deleted_at IS NULL", analysisType).
+		Joins("LEFT JOIN analysis_metrics ON analysis_metrics.analysis_id = analyses.id "+
+			"AND analysis_metrics.metric_key = ? AND analysis_metrics.deleted_at IS NULL", entity.MetricKeyContentObjectivity).
+		Where("news_entities.entity_id = ?", entityID).
+		Where("news.published_at >= ? AND news.published_at < ?", from, to).
+		Order("news.published_at, news_entities.media_id").
+		Scan(&rows)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find entity coverage: %w", result.Error)
+	}
+
+	return rows, nil
+}
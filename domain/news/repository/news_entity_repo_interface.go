@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+type NewsEntityRepository interface {
+	// ReplaceNewsEntities 以 entityList 取代單篇新聞已儲存的實體, 重新擷取時不會留下舊資料
+	ReplaceNewsEntities(ctx context.Context, newsID string, mediaID uint, entityList []entity.NewsEntity) error
+	// FindEntityCoverage 找出 [from, to) 期間發布且提及 entityID 的新聞, 與 analysisType 分析的客觀性分數
+	FindEntityCoverage(
+		ctx context.Context,
+		entityID string,
+		analysisType entity.AnalysisType,
+		from time.Time,
+		to time.Time,
+	) ([]*entity.EntityCoverageRow, error)
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-testfixtures/testfixtures/v3"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
+)
+
+func TestNewsEntityRepoSuite(t *testing.T) {
+	suite.Run(t, new(NewsEntityTestSuite))
+}
+
+type NewsEntityTestSuite struct {
+	suite.Suite
+	entityRepo   NewsEntityRepository
+	analysisRepo AnalysisRepository
+	db           *gorm.DB
+}
+
+func (s *NewsEntityTestSuite) SetupTest() {
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	logger := zerolog.New(os.Stdout).Level(zerolog.InfoLevel)
+
+	s.db = testdb.New(context.Background(), &logger, tracer)
+
+	sqlDB, err := s.db.DB()
+	s.Require().NoError(err)
+
+	fixtures, err := testfixtures.New(
+		testfixtures.Database(sqlDB),
+		testfixtures.Dialect(s.db.Dialector.Name()),
+		testfixtures.Directory("testdata"),
+		testfixtures.DangerousSkipTestDatabaseCheck(),
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(fixtures.Load())
+
+	s.entityRepo = NewNewsEntityRepositoryImpl(&logger, s.db)
+	s.analysisRepo = NewAnalysisRepositoryImpl(&logger, s.db)
+}
+
+func (s *NewsEntityTestSuite) TestReplaceNewsEntities() {
+	ctx := context.Background()
+
+	s.Require().NoError(s.entityRepo.ReplaceNewsEntities(ctx, "1", 1, []entity.NewsEntity{
+		{EntityID: "kmt", EntityName: "國民黨", EntityType: "party", Mentions: 2, InTitle: true, Aliases: "國民黨"},
+		{EntityID: "dpp", EntityName: "民進黨", EntityType: "party", Mentions: 1, Aliases: "民進黨"},
+	}))
+
+	// 重新擷取時取代舊資料
+	s.Require().NoError(s.entityRepo.ReplaceNewsEntities(ctx, "1", 1, []entity.NewsEntity{
+		{EntityID: "kmt", EntityName: "國民黨", EntityType: "party", Mentions: 3, Aliases: "國民黨,中國國民黨"},
+	}))
+
+	var saved []entity.NewsEntity
+	s.Require().NoError(s.db.Find(&saved).Error)
+	s.Require().Len(saved, 1)
+	s.Equal("kmt", saved[0].EntityID)
+	s.Equal("1", saved[0].NewsID)
+	s.Equal(uint(1), saved[0].MediaID)
+	s.Equal(3, saved[0].Mentions)
+
+	// 沒有提及任何實體
+	s.Require().NoError(s.entityRepo.ReplaceNewsEntities(ctx, "1", 1, nil))
+	s.Require().NoError(s.db.Find(&saved).Error)
+	s.Empty(saved)
+}
+
+func (s *NewsEntityTestSuite) TestFindEntityCoverage() {
+	ctx := context.Background()
+
+	s.Require().NoError(s.db.Create(&entity.News{
+		NewsID: "2", MediaID: 2, Title: "test news 2", Content: "test content 2", URL: "https://test.com/news/2",
+		AuthorID: 1, PublishedAt: time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC),
+	}).Error)
+	s.Require().NoError(s.entityRepo.ReplaceNewsEntities(ctx, "1", 1, []entity.NewsEntity{
+		{EntityID: "kmt", EntityName: "國民黨", EntityType: "party", Mentions: 1},
+	}))
+	s.Require().NoError(s.entityRepo.ReplaceNewsEntities(ctx, "2", 2, []entity.NewsEntity{
+		{EntityID: "kmt", EntityName: "國民黨", EntityType: "party", Mentions: 1},
+		{EntityID: "dpp", EntityName: "民進黨", EntityType: "party", Mentions: 1},
+	}))
+
+	// 只有新聞 1 已分析
+	s.Require().NoError(s.analysisRepo.SaveAnalysisList(context.Background(), []entity.Analysis{
+		{NewsID: "1", MediaID: 1, Type: entity.AnalysisTypeContent, Score: decimal.NewFromFloat(3), Reason: "r",
+			AnalysisMetricsList: []entity.AnalysisMetric{
+				{MetricKey: "accuracy", Score: decimal.NewFromFloat(4), Reason: "r"},
+				{MetricKey: "objectivity", Score: decimal.NewFromFloat(2.5), Reason: "r"},
+			}},
+	}))
+
+	rows, err := s.entityRepo.FindEntityCoverage(ctx, "kmt", entity.AnalysisTypeContent,
+		time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+	)
+	s.Require().NoError(err)
+	s.Require().Len(rows, 2)
+
+	s.Equal("1", rows[0].NewsID)
+	s.Equal(uint(1), rows[0].MediaID)
+	s.True(rows[0].Objectivity.Valid)
+	s.Equal("2.5", rows[0].Objectivity.Decimal.String())
+
+	s.Equal("2", rows[1].NewsID)
+	s.Equal(uint(2), rows[1].MediaID)
+	s.Equal(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC), rows[1].PublishedAt.UTC())
+	s.False(rows[1].Objectivity.Valid)
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"itmrchow/tw-media-analytics-service/domain/news/entity"
 )
@@ -22,11 +24,10 @@ func NewNewsRepositoryImpl(log *zerolog.Logger, db *gorm.DB) *NewsRepositoryImpl
 }
 
 func (r *NewsRepositoryImpl) WithTransaction(tx *gorm.DB) NewsRepository {
-	r.db = tx
-	return r
+	return NewNewsRepositoryImpl(r.logger, tx)
 }
 
-func (r *NewsRepositoryImpl) FindNonExistingNewsIDs(mediaID uint, newsIDList []string) ([]string, error) {
+func (r *NewsRepositoryImpl) FindNonExistingNewsIDs(ctx context.Context, mediaID uint, newsIDList []string) ([]string, error) {
 	// 先檢查輸入
 	if len(newsIDList) == 0 {
 		return []string{}, nil
@@ -36,10 +37,10 @@ func (r *NewsRepositoryImpl) FindNonExistingNewsIDs(mediaID uint, newsIDList []s
 	existingMap := make(map[string]struct{}, len(newsIDList))
 	var existingNewsIDs []string
 
-	if err := r.db.Model(&entity.News{}).
+	if err := r.db.WithContext(ctx).Model(&entity.News{}).
 		Where("media_id = ? AND news_id IN (?)", mediaID, newsIDList).
 		Pluck("news_id", &existingNewsIDs).Error; err != nil {
-		r.logger.Error().Err(err).Msg("查詢已存在的新聞ID失敗")
+		r.logger.Error().Err(err).Ctx(ctx).Msg("查詢已存在的新聞ID失敗")
 		return nil, err
 	}
 
@@ -59,18 +60,27 @@ func (r *NewsRepositoryImpl) FindNonExistingNewsIDs(mediaID uint, newsIDList []s
 	return nonExistingNewsIDs, nil
 }
 
-func (r *NewsRepositoryImpl) SaveNews(news *entity.News) error {
-	return r.db.Save(news).Error
+// SaveNews 新增新聞, 已存在時 (例如重新解析) 只更新解析出的欄位, 保留 created_at.
+func (r *NewsRepositoryImpl) SaveNews(ctx context.Context, news *entity.News) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "news_id"}, {Name: "media_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"title", "content", "url", "author_id", "category", "published_at",
+				"category_key", "category_source", "updated_at",
+			}),
+		}).
+		Create(news).Error
 }
 
-func (r *NewsRepositoryImpl) FindNonAnalysisNews(analysisNum uint) ([]*entity.News, error) {
+func (r *NewsRepositoryImpl) FindNonAnalysisNews(ctx context.Context, analysisNum uint) ([]*entity.News, error) {
 	var news []*entity.News
 	// 使用左連接查詢沒有 analysis 的新聞
-	result := r.db.
+	result := r.db.WithContext(ctx).
 		Model(&entity.News{}).
 		Joins("LEFT JOIN analyses ON analyses.news_id = news.news_id AND analyses.media_id = news.media_id").
 		Where("analyses.id IS NULL").
-		Order("published_at ASC").
+		Order("news.published_at ASC").
 		Limit(int(analysisNum)).
 		Find(&news)
 
@@ -81,6 +91,73 @@ func (r *NewsRepositoryImpl) FindNonAnalysisNews(analysisNum uint) ([]*entity.Ne
 	return news, nil
 }
 
-func (n *NewsRepositoryImpl) FirstOrCreate(ctx context.Context, author *entity.Author) error {
-	panic("TODO: Implement")
+func (r *NewsRepositoryImpl) CountNonAnalysisNews(ctx context.Context) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).
+		Model(&entity.News{}).
+		Joins("LEFT JOIN analyses ON analyses.news_id = news.news_id AND analyses.media_id = news.media_id").
+		Where("analyses.id IS NULL").
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count non analysis news: %w", result.Error)
+	}
+
+	return count, nil
+}
+
+func (r *NewsRepositoryImpl) FindLastCrawledAt(ctx context.Context) (map[uint]time.Time, error) {
+	var mediaIDs []uint
+	if err := r.db.WithContext(ctx).Model(&entity.Media{}).Order("id").Pluck("id", &mediaIDs).Error; err != nil {
+		r.logger.Error().Err(err).Ctx(ctx).Msg("查詢媒體列表失敗")
+		return nil, err
+	}
+
+	lastCrawledAt := make(map[uint]time.Time, len(mediaIDs))
+	for _, mediaID := range mediaIDs {
+		var news []entity.News
+		if err := r.db.WithContext(ctx).Model(&entity.News{}).
+			Select("created_at").
+			Where("media_id = ?", mediaID).
+			Order("created_at DESC").
+			Limit(1).
+			Find(&news).Error; err != nil {
+			r.logger.Error().Err(err).Ctx(ctx).Uint("media_id", mediaID).Msg("查詢最後寫入時間失敗")
+			return nil, err
+		}
+
+		lastCrawledAt[mediaID] = time.Time{}
+		if len(news) > 0 {
+			lastCrawledAt[mediaID] = news[0].CreatedAt
+		}
+	}
+
+	return lastCrawledAt, nil
+}
+
+func (r *NewsRepositoryImpl) FindUncategorizedNews(ctx context.Context, limit int) ([]*entity.News, error) {
+	var news []*entity.News
+	if err := r.db.WithContext(ctx).
+		Where("category_key = ?", "").
+		Order("published_at, news_id, media_id").
+		Limit(limit).
+		Find(&news).Error; err != nil {
+		return nil, fmt.Errorf("failed to find uncategorized news: %w", err)
+	}
+
+	return news, nil
+}
+
+func (r *NewsRepositoryImpl) UpdateCategory(ctx context.Context, news *entity.News) error {
+	if err := r.db.WithContext(ctx).
+		Model(&entity.News{}).
+		Where("news_id = ? AND media_id = ?", news.NewsID, news.MediaID).
+		Updates(map[string]any{
+			"category_key":    news.CategoryKey,
+			"category_source": news.CategorySource,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update news category: %w", err)
+	}
+
+	return nil
 }
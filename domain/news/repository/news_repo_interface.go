@@ -1,6 +1,11 @@
 package repository
 
-import "itmrchow/tw-media-analytics-service/domain/news/entity"
+import (
+	"context"
+	"time"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
 
 type NewsRepository interface {
 	BaseRepository[NewsRepository]
@@ -8,12 +13,21 @@ type NewsRepository interface {
 
 	// FindNonExistingNewsIDs 根據媒體ID和新聞ID列表，找出在資料庫中不存在的新聞ID
 	// Args:
+	//   ctx: context
 	//   mediaID: 媒體ID
 	//   newsIDList: 新聞ID列表
 	// Returns:
 	//   []string: 不存在的新聞ID列表
 	//   error: 錯誤資訊
-	FindNonExistingNewsIDs(mediaID uint, newsIDList []string) ([]string, error)
-	SaveNews(news *entity.News) error
-	FindNonAnalysisNews(analysisNum uint) ([]*entity.News, error)
+	FindNonExistingNewsIDs(ctx context.Context, mediaID uint, newsIDList []string) ([]string, error)
+	SaveNews(ctx context.Context, news *entity.News) error
+	FindNonAnalysisNews(ctx context.Context, analysisNum uint) ([]*entity.News, error)
+	// CountNonAnalysisNews 計算尚未分析的新聞數量
+	CountNonAnalysisNews(ctx context.Context) (int64, error)
+	// FindLastCrawledAt 回傳各媒體最後一篇新聞的寫入時間, 從未寫入的媒體為零值
+	FindLastCrawledAt(ctx context.Context) (map[uint]time.Time, error)
+	// FindUncategorizedNews 取得尚未對應統一分類 (category_key 為空) 的新聞
+	FindUncategorizedNews(ctx context.Context, limit int) ([]*entity.News, error)
+	// UpdateCategory 只更新新聞的統一分類與判斷來源
+	UpdateCategory(ctx context.Context, news *entity.News) error
 }
@@ -4,14 +4,17 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/go-testfixtures/testfixtures/v3"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/suite"
 	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
 
-	"itmrchow/tw-media-analytics-service/domain/utils/db"
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
 	"itmrchow/tw-media-analytics-service/infra"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
 )
 
 func TestNewsRepoSuite(t *testing.T) {
@@ -21,7 +24,7 @@ func TestNewsRepoSuite(t *testing.T) {
 type NewsTestSuite struct {
 	suite.Suite
 	newsRepo NewsRepository
-	// db       *gorm.DB
+	db       *gorm.DB
 }
 
 func (s *NewsTestSuite) SetupTest() {
@@ -31,7 +34,7 @@ func (s *NewsTestSuite) SetupTest() {
 	logger := zerolog.New(os.Stdout).Level(zerolog.DebugLevel)
 	infra.SetInfraLogger(&logger)
 
-	db := db.NewSqliteDB(context.Background(), &logger, tracer)
+	db := testdb.New(context.Background(), &logger, tracer)
 
 	sqlDB, err := db.DB()
 	s.Require().NoError(err)
@@ -40,7 +43,7 @@ func (s *NewsTestSuite) SetupTest() {
 	fixtures, _ := testfixtures.New(
 		testfixtures.Database(sqlDB), // You database connection
 		testfixtures.Dialect(
-			"sqlite",
+			db.Dialector.Name(),
 		), // Available: "postgresql", "timescaledb", "mysql", "mariadb", "sqlite" and "sqlserver"
 		testfixtures.Directory("testdata"), // The directory containing the YAML files
 		testfixtures.DangerousSkipTestDatabaseCheck(),
@@ -49,13 +52,98 @@ func (s *NewsTestSuite) SetupTest() {
 	s.Require().NoError(err)
 
 	s.newsRepo = NewNewsRepositoryImpl(&logger, db)
+	s.db = db
 }
 
 func (s *NewsTestSuite) TestFindNonExistingNewsIDs() {
 	mediaID := uint(1)
 	newsIDList := []string{"1", "2", "3"}
 
-	nonExistingNewsIDs, err := s.newsRepo.FindNonExistingNewsIDs(mediaID, newsIDList)
+	nonExistingNewsIDs, err := s.newsRepo.FindNonExistingNewsIDs(context.Background(), mediaID, newsIDList)
 	s.NoError(err)
 	s.Equal(nonExistingNewsIDs, []string{"2", "3"})
 }
+
+func (s *NewsTestSuite) TestCountNonAnalysisNews() {
+	nonAnalysisNews, err := s.newsRepo.FindNonAnalysisNews(context.Background(), 100)
+	s.Require().NoError(err)
+
+	count, err := s.newsRepo.CountNonAnalysisNews(context.Background())
+	s.NoError(err)
+	s.Equal(int64(len(nonAnalysisNews)), count)
+	s.Positive(count)
+}
+
+func (s *NewsTestSuite) TestSaveNews_Upsert() {
+	ctx := context.Background()
+
+	var before entity.News
+	s.Require().NoError(s.db.First(&before, "news_id = ? AND media_id = ?", "1", 1).Error)
+
+	// 重新解析已存在的新聞
+	publishedAt := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	s.Require().NoError(s.newsRepo.SaveNews(ctx, &entity.News{
+		NewsID:      "1",
+		MediaID:     1,
+		Title:       "reparsed title",
+		Content:     "reparsed content",
+		URL:         "https://test.com/news/1",
+		AuthorID:    1,
+		Category:    "a",
+		CategoryKey: "politics",
+		PublishedAt: publishedAt,
+	}))
+
+	var after entity.News
+	s.Require().NoError(s.db.First(&after, "news_id = ? AND media_id = ?", "1", 1).Error)
+	s.Equal("reparsed title", after.Title)
+	s.Equal("reparsed content", after.Content)
+	s.True(publishedAt.Equal(after.PublishedAt))
+	s.True(before.CreatedAt.Equal(after.CreatedAt), "created_at should be kept")
+	s.False(after.CreatedAt.IsZero())
+	s.True(after.UpdatedAt.After(before.UpdatedAt))
+}
+
+func (s *NewsTestSuite) TestFindLastCrawledAt() {
+	lastCrawledAt, err := s.newsRepo.FindLastCrawledAt(context.Background())
+	s.Require().NoError(err)
+	s.Len(lastCrawledAt, 2)
+	s.True(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).Equal(lastCrawledAt[1]))
+	// 三立尚未有新聞
+	s.True(lastCrawledAt[2].IsZero())
+}
+
+func (s *NewsTestSuite) TestFindUncategorizedNews() {
+	ctx := context.Background()
+
+	// 加入統一分類前儲存的新聞
+	s.Require().NoError(s.newsRepo.SaveNews(ctx, &entity.News{
+		NewsID:      "old",
+		MediaID:     1,
+		Title:       "old news",
+		Content:     "old content",
+		URL:         "https://test.com/news/old",
+		AuthorID:    1,
+		Category:    "政治",
+		PublishedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}))
+
+	newsList, err := s.newsRepo.FindUncategorizedNews(ctx, 10)
+	s.Require().NoError(err)
+	s.Require().Len(newsList, 1)
+	s.Equal("old", newsList[0].NewsID)
+
+	newsList[0].CategoryKey = "politics"
+	newsList[0].CategorySource = "mapping"
+	s.Require().NoError(s.newsRepo.UpdateCategory(ctx, newsList[0]))
+
+	newsList, err = s.newsRepo.FindUncategorizedNews(ctx, 10)
+	s.Require().NoError(err)
+	s.Empty(newsList)
+
+	var news entity.News
+	s.Require().NoError(s.db.First(&news, "news_id = ? AND media_id = ?", "old", 1).Error)
+	s.Equal("politics", news.CategoryKey)
+	s.Equal("mapping", news.CategorySource)
+	s.Equal("old news", news.Title)
+}
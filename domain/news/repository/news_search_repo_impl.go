@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+var _ NewsSearchRepository = &NewsSearchRepositoryImpl{}
+
+// defaultNgramTokenSize MySQL ngram_token_size 的預設值.
+const defaultNgramTokenSize = 2
+
+type NewsSearchRepositoryImpl struct {
+	logger *zerolog.Logger
+	db     *gorm.DB
+	// 短於此長度的詞不在 ngram 全文索引中, 改以 LIKE 比對
+	ngramTokenSize int
+}
+
+func NewNewsSearchRepositoryImpl(logger *zerolog.Logger, db *gorm.DB) *NewsSearchRepositoryImpl {
+	ngramTokenSize := viper.GetInt("MYSQL_NGRAM_TOKEN_SIZE")
+	if ngramTokenSize <= 0 {
+		ngramTokenSize = defaultNgramTokenSize
+	}
+
+	return &NewsSearchRepositoryImpl{
+		logger:         logger,
+		db:             db,
+		ngramTokenSize: ngramTokenSize,
+	}
+}
+
+// SearchNews MySQL 以 ngram 全文索引比對關鍵字並依相關度排序, 短於 ngram_token_size 的詞 (例如「藍」) 改以 LIKE 比對;
+// PostgreSQL 以 ILIKE 比對 (pg_trgm 索引), SQLite 以 LIKE 比對, 皆再依發布時間新到舊排序.
+func (r *NewsSearchRepositoryImpl) SearchNews(
+	ctx context.Context,
+	query entity.NewsSearchQuery,
+) ([]*entity.NewsSearchRow, int64, error) {
+	tx := r.db.WithContext(ctx).
+		Table("news").
+		Where("news.deleted_at IS NULL")
+
+	likeTerms := strings.Fields(query.Keyword)
+	var fullTextTerms []string
+	if r.db.Dialector.Name() == "mysql" {
+		fullTextTerms, likeTerms = splitNgramTerms(likeTerms, r.ngramTokenSize)
+	}
+	fullText := len(fullTextTerms) > 0
+	if fullText {
+		tx = tx.Where("MATCH (news.title, news.content) AGAINST (? IN BOOLEAN MODE)", booleanQuery(fullTextTerms))
+	}
+
+	// PostgreSQL 的 LIKE 區分大小寫, 改以 ILIKE 比對; SQLite 與 MySQL 的 LIKE 本就不區分大小寫.
+	// MySQL 字串中的反斜線需再跳脫
+	like, escape := "LIKE", `'\'`
+	switch r.db.Dialector.Name() {
+	case "postgres":
+		like = "ILIKE"
+	case "mysql":
+		escape = `'\\'`
+	}
+	condition := fmt.Sprintf(`(news.title %[1]s ? ESCAPE %[2]s OR news.content %[1]s ? ESCAPE %[2]s)`, like, escape)
+	for _, term := range likeTerms {
+		pattern := "%" + escapeLike(term) + "%"
+		tx = tx.Where(condition, pattern, pattern)
+	}
+
+	if len(query.MediaIDList) > 0 {
+		tx = tx.Where("news.media_id IN ?", query.MediaIDList)
+	}
+	if query.AuthorID != 0 {
+		tx = tx.Where("news.author_id = ?", query.AuthorID)
+	}
+	if query.CategoryKey != "" {
+		tx = tx.Where("news.category_key = ?", query.CategoryKey)
+	}
+	if !query.From.IsZero() {
+		tx = tx.Where("news.published_at >= ?", query.From)
+	}
+	if !query.To.IsZero() {
+		tx = tx.Where("news.published_at < ?", query.To)
+	}
+
+	scoreColumn := "NULL"
+	if query.HasScoreRange() {
+		tx = tx.Joins("JOIN analyses ON analyses.news_id = news.news_id AND analyses.media_id = news.media_id "+
+			"AND analyses.type = ? AND analyses.deleted_at IS NULL", query.ScoreType)
+		scoreColumn = "analyses.score"
+		if query.MetricKey != "" {
+			tx = tx.Joins("JOIN analysis_metrics ON analysis_metrics.analysis_id = analyses.id "+
+				"AND analysis_metrics.metric_key = ? AND analysis_metrics.deleted_at IS NULL", query.MetricKey)
+			scoreColumn = "analysis_metrics.score"
+		}
+		if query.MinScore.Valid {
+			tx = tx.Where(scoreColumn+" >= ?", query.MinScore.Decimal)
+		}
+		if query.MaxScore.Valid {
+			tx = tx.Where(scoreColumn+" <= ?", query.MaxScore.Decimal)
+		}
+	}
+
+	var total int64
+	if err := tx.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count searched news: %w", err)
+	}
+	if total == 0 {
+		return []*entity.NewsSearchRow{}, 0, nil
+	}
+
+	tx = tx.Select("news.news_id, news.media_id, news.title, news.content, news.url, news.author_id, " +
+		"news.category_key, news.published_at, " + scoreColumn + " AS score")
+	if fullText {
+		tx = tx.Order(gorm.Expr("MATCH (news.title, news.content) AGAINST (? IN BOOLEAN MODE) DESC", booleanQuery(fullTextTerms)))
+	}
+
+	var rows []*entity.NewsSearchRow
+	err := tx.Order("news.published_at DESC").
+		Order("news.media_id").
+		Order("news.news_id").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search news: %w", err)
+	}
+
+	return rows, total, nil
+}
+
+// splitNgramTerms 依長度分為可用 ngram 全文索引比對的詞與需以 LIKE 比對的詞,
+// 短於 ngram_token_size 的詞不會被索引, 以 MATCH 比對會查無結果.
+func splitNgramTerms(terms []string, ngramTokenSize int) (fullTextTerms []string, likeTerms []string) {
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) < ngramTokenSize {
+			likeTerms = append(likeTerms, term)
+		} else {
+			fullTextTerms = append(fullTextTerms, term)
+		}
+	}
+	return fullTextTerms, likeTerms
+}
+
+// booleanQuery 將各詞轉為 MySQL BOOLEAN MODE 的必要詞組, 例如 `+"颱風" +"停班"`.
+func booleanQuery(terms []string) string {
+	replacer := strings.NewReplacer(`"`, "", `\`, "")
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term = replacer.Replace(term); term != "" {
+			parts = append(parts, `+"`+term+`"`)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// escapeLike 跳脫 LIKE 的萬用字元.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+type NewsSearchRepository interface {
+	// SearchNews 依關鍵字與結構化條件檢索新聞, 回傳該頁結果與符合條件的總數
+	SearchNews(ctx context.Context, query entity.NewsSearchQuery) ([]*entity.NewsSearchRow, int64, error)
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-testfixtures/testfixtures/v3"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
+)
+
+func TestNewsSearchRepoSuite(t *testing.T) {
+	suite.Run(t, new(NewsSearchTestSuite))
+}
+
+type NewsSearchTestSuite struct {
+	suite.Suite
+	searchRepo   NewsSearchRepository
+	analysisRepo AnalysisRepository
+	db           *gorm.DB
+}
+
+func (s *NewsSearchTestSuite) SetupTest() {
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	logger := zerolog.New(os.Stdout).Level(zerolog.InfoLevel)
+
+	s.db = testdb.New(context.Background(), &logger, tracer)
+
+	sqlDB, err := s.db.DB()
+	s.Require().NoError(err)
+
+	fixtures, err := testfixtures.New(
+		testfixtures.Database(sqlDB),
+		testfixtures.Dialect(s.db.Dialector.Name()),
+		testfixtures.Directory("testdata"),
+		testfixtures.DangerousSkipTestDatabaseCheck(),
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(fixtures.Load())
+
+	s.searchRepo = NewNewsSearchRepositoryImpl(&logger, s.db)
+	s.analysisRepo = NewAnalysisRepositoryImpl(&logger, s.db)
+
+	// 新聞 1 (fixture) 發布於 2021-01-01, 其餘為颱風相關新聞
+	newsList := []entity.News{
+		{NewsID: "2", MediaID: 1, Title: "颱風逼近 北部停班停課", Content: "中央氣象署發布颱風警報.",
+			URL: "https://test.com/news/2", AuthorID: 1, CategoryKey: "society",
+			PublishedAt: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{NewsID: "3", MediaID: 2, Title: "停班停課一覽", Content: "颱風來襲, 各縣市宣布停班停課 100% 確定.",
+			URL: "https://test.com/news/3", AuthorID: 1, CategoryKey: "society",
+			PublishedAt: time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{NewsID: "4", MediaID: 2, Title: "颱風天股市照常交易", Content: "證交所表示今天照常交易, TSMC 股價持平.",
+			URL: "https://test.com/news/4", AuthorID: 1, CategoryKey: "finance",
+			PublishedAt: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)},
+	}
+	s.Require().NoError(s.db.Create(&newsList).Error)
+
+	titleAnalysis := func(newsID string, mediaID uint, score float64, clarity float64) entity.Analysis {
+		return entity.Analysis{NewsID: newsID, MediaID: mediaID, Type: entity.AnalysisTypeTitle,
+			Score: decimal.NewFromFloat(score), Reason: "r",
+			AnalysisMetricsList: []entity.AnalysisMetric{
+				{MetricKey: "clarity", Score: decimal.NewFromFloat(clarity), Reason: "r"},
+			}}
+	}
+	s.Require().NoError(s.analysisRepo.SaveAnalysisList(context.Background(), []entity.Analysis{
+		titleAnalysis("2", 1, 4, 4.5),
+		titleAnalysis("3", 2, 3, 1.5),
+		titleAnalysis("4", 2, 2, 1),
+	}))
+}
+
+func (s *NewsSearchTestSuite) search(query entity.NewsSearchQuery) ([]string, int64) {
+	if query.Limit == 0 {
+		query.Limit = 10
+	}
+	rows, total, err := s.searchRepo.SearchNews(context.Background(), query)
+	s.Require().NoError(err)
+
+	newsIDList := make([]string, 0, len(rows))
+	for _, row := range rows {
+		newsIDList = append(newsIDList, row.NewsID)
+	}
+	return newsIDList, total
+}
+
+func (s *NewsSearchTestSuite) TestSearchNewsKeyword() {
+	// 比對標題與內容, 依發布時間新到舊
+	newsIDList, total := s.search(entity.NewsSearchQuery{Keyword: "颱風"})
+	s.Equal([]string{"4", "3", "2"}, newsIDList)
+	s.Equal(int64(3), total)
+
+	// 需同時符合所有詞
+	newsIDList, _ = s.search(entity.NewsSearchQuery{Keyword: "颱風  停班"})
+	s.Equal([]string{"3", "2"}, newsIDList)
+
+	// 萬用字元視為一般文字
+	newsIDList, _ = s.search(entity.NewsSearchQuery{Keyword: "100%"})
+	s.Equal([]string{"3"}, newsIDList)
+	newsIDList, _ = s.search(entity.NewsSearchQuery{Keyword: "%"})
+	s.Equal([]string{"3"}, newsIDList)
+
+	// 不區分大小寫
+	newsIDList, _ = s.search(entity.NewsSearchQuery{Keyword: "tsmc"})
+	s.Equal([]string{"4"}, newsIDList)
+
+	// 單字關鍵字 (MySQL 短於 ngram_token_size, 改以 LIKE 比對)
+	newsIDList, _ = s.search(entity.NewsSearchQuery{Keyword: "股"})
+	s.Equal([]string{"4"}, newsIDList)
+	newsIDList, _ = s.search(entity.NewsSearchQuery{Keyword: "颱風 股"})
+	s.Equal([]string{"4"}, newsIDList)
+
+	// 無關鍵字時只套用結構化條件
+	newsIDList, total = s.search(entity.NewsSearchQuery{})
+	s.Equal([]string{"4", "3", "2", "1"}, newsIDList)
+	s.Equal(int64(4), total)
+}
+
+func (s *NewsSearchTestSuite) TestSearchNewsFilters() {
+	newsIDList, _ := s.search(entity.NewsSearchQuery{Keyword: "颱風", MediaIDList: []uint{2}})
+	s.Equal([]string{"4", "3"}, newsIDList)
+
+	newsIDList, _ = s.search(entity.NewsSearchQuery{Keyword: "颱風", CategoryKey: "society"})
+	s.Equal([]string{"3", "2"}, newsIDList)
+
+	newsIDList, _ = s.search(entity.NewsSearchQuery{AuthorID: 2})
+	s.Empty(newsIDList)
+
+	newsIDList, _ = s.search(entity.NewsSearchQuery{
+		Keyword: "颱風",
+		From:    time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+	})
+	s.Equal([]string{"3", "2"}, newsIDList)
+
+	// 分頁時總數不受 limit 影響
+	newsIDList, total := s.search(entity.NewsSearchQuery{Keyword: "颱風", Limit: 1, Offset: 1})
+	s.Equal([]string{"3"}, newsIDList)
+	s.Equal(int64(3), total)
+}
+
+func (s *NewsSearchTestSuite) TestSearchNewsScoreRange() {
+	ctx := context.Background()
+
+	// 標題清晰度低於 2
+	rows, total, err := s.searchRepo.SearchNews(ctx, entity.NewsSearchQuery{
+		Keyword:   "颱風",
+		ScoreType: entity.AnalysisTypeTitle,
+		MetricKey: "clarity",
+		MaxScore:  decimal.NewNullDecimal(decimal.NewFromFloat(2)),
+		Limit:     10,
+	})
+	s.Require().NoError(err)
+	s.Equal(int64(2), total)
+	s.Require().Len(rows, 2)
+	s.Equal("4", rows[0].NewsID)
+	s.Equal("1", rows[0].Score.Decimal.String())
+	s.Equal("3", rows[1].NewsID)
+	s.Equal("1.5", rows[1].Score.Decimal.String())
+
+	// 分析總分
+	newsIDList, _ := s.search(entity.NewsSearchQuery{
+		ScoreType: entity.AnalysisTypeTitle,
+		MinScore:  decimal.NewNullDecimal(decimal.NewFromFloat(3)),
+		MaxScore:  decimal.NewNullDecimal(decimal.NewFromFloat(4)),
+	})
+	s.Equal([]string{"3", "2"}, newsIDList)
+
+	// 未分析的新聞不符合分數條件
+	newsIDList, _ = s.search(entity.NewsSearchQuery{
+		ScoreType: entity.AnalysisTypeContent,
+		MinScore:  decimal.NewNullDecimal(decimal.Zero),
+	})
+	s.Empty(newsIDList)
+}
+
+func TestSplitNgramTerms(t *testing.T) {
+	fullTextTerms, likeTerms := splitNgramTerms([]string{"颱風", "藍", "綠", "停班停課", "a"}, 2)
+	assert.Equal(t, []string{"颱風", "停班停課"}, fullTextTerms)
+	assert.Equal(t, []string{"藍", "綠", "a"}, likeTerms)
+
+	fullTextTerms, likeTerms = splitNgramTerms([]string{"颱風", "停班停課"}, 3)
+	assert.Equal(t, []string{"停班停課"}, fullTextTerms)
+	assert.Equal(t, []string{"颱風"}, likeTerms)
+}
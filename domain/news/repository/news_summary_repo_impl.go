@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+var _ NewsSummaryRepository = &NewsSummaryRepositoryImpl{}
+
+type NewsSummaryRepositoryImpl struct {
+	logger *zerolog.Logger
+	db     *gorm.DB
+}
+
+func NewNewsSummaryRepositoryImpl(logger *zerolog.Logger, db *gorm.DB) *NewsSummaryRepositoryImpl {
+	return &NewsSummaryRepositoryImpl{
+		logger: logger,
+		db:     db,
+	}
+}
+
+func (r *NewsSummaryRepositoryImpl) ReplaceNewsSummary(ctx context.Context, summary *entity.NewsSummary) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		where := map[string]any{"news_id": summary.NewsID, "media_id": summary.MediaID}
+		if err := tx.Unscoped().Where(where).Delete(&entity.NewsClaim{}).Error; err != nil {
+			return fmt.Errorf("failed to delete news claims: %w", err)
+		}
+		if err := tx.Unscoped().Where(where).Delete(&entity.NewsSummary{}).Error; err != nil {
+			return fmt.Errorf("failed to delete news summary: %w", err)
+		}
+
+		for i := range summary.ClaimList {
+			summary.ClaimList[i].NewsID = summary.NewsID
+			summary.ClaimList[i].MediaID = summary.MediaID
+		}
+		if err := tx.Create(summary).Error; err != nil {
+			return fmt.Errorf("failed to create news summary: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *NewsSummaryRepositoryImpl) GetNewsSummary(
+	ctx context.Context,
+	newsID string,
+	mediaID uint,
+) (*entity.NewsSummary, error) {
+	var summary entity.NewsSummary
+
+	err := r.db.WithContext(ctx).
+		Preload("ClaimList", func(db *gorm.DB) *gorm.DB { return db.Order("seq") }).
+		Where(&entity.NewsSummary{NewsID: newsID, MediaID: mediaID}).
+		First(&summary).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get news summary: %w", err)
+	}
+
+	return &summary, nil
+}
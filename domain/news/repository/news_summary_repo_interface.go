@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+type NewsSummaryRepository interface {
+	// ReplaceNewsSummary 以 summary (含 ClaimList) 取代單篇新聞已儲存的摘要與事實陳述
+	ReplaceNewsSummary(ctx context.Context, summary *entity.NewsSummary) error
+	// GetNewsSummary 取得新聞的摘要與依順序排列的事實陳述, 不存在時回傳 nil
+	GetNewsSummary(ctx context.Context, newsID string, mediaID uint) (*entity.NewsSummary, error)
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/go-testfixtures/testfixtures/v3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
+)
+
+func TestNewsSummaryRepoSuite(t *testing.T) {
+	suite.Run(t, new(NewsSummaryTestSuite))
+}
+
+type NewsSummaryTestSuite struct {
+	suite.Suite
+	summaryRepo NewsSummaryRepository
+	db          *gorm.DB
+}
+
+func (s *NewsSummaryTestSuite) SetupTest() {
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	logger := zerolog.New(os.Stdout).Level(zerolog.InfoLevel)
+
+	s.db = testdb.New(context.Background(), &logger, tracer)
+
+	sqlDB, err := s.db.DB()
+	s.Require().NoError(err)
+
+	fixtures, err := testfixtures.New(
+		testfixtures.Database(sqlDB),
+		testfixtures.Dialect(s.db.Dialector.Name()),
+		testfixtures.Directory("testdata"),
+		testfixtures.DangerousSkipTestDatabaseCheck(),
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(fixtures.Load())
+
+	s.summaryRepo = NewNewsSummaryRepositoryImpl(&logger, s.db)
+}
+
+func (s *NewsSummaryTestSuite) TestReplaceNewsSummary() {
+	ctx := context.Background()
+	start, end := 0, 4
+
+	s.Require().NoError(s.summaryRepo.ReplaceNewsSummary(ctx, &entity.NewsSummary{
+		NewsID: "1", MediaID: 1, Summary: "舊摘要",
+		ClaimList: []entity.NewsClaim{
+			{Seq: 1, Claim: "c1", Quote: "q1"},
+			{Seq: 2, Claim: "c2", Quote: "q2"},
+		},
+	}))
+
+	// 重新產生時取代舊資料
+	s.Require().NoError(s.summaryRepo.ReplaceNewsSummary(ctx, &entity.NewsSummary{
+		NewsID: "1", MediaID: 1, Summary: "新摘要",
+		ClaimList: []entity.NewsClaim{
+			{Seq: 2, Claim: "new c2", Quote: "找不到的引文"},
+			{Seq: 1, Claim: "new c1", Quote: "test", QuoteStart: &start, QuoteEnd: &end},
+		},
+	}))
+
+	summary, err := s.summaryRepo.GetNewsSummary(ctx, "1", 1)
+	s.Require().NoError(err)
+	s.Require().NotNil(summary)
+	s.Equal("新摘要", summary.Summary)
+	s.Require().Len(summary.ClaimList, 2)
+
+	s.Equal("new c1", summary.ClaimList[0].Claim)
+	s.Equal("1", summary.ClaimList[0].NewsID)
+	s.Equal(&start, summary.ClaimList[0].QuoteStart)
+	s.Equal(&end, summary.ClaimList[0].QuoteEnd)
+	s.Equal("new c2", summary.ClaimList[1].Claim)
+	s.Nil(summary.ClaimList[1].QuoteStart)
+
+	var count int64
+	s.Require().NoError(s.db.Model(&entity.NewsClaim{}).Count(&count).Error)
+	s.Equal(int64(2), count)
+
+	notFound, err := s.summaryRepo.GetNewsSummary(ctx, "1", 2)
+	s.Require().NoError(err)
+	s.Nil(notFound)
+}
@@ -3,5 +3,6 @@ package repository
 import "gorm.io/gorm"
 
 type BaseRepository[T any] interface {
+	// WithTransaction 回傳使用 tx 的 repository 副本, 不會修改原本的 repository
 	WithTransaction(tx *gorm.DB) T
 }
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+var _ StoryRepository = &StoryRepositoryImpl{}
+
+type StoryRepositoryImpl struct {
+	logger *zerolog.Logger
+	db     *gorm.DB
+}
+
+func NewStoryRepositoryImpl(logger *zerolog.Logger, db *gorm.DB) *StoryRepositoryImpl {
+	return &StoryRepositoryImpl{
+		logger: logger,
+		db:     db,
+	}
+}
+
+func (r *StoryRepositoryImpl) FindStoryCandidates(ctx context.Context, since time.Time) ([]*entity.StoryCandidateRow, error) {
+	var rows []*entity.StoryCandidateRow
+
+	result := r.db.WithContext(ctx).
+		Model(&entity.News{}).
+		Select("news.news_id AS news_id, news.media_id AS media_id, news.title AS title, news.content AS content, "+
+			"news.published_at AS published_at, COALESCE(story_articles.story_id, 0) AS story_id").
+		Joins("LEFT JOIN story_articles ON story_articles.news_id = news.news_id "+
+			"AND story_articles.media_id = news.media_id AND story_articles.deleted_at IS NULL").
+		Where("news.published_at >= ?", since).
+		Order("news.published_at, news.media_id, news.news_id").
+		Scan(&rows)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find story candidates: %w", result.Error)
+	}
+
+	return rows, nil
+}
+
+func (r *StoryRepositoryImpl) AddStoryArticles(
+	ctx context.Context,
+	storyID uint,
+	articleList []entity.StoryArticle,
+) (uint, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if storyID == 0 {
+			// 標題與時間於加入新聞後更新
+			story := entity.Story{FirstPublishedAt: time.Now(), LastPublishedAt: time.Now()}
+			if err := tx.Create(&story).Error; err != nil {
+				return fmt.Errorf("failed to create story: %w", err)
+			}
+			storyID = story.ID
+		}
+
+		if len(articleList) > 0 {
+			for i := range articleList {
+				articleList[i].StoryID = storyID
+			}
+			if err := tx.Create(&articleList).Error; err != nil {
+				return fmt.Errorf("failed to create story articles: %w", err)
+			}
+		}
+
+		return r.refreshStory(tx, storyID)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return storyID, nil
+}
+
+// refreshStory 依事件包含的新聞更新標題, 發布時間與數量.
+func (r *StoryRepositoryImpl) refreshStory(tx *gorm.DB, storyID uint) error {
+	var newsList []entity.News
+	err := tx.Model(&entity.News{}).
+		Joins("JOIN story_articles ON story_articles.news_id = news.news_id AND story_articles.media_id = news.media_id").
+		Where("story_articles.story_id = ? AND story_articles.deleted_at IS NULL", storyID).
+		Order("news.published_at, news.media_id").
+		Find(&newsList).Error
+	if err != nil {
+		return fmt.Errorf("failed to find story news: %w", err)
+	}
+	if len(newsList) == 0 {
+		return fmt.Errorf("story %d has no articles", storyID)
+	}
+
+	media := map[uint]bool{}
+	for _, news := range newsList {
+		media[news.MediaID] = true
+	}
+
+	err = tx.Model(&entity.Story{ID: storyID}).Updates(map[string]any{
+		"title":              newsList[0].Title,
+		"first_published_at": newsList[0].PublishedAt,
+		"last_published_at":  newsList[len(newsList)-1].PublishedAt,
+		"article_count":      len(newsList),
+		"media_count":        len(media),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update story: %w", err)
+	}
+
+	return nil
+}
+
+func (r *StoryRepositoryImpl) FindStories(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	minMedia int,
+	limit int,
+) ([]*entity.Story, error) {
+	var stories []*entity.Story
+
+	err := r.db.WithContext(ctx).
+		Where("first_published_at >= ? AND first_published_at < ?", from, to).
+		Where("media_count >= ?", minMedia).
+		Order("first_published_at DESC, id DESC").
+		Limit(limit).
+		Find(&stories).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stories: %w", err)
+	}
+
+	return stories, nil
+}
+
+func (r *StoryRepositoryImpl) GetStory(ctx context.Context, storyID uint) (*entity.Story, error) {
+	var story entity.Story
+
+	err := r.db.WithContext(ctx).First(&story, storyID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get story: %w", err)
+	}
+
+	return &story, nil
+}
+
+func (r *StoryRepositoryImpl) FindStoryArticles(ctx context.Context, storyIDList []uint) ([]*entity.StoryArticleRow, error) {
+	var rows []*entity.StoryArticleRow
+	if len(storyIDList) == 0 {
+		return rows, nil
+	}
+
+	analysisJoin := "LEFT JOIN analyses AS %[1]s ON %[1]s.news_id = news.news_id AND %[1]s.media_id = news.media_id " +
+		"AND %[1]s.type = ? AND %[1]s.deleted_at IS NULL"
+
+	result := r.db.WithContext(ctx).
+		Model(&entity.StoryArticle{}).
+		Select("story_articles.story_id AS story_id, story_articles.news_id AS news_id, "+
+			"story_articles.media_id AS media_id, media.name AS media_name, news.title AS title, news.url AS url, "+
+			"news.published_at AS published_at, story_articles.similarity AS similarity, "+
+			"title_analyses.score AS title_score, content_analyses.score AS content_score").
+		Joins("JOIN news ON news.news_id = story_articles.news_id AND news.media_id = story_articles.media_id").
+		Joins("JOIN media ON media.id = story_articles.media_id").
+		Joins(fmt.Sprintf(analysisJoin, "title_analyses"), entity.AnalysisTypeTitle).
+		Joins(fmt.Sprintf(analysisJoin, "content_analyses"), entity.AnalysisTypeContent).
+		Where("story_articles.story_id IN ?", storyIDList).
+		Order("story_articles.story_id, news.published_at, story_articles.media_id").
+		Scan(&rows)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find story articles: %w", result.Error)
+	}
+
+	return rows, nil
+}
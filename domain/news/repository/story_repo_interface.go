@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+type StoryRepository interface {
+	// FindStoryCandidates 找出 since 之後發布的新聞與已歸入的事件
+	FindStoryCandidates(ctx context.Context, since time.Time) ([]*entity.StoryCandidateRow, error)
+	// AddStoryArticles 將新聞加入事件並更新事件的發布時間與媒體數, storyID 為 0 時建立新事件, 回傳事件 ID
+	AddStoryArticles(ctx context.Context, storyID uint, articleList []entity.StoryArticle) (uint, error)
+	// FindStories 找出 [from, to) 期間首次報導且至少 minMedia 家媒體報導的事件, 依首次報導時間新到舊
+	FindStories(ctx context.Context, from time.Time, to time.Time, minMedia int, limit int) ([]*entity.Story, error)
+	// GetStory 取得事件, 不存在時回傳 nil
+	GetStory(ctx context.Context, storyID uint) (*entity.Story, error)
+	// FindStoryArticles 找出事件包含的新聞與標題, 內容分析的總分, 依發布時間排序
+	FindStoryArticles(ctx context.Context, storyIDList []uint) ([]*entity.StoryArticleRow, error)
+}
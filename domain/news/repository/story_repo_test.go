@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-testfixtures/testfixtures/v3"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
+)
+
+func TestStoryRepoSuite(t *testing.T) {
+	suite.Run(t, new(StoryTestSuite))
+}
+
+type StoryTestSuite struct {
+	suite.Suite
+	storyRepo    StoryRepository
+	analysisRepo AnalysisRepository
+	db           *gorm.DB
+}
+
+func (s *StoryTestSuite) SetupTest() {
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	logger := zerolog.New(os.Stdout).Level(zerolog.InfoLevel)
+
+	s.db = testdb.New(context.Background(), &logger, tracer)
+
+	sqlDB, err := s.db.DB()
+	s.Require().NoError(err)
+
+	fixtures, err := testfixtures.New(
+		testfixtures.Database(sqlDB),
+		testfixtures.Dialect(s.db.Dialector.Name()),
+		testfixtures.Directory("testdata"),
+		testfixtures.DangerousSkipTestDatabaseCheck(),
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(fixtures.Load())
+
+	s.storyRepo = NewStoryRepositoryImpl(&logger, s.db)
+	s.analysisRepo = NewAnalysisRepositoryImpl(&logger, s.db)
+
+	// 新聞 1 (中天) 發布於 2021-01-01 00:00, 新聞 2 (三立) 晚 30 分鐘
+	s.Require().NoError(s.db.Create(&entity.News{
+		NewsID: "2", MediaID: 2, Title: "test news 2", Content: "test content 2", URL: "https://test.com/news/2",
+		AuthorID: 1, PublishedAt: time.Date(2021, 1, 1, 0, 30, 0, 0, time.UTC),
+	}).Error)
+}
+
+func (s *StoryTestSuite) TestAddStoryArticles() {
+	ctx := context.Background()
+
+	// 先加入較晚發布的新聞
+	storyID, err := s.storyRepo.AddStoryArticles(ctx, 0, []entity.StoryArticle{
+		{NewsID: "2", MediaID: 2, Similarity: 1},
+	})
+	s.Require().NoError(err)
+	s.NotZero(storyID)
+
+	story, err := s.storyRepo.GetStory(ctx, storyID)
+	s.Require().NoError(err)
+	s.Require().NotNil(story)
+	s.Equal("test news 2", story.Title)
+	s.Equal(1, story.ArticleCount)
+	s.Equal(1, story.MediaCount)
+
+	// 加入既有事件, 標題改為最早的報導
+	_, err = s.storyRepo.AddStoryArticles(ctx, storyID, []entity.StoryArticle{
+		{NewsID: "1", MediaID: 1, Similarity: 0.8},
+	})
+	s.Require().NoError(err)
+
+	story, err = s.storyRepo.GetStory(ctx, storyID)
+	s.Require().NoError(err)
+	s.Equal("test news 1", story.Title)
+	s.Equal(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), story.FirstPublishedAt.UTC())
+	s.Equal(time.Date(2021, 1, 1, 0, 30, 0, 0, time.UTC), story.LastPublishedAt.UTC())
+	s.Equal(2, story.ArticleCount)
+	s.Equal(2, story.MediaCount)
+
+	// 同一篇新聞只能屬於一個事件
+	_, err = s.storyRepo.AddStoryArticles(ctx, 0, []entity.StoryArticle{{NewsID: "1", MediaID: 1, Similarity: 1}})
+	s.Error(err)
+
+	notFound, err := s.storyRepo.GetStory(ctx, storyID+100)
+	s.Require().NoError(err)
+	s.Nil(notFound)
+}
+
+func (s *StoryTestSuite) TestFindStoryCandidates() {
+	ctx := context.Background()
+
+	storyID, err := s.storyRepo.AddStoryArticles(ctx, 0, []entity.StoryArticle{{NewsID: "1", MediaID: 1, Similarity: 1}})
+	s.Require().NoError(err)
+
+	rows, err := s.storyRepo.FindStoryCandidates(ctx, time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC))
+	s.Require().NoError(err)
+	s.Require().Len(rows, 2)
+	s.Equal("1", rows[0].NewsID)
+	s.Equal(storyID, rows[0].StoryID)
+	s.Equal("test content 1", rows[0].Content)
+	s.Equal("2", rows[1].NewsID)
+	s.Zero(rows[1].StoryID)
+
+	rows, err = s.storyRepo.FindStoryCandidates(ctx, time.Date(2021, 1, 1, 0, 10, 0, 0, time.UTC))
+	s.Require().NoError(err)
+	s.Require().Len(rows, 1)
+	s.Equal("2", rows[0].NewsID)
+}
+
+func (s *StoryTestSuite) TestFindStoriesAndArticles() {
+	ctx := context.Background()
+
+	storyID, err := s.storyRepo.AddStoryArticles(ctx, 0, []entity.StoryArticle{
+		{NewsID: "1", MediaID: 1, Similarity: 1},
+		{NewsID: "2", MediaID: 2, Similarity: 0.6},
+	})
+	s.Require().NoError(err)
+
+	// 只有新聞 1 已分析
+	s.Require().NoError(s.analysisRepo.SaveAnalysisList(context.Background(), []entity.Analysis{
+		{NewsID: "1", MediaID: 1, Type: entity.AnalysisTypeTitle, Score: decimal.NewFromFloat(3.5), Reason: "r"},
+		{NewsID: "1", MediaID: 1, Type: entity.AnalysisTypeContent, Score: decimal.NewFromFloat(4), Reason: "r"},
+	}))
+
+	from := time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	stories, err := s.storyRepo.FindStories(ctx, from, to, 2, 10)
+	s.Require().NoError(err)
+	s.Require().Len(stories, 1)
+	s.Equal(storyID, stories[0].ID)
+
+	stories, err = s.storyRepo.FindStories(ctx, from, to, 3, 10)
+	s.Require().NoError(err)
+	s.Empty(stories)
+
+	rows, err := s.storyRepo.FindStoryArticles(ctx, []uint{storyID})
+	s.Require().NoError(err)
+	s.Require().Len(rows, 2)
+
+	s.Equal("1", rows[0].NewsID)
+	s.Equal("中天", rows[0].MediaName)
+	s.Equal("https://test.com/news/1", rows[0].URL)
+	s.Equal("3.5", rows[0].TitleScore.Decimal.String())
+	s.Equal("4", rows[0].ContentScore.Decimal.String())
+
+	s.Equal("2", rows[1].NewsID)
+	s.Equal("三立", rows[1].MediaName)
+	s.Equal(0.6, rows[1].Similarity)
+	s.False(rows[1].TitleScore.Valid)
+	s.False(rows[1].ContentScore.Valid)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ UnitOfWork = &UnitOfWorkImpl{}
+
+type UnitOfWorkImpl struct {
+	logger *zerolog.Logger
+	db     *gorm.DB
+}
+
+func NewUnitOfWorkImpl(logger *zerolog.Logger, db *gorm.DB) *UnitOfWorkImpl {
+	return &UnitOfWorkImpl{logger: logger, db: db}
+}
+
+func (u *UnitOfWorkImpl) Do(ctx context.Context, fn func(repos Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			News:     NewNewsRepositoryImpl(u.logger, tx),
+			Author:   NewAuthorRepositoryImpl(u.logger, tx),
+			Analysis: NewAnalysisRepositoryImpl(u.logger, tx),
+		})
+	})
+}
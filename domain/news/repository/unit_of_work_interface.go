@@ -0,0 +1,16 @@
+package repository
+
+import "context"
+
+// Repositories 為同一個交易範圍內的 repository.
+type Repositories struct {
+	News     NewsRepository
+	Author   AuthorRepository
+	Analysis AnalysisRepository
+}
+
+type UnitOfWork interface {
+	// Do 在交易中執行 fn, fn 回傳錯誤或 panic 時 rollback, 否則 commit.
+	// repos 只在 fn 內有效, 不可保留至交易結束後使用.
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+}
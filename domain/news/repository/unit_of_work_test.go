@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-testfixtures/testfixtures/v3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/infra"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
+)
+
+func TestUnitOfWorkSuite(t *testing.T) {
+	suite.Run(t, new(UnitOfWorkTestSuite))
+}
+
+type UnitOfWorkTestSuite struct {
+	suite.Suite
+	db       *gorm.DB
+	uow      UnitOfWork
+	newsRepo NewsRepository
+}
+
+func (s *UnitOfWorkTestSuite) SetupTest() {
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	infra.SetInfraTracer(tracer)
+
+	logger := zerolog.New(os.Stdout).Level(zerolog.InfoLevel)
+	infra.SetInfraLogger(&logger)
+
+	s.db = testdb.New(context.Background(), &logger, tracer)
+
+	sqlDB, err := s.db.DB()
+	s.Require().NoError(err)
+
+	// 初始化測試資料
+	fixtures, _ := testfixtures.New(
+		testfixtures.Database(sqlDB),
+		testfixtures.Dialect(s.db.Dialector.Name()),
+		testfixtures.Directory("testdata"),
+		testfixtures.DangerousSkipTestDatabaseCheck(),
+	)
+	s.Require().NoError(fixtures.Load())
+
+	s.uow = NewUnitOfWorkImpl(&logger, s.db)
+	s.newsRepo = NewNewsRepositoryImpl(&logger, s.db)
+}
+
+func (s *UnitOfWorkTestSuite) saveAuthorAndNews(ctx context.Context, fail bool) error {
+	return s.uow.Do(ctx, func(repos Repositories) error {
+		author := &entity.Author{MediaID: 1, Name: "uow author"}
+		if err := repos.Author.FirstOrCreate(ctx, author); err != nil {
+			return err
+		}
+
+		if err := repos.News.SaveNews(ctx, &entity.News{
+			MediaID:     1,
+			NewsID:      "uow-1",
+			Title:       "uow news",
+			Content:     "uow content",
+			URL:         "https://example.com/uow-1",
+			AuthorID:    author.ID,
+			PublishedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		}); err != nil {
+			return err
+		}
+
+		if fail {
+			return errors.New("fail after save")
+		}
+		return nil
+	})
+}
+
+func (s *UnitOfWorkTestSuite) TestDo_Commit() {
+	ctx := context.Background()
+	s.Require().NoError(s.saveAuthorAndNews(ctx, false))
+
+	var count int64
+	s.Require().NoError(s.db.Model(&entity.Author{}).Where("name = ?", "uow author").Count(&count).Error)
+	s.Equal(int64(1), count)
+
+	nonExisting, err := s.newsRepo.FindNonExistingNewsIDs(ctx, 1, []string{"uow-1"})
+	s.Require().NoError(err)
+	s.Empty(nonExisting)
+}
+
+func (s *UnitOfWorkTestSuite) TestDo_Rollback() {
+	ctx := context.Background()
+	s.Require().Error(s.saveAuthorAndNews(ctx, true))
+
+	// 作者與新聞皆不應寫入
+	var count int64
+	s.Require().NoError(s.db.Model(&entity.Author{}).Where("name = ?", "uow author").Count(&count).Error)
+	s.Zero(count)
+
+	nonExisting, err := s.newsRepo.FindNonExistingNewsIDs(ctx, 1, []string{"uow-1"})
+	s.Require().NoError(err)
+	s.Equal([]string{"uow-1"}, nonExisting)
+}
+
+func (s *UnitOfWorkTestSuite) TestWithTransaction_DoesNotMutate() {
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	txRepo := s.newsRepo.WithTransaction(tx)
+	s.NotSame(s.newsRepo, txRepo)
+	s.Same(s.db, s.newsRepo.(*NewsRepositoryImpl).db)
+	s.Same(tx, txRepo.(*NewsRepositoryImpl).db)
+}
+
+func (s *UnitOfWorkTestSuite) TestDo_Canceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s.Error(s.saveAuthorAndNews(ctx, false))
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+	"itmrchow/tw-media-analytics-service/domain/utils/health"
+)
+
+const defaultCrawlMaxAge = 2 * time.Hour
+
+// CrawlStatus 單一媒體的爬取狀態.
+type CrawlStatus struct {
+	MediaID       uint      `json:"mediaId"`
+	LastCrawledAt time.Time `json:"lastCrawledAt"`
+}
+
+// CrawlHealthCheck 爬取健康檢查, 以資料庫中各媒體最後一篇新聞的寫入時間判斷,
+// 超過 maxAge 未寫入即視為異常. 爬蟲異常不影響服務本身, 因此不列入 liveness / readiness.
+func CrawlHealthCheck(newsRepo repository.NewsRepository, maxAge time.Duration) health.Check {
+	if maxAge <= 0 {
+		maxAge = defaultCrawlMaxAge
+	}
+
+	return health.Check{
+		Name:     "crawl",
+		CacheTTL: time.Minute,
+		Func: func(ctx context.Context) (any, error) {
+			lastCrawledAt, err := newsRepo.FindLastCrawledAt(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if len(lastCrawledAt) == 0 {
+				return nil, health.ErrUnknown
+			}
+
+			now := time.Now()
+			statusList := make([]CrawlStatus, 0, len(lastCrawledAt))
+			var unhealthy []string
+			for mediaID, crawledAt := range lastCrawledAt {
+				statusList = append(statusList, CrawlStatus{MediaID: mediaID, LastCrawledAt: crawledAt})
+				if now.Sub(crawledAt) > maxAge {
+					unhealthy = append(unhealthy, strconv.FormatUint(uint64(mediaID), 10))
+				}
+			}
+			sort.Slice(statusList, func(i, j int) bool { return statusList[i].MediaID < statusList[j].MediaID })
+
+			if len(unhealthy) > 0 {
+				sort.Strings(unhealthy)
+				return statusList, fmt.Errorf("no news crawled within %s, media_id: %s", maxAge, strings.Join(unhealthy, ","))
+			}
+
+			return statusList, nil
+		},
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/ai"
+	"itmrchow/tw-media-analytics-service/domain/embedding"
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+	"itmrchow/tw-media-analytics-service/domain/utils"
+)
+
+var (
+	// ErrEmbeddingDisabled 未設定 EMBEDDING_PROVIDER.
+	ErrEmbeddingDisabled = errors.New("embedding disabled")
+	// ErrEmbeddingNotFound 新聞尚未計算向量.
+	ErrEmbeddingNotFound = errors.New("embedding not found")
+)
+
+// SimilarNews 相似新聞與餘弦相似度.
+type SimilarNews struct {
+	NewsID      string    `json:"newsId"`
+	MediaID     uint      `json:"mediaId"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"publishedAt"`
+	Similarity  float64   `json:"similarity"` // 四捨五入至小數點下四位
+}
+
+var _ EmbeddingService = &EmbeddingServiceImpl{}
+
+type EmbeddingServiceImpl struct {
+	logger        *zerolog.Logger
+	tracer        trace.Tracer
+	embeddingRepo repository.NewsEmbeddingRepository
+	model         ai.EmbeddingModel // nil 代表未啟用
+}
+
+func NewEmbeddingServiceImpl(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	embeddingRepo repository.NewsEmbeddingRepository,
+	model ai.EmbeddingModel,
+) *EmbeddingServiceImpl {
+	return &EmbeddingServiceImpl{
+		logger:        logger,
+		tracer:        tracer,
+		embeddingRepo: embeddingRepo,
+		model:         model,
+	}
+}
+
+// EmbedNews 計算最新且尚未有向量的新聞, 每次最多 BatchSize 篇.
+func (s *EmbeddingServiceImpl) EmbedNews(ctx context.Context, event utils.EventNewsEmbedding) error {
+	ctx, span := s.tracer.Start(ctx, "domain/news/service/embedding_service_impl/EmbedNews: Embed News")
+	defer span.End()
+
+	if s.model == nil {
+		s.logger.Debug().Ctx(ctx).Msg("embedding disabled, skip")
+		return nil
+	}
+
+	newsList, err := s.embeddingRepo.FindNewsWithoutEmbedding(ctx, s.model.Name(), int(event.BatchSize))
+	if err != nil {
+		return err
+	}
+	if len(newsList) == 0 {
+		return nil
+	}
+
+	docs := make([]ai.EmbeddingDocument, 0, len(newsList))
+	for _, news := range newsList {
+		docs = append(docs, ai.EmbeddingDocument{Title: news.Title, Content: news.Content})
+	}
+	vectors, err := s.model.EmbedDocuments(ctx, docs)
+	if err != nil {
+		return fmt.Errorf("failed to embed news: %w", err)
+	}
+
+	embeddingList := make([]entity.NewsEmbedding, 0, len(newsList))
+	for i, news := range newsList {
+		embeddingList = append(embeddingList, entity.NewsEmbedding{
+			NewsID:     news.NewsID,
+			MediaID:    news.MediaID,
+			Model:      s.model.Name(),
+			Dimensions: len(vectors[i]),
+			Vector:     embedding.Encode(vectors[i]),
+		})
+	}
+	if err = s.embeddingRepo.SaveEmbeddings(ctx, embeddingList); err != nil {
+		return err
+	}
+
+	s.logger.Info().Ctx(ctx).Str("model", s.model.Name()).Int("news", len(embeddingList)).Msg("news embedded")
+	return nil
+}
+
+func (s *EmbeddingServiceImpl) SimilarToNews(
+	ctx context.Context,
+	newsID string,
+	mediaID uint,
+	filter entity.EmbeddingFilter,
+	limit int,
+) ([]SimilarNews, error) {
+	ctx, span := s.tracer.Start(ctx, "domain/news/service/embedding_service_impl/SimilarToNews: Similar To News")
+	defer span.End()
+
+	if s.model == nil {
+		return nil, ErrEmbeddingDisabled
+	}
+
+	e, err := s.embeddingRepo.GetEmbedding(ctx, newsID, mediaID, s.model.Name())
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, ErrEmbeddingNotFound
+	}
+	query, err := embedding.Decode(e.Vector)
+	if err != nil {
+		return nil, err
+	}
+
+	// 多取一筆, 排除新聞本身
+	result, err := s.search(ctx, query, filter, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	similar := make([]SimilarNews, 0, limit)
+	for _, news := range result {
+		if news.NewsID == newsID && news.MediaID == mediaID {
+			continue
+		}
+		similar = append(similar, news)
+	}
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, nil
+}
+
+func (s *EmbeddingServiceImpl) SearchSimilar(
+	ctx context.Context,
+	query string,
+	filter entity.EmbeddingFilter,
+	limit int,
+) ([]SimilarNews, error) {
+	ctx, span := s.tracer.Start(ctx, "domain/news/service/embedding_service_impl/SearchSimilar: Search Similar")
+	defer span.End()
+
+	if s.model == nil {
+		return nil, ErrEmbeddingDisabled
+	}
+
+	vector, err := s.model.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	return s.search(ctx, vector, filter, limit)
+}
+
+// search 以暴力搜尋比對符合條件的新聞向量.
+func (s *EmbeddingServiceImpl) search(
+	ctx context.Context,
+	query []float32,
+	filter entity.EmbeddingFilter,
+	limit int,
+) ([]SimilarNews, error) {
+	rows, err := s.embeddingRepo.FindEmbeddings(ctx, s.model.Name(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		row    *entity.NewsEmbeddingRow
+		vector []float32
+	}
+	candidates := make([]candidate, 0, len(rows))
+	for _, row := range rows {
+		vector, err := embedding.Decode(row.Vector)
+		if err != nil {
+			s.logger.Warn().Err(err).Ctx(ctx).Str("news_id", row.NewsID).Msg("invalid news embedding, skip")
+			continue
+		}
+		candidates = append(candidates, candidate{row: row, vector: vector})
+	}
+
+	top := embedding.TopK(query, candidates, func(c candidate) []float32 { return c.vector }, limit)
+
+	result := make([]SimilarNews, 0, len(top))
+	for _, scored := range top {
+		row := scored.Item.row
+		result = append(result, SimilarNews{
+			NewsID:      row.NewsID,
+			MediaID:     row.MediaID,
+			Title:       row.Title,
+			URL:         row.URL,
+			PublishedAt: row.PublishedAt,
+			Similarity:  math.Round(scored.Score*10000) / 10000,
+		})
+	}
+	return result, nil
+}
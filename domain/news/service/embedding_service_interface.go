@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/utils"
+)
+
+type EmbeddingService interface {
+	// 計算尚未有向量的新聞
+	EmbedNews(ctx context.Context, event utils.EventNewsEmbedding) error
+
+	// 與指定新聞最相似的新聞, 不包含新聞本身
+	SimilarToNews(
+		ctx context.Context,
+		newsID string,
+		mediaID uint,
+		filter entity.EmbeddingFilter,
+		limit int,
+	) ([]SimilarNews, error)
+
+	// 與查詢文字最相似的新聞
+	SearchSimilar(ctx context.Context, query string, filter entity.EmbeddingFilter, limit int) ([]SimilarNews, error)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+// Interval 報導量統計的時間區間.
+type Interval string
+
+const (
+	IntervalDay   Interval = "day"
+	IntervalWeek  Interval = "week" // 週一開始
+	IntervalMonth Interval = "month"
+)
+
+// ParseInterval 解析時間區間, 空字串為 day.
+func ParseInterval(s string) (Interval, error) {
+	switch Interval(s) {
+	case "":
+		return IntervalDay, nil
+	case IntervalDay, IntervalWeek, IntervalMonth:
+		return Interval(s), nil
+	default:
+		return "", fmt.Errorf("unknown interval %q", s)
+	}
+}
+
+// PeriodStart 回傳 t 所在區間的開始時間 (t 的時區).
+func (i Interval) PeriodStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch i {
+	case IntervalWeek:
+		// time.Sunday 為 0, 往前推至週一
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// EntityCoveragePoint 單一區間, 單一媒體提及實體的報導量與平均客觀性.
+type EntityCoveragePoint struct {
+	Period         time.Time `json:"period"`
+	MediaID        uint      `json:"mediaId"`
+	Articles       int       `json:"articles"`
+	Scored         int       `json:"scored"`                   // 已有客觀性分數的新聞數
+	AvgObjectivity *float64  `json:"avgObjectivity,omitempty"` // 四捨五入至小數點下兩位, 皆未分析時為 nil
+}
+
+// EntityCoverage 依區間與媒體彙總提及實體的新聞, 區間以 loc 時區計算.
+func EntityCoverage(rows []*entity.EntityCoverageRow, interval Interval, loc *time.Location) []EntityCoveragePoint {
+	type key struct {
+		period  time.Time
+		mediaID uint
+	}
+	type sum struct {
+		articles int
+		scored   int
+		score    float64
+	}
+
+	var keys []key
+	sums := map[key]*sum{}
+	for _, row := range rows {
+		k := key{period: interval.PeriodStart(row.PublishedAt.In(loc)), mediaID: row.MediaID}
+		s, ok := sums[k]
+		if !ok {
+			s = &sum{}
+			sums[k] = s
+			keys = append(keys, k)
+		}
+
+		s.articles++
+		if row.Objectivity.Valid {
+			s.scored++
+			s.score += row.Objectivity.Decimal.InexactFloat64()
+		}
+	}
+
+	slices.SortFunc(keys, func(a, b key) int {
+		if c := a.period.Compare(b.period); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.mediaID, b.mediaID)
+	})
+
+	points := make([]EntityCoveragePoint, 0, len(keys))
+	for _, k := range keys {
+		s := sums[k]
+		point := EntityCoveragePoint{
+			Period:   k.period,
+			MediaID:  k.mediaID,
+			Articles: s.articles,
+			Scored:   s.scored,
+		}
+		if s.scored > 0 {
+			avg := math.Round(s.score/float64(s.scored)*100) / 100
+			point.AvgObjectivity = &avg
+		}
+		points = append(points, point)
+	}
+
+	return points
+}
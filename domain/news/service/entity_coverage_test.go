@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+func TestInterval_PeriodStart(t *testing.T) {
+	// 2025-05-07 為週三
+	at := time.Date(2025, 5, 7, 15, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC), IntervalDay.PeriodStart(at))
+	assert.Equal(t, time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC), IntervalWeek.PeriodStart(at))
+	assert.Equal(t, time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), IntervalMonth.PeriodStart(at))
+
+	// 週日歸屬前一個週一
+	sunday := time.Date(2025, 5, 11, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC), IntervalWeek.PeriodStart(sunday))
+}
+
+func TestParseInterval(t *testing.T) {
+	interval, err := ParseInterval("")
+	require.NoError(t, err)
+	assert.Equal(t, IntervalDay, interval)
+
+	_, err = ParseInterval("year")
+	assert.Error(t, err)
+}
+
+func TestEntityCoverage(t *testing.T) {
+	taipei := time.FixedZone("Asia/Taipei", 8*60*60)
+	score := func(v float64) decimal.NullDecimal {
+		return decimal.NewNullDecimal(decimal.NewFromFloat(v))
+	}
+
+	rows := []*entity.EntityCoverageRow{
+		// UTC 5/6 17:00 為台北 5/7 01:00
+		{NewsID: "a", MediaID: 1, PublishedAt: time.Date(2025, 5, 6, 17, 0, 0, 0, time.UTC), Objectivity: score(2)},
+		{NewsID: "b", MediaID: 1, PublishedAt: time.Date(2025, 5, 7, 3, 0, 0, 0, time.UTC), Objectivity: score(3.25)},
+		{NewsID: "c", MediaID: 2, PublishedAt: time.Date(2025, 5, 7, 4, 0, 0, 0, time.UTC)},
+		{NewsID: "d", MediaID: 1, PublishedAt: time.Date(2025, 5, 8, 4, 0, 0, 0, time.UTC), Objectivity: score(4)},
+	}
+
+	avg := func(v float64) *float64 { return &v }
+
+	assert.Equal(t, []EntityCoveragePoint{
+		{Period: time.Date(2025, 5, 7, 0, 0, 0, 0, taipei), MediaID: 1, Articles: 2, Scored: 2, AvgObjectivity: avg(2.63)},
+		{Period: time.Date(2025, 5, 7, 0, 0, 0, 0, taipei), MediaID: 2, Articles: 1},
+		{Period: time.Date(2025, 5, 8, 0, 0, 0, 0, taipei), MediaID: 1, Articles: 1, Scored: 1, AvgObjectivity: avg(4)},
+	}, EntityCoverage(rows, IntervalDay, taipei))
+
+	assert.Equal(t, []EntityCoveragePoint{
+		{Period: time.Date(2025, 5, 5, 0, 0, 0, 0, taipei), MediaID: 1, Articles: 3, Scored: 3, AvgObjectivity: avg(3.08)},
+		{Period: time.Date(2025, 5, 5, 0, 0, 0, 0, taipei), MediaID: 2, Articles: 1},
+	}, EntityCoverage(rows, IntervalWeek, taipei))
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"itmrchow/tw-media-analytics-service/domain/category"
+	"itmrchow/tw-media-analytics-service/domain/ner"
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+	"itmrchow/tw-media-analytics-service/domain/utils"
+)
+
+// NewsSaver 儲存爬取的新聞: 對應統一分類, 與作者在同一個交易中儲存, 並擷取具名實體.
+// 爬蟲事件與 reparse 子命令共用, 只依賴儲存需要的元件.
+type NewsSaver struct {
+	logger *zerolog.Logger
+
+	// 作者與新聞需在同一個交易中儲存
+	uow        repository.UnitOfWork
+	entityRepo repository.NewsEntityRepository
+	// 統一分類
+	categories *category.Classifier
+	// 具名實體擷取
+	extractor *ner.Extractor
+
+	// metrics
+	savedCounter metric.Int64Counter
+}
+
+func NewNewsSaver(
+	logger *zerolog.Logger,
+	uow repository.UnitOfWork,
+	entityRepo repository.NewsEntityRepository,
+	categories *category.Classifier,
+	extractor *ner.Extractor,
+) *NewsSaver {
+	savedCounter, err := otel.Meter("domain/news").Int64Counter(
+		"news.articles.saved",
+		metric.WithDescription("Number of articles saved"),
+	)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create news.articles.saved counter")
+	}
+
+	return &NewsSaver{
+		logger:       logger,
+		uow:          uow,
+		entityRepo:   entityRepo,
+		categories:   categories,
+		extractor:    extractor,
+		savedCounter: savedCounter,
+	}
+}
+
+// SaveNews 分類並儲存新聞與作者, 再擷取具名實體.
+func (s *NewsSaver) SaveNews(ctx context.Context, saveNews utils.EventNewsSave) error {
+
+	// 對應至統一分類, AI 模型分類有自己的逾時, 不佔用儲存的時間
+	categoryResult := s.categories.Classify(ctx, saveNews.MediaID, saveNews.Category, saveNews.Title, saveNews.Content)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// event dto to news entity
+	news := &entity.News{
+		MediaID:     saveNews.MediaID,
+		NewsID:      saveNews.NewsID,
+		Title:       saveNews.Title,
+		Content:     saveNews.Content,
+		URL:         saveNews.URL,
+		PublishedAt: saveNews.PublishedAt,
+		Category:    saveNews.Category,
+
+		CategoryKey:    categoryResult.Key,
+		CategorySource: string(categoryResult.Source),
+	}
+
+	// 作者與新聞在同一個交易中儲存, 避免留下沒有新聞的作者
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		// get or create author
+		author := &entity.Author{
+			MediaID: saveNews.MediaID,
+			Name:    saveNews.AuthorName,
+		}
+		if err := repos.Author.FirstOrCreate(ctx, author); err != nil {
+			return fmt.Errorf("failed to get or create author: %w", err)
+		}
+
+		// save news
+		news.AuthorID = author.ID
+		if err := repos.News.SaveNews(ctx, news); err != nil {
+			return fmt.Errorf("failed to save news: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Ctx(ctx).Msg("failed to save news")
+		return err
+	}
+
+	s.savedCounter.Add(ctx, 1, metric.WithAttributes(attribute.Int64("media_id", int64(saveNews.MediaID))))
+
+	// 擷取具名實體, 失敗不影響新聞儲存
+	s.saveNewsEntities(ctx, news)
+
+	s.logger.Info().
+		Str("media_id", strconv.Itoa(int(saveNews.MediaID))).
+		Str("news_id", news.NewsID).
+		Str("category", news.CategoryKey).
+		Str("title", news.Title[:min(10, len(news.Title))]).
+		Msg("save news")
+
+	return nil
+}
+
+// saveNewsEntities 擷取新聞提及的人物, 政黨, 機關與企業並儲存.
+func (s *NewsSaver) saveNewsEntities(ctx context.Context, news *entity.News) {
+	mentions := s.extractor.Extract(news.Title, news.Content)
+
+	entityList := make([]entity.NewsEntity, 0, len(mentions))
+	for _, mention := range mentions {
+		entityList = append(entityList, entity.NewsEntity{
+			EntityID:   mention.EntityID,
+			EntityName: mention.EntityName,
+			EntityType: mention.EntityType,
+			Mentions:   mention.Count,
+			InTitle:    mention.InTitle,
+			Aliases:    strings.Join(mention.Aliases, ","),
+		})
+	}
+
+	if err := s.entityRepo.ReplaceNewsEntities(ctx, news.NewsID, news.MediaID, entityList); err != nil {
+		s.logger.Error().Err(err).Ctx(ctx).Str("news_id", news.NewsID).Msg("failed to save news entities")
+	}
+}
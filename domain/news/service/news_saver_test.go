@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-testfixtures/testfixtures/v3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/category"
+	"itmrchow/tw-media-analytics-service/domain/ner"
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+	"itmrchow/tw-media-analytics-service/domain/utils"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
+)
+
+func TestNewsSaver_SaveNews(t *testing.T) {
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	logger := zerolog.New(os.Stdout).Level(zerolog.InfoLevel)
+
+	ormDB := testdb.New(context.Background(), &logger, tracer)
+	sqlDB, err := ormDB.DB()
+	require.NoError(t, err)
+
+	// 與 repository 共用測試資料
+	fixtures, err := testfixtures.New(
+		testfixtures.Database(sqlDB),
+		testfixtures.Dialect(ormDB.Dialector.Name()),
+		testfixtures.Directory("../repository/testdata"),
+		testfixtures.DangerousSkipTestDatabaseCheck(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, fixtures.Load())
+
+	// 只以儲存需要的元件建立, 不需 AI 模型與事件發布
+	saver := NewNewsSaver(
+		&logger,
+		repository.NewUnitOfWorkImpl(&logger, ormDB),
+		repository.NewNewsEntityRepositoryImpl(&logger, ormDB),
+		category.NewClassifier(&logger, nil),
+		ner.NewExtractor(&logger),
+	)
+
+	err = saver.SaveNews(context.Background(), utils.EventNewsSave{
+		MediaID:     2,
+		NewsID:      "saver-1",
+		Title:       "民進黨團提出新法案",
+		Content:     "民進黨團今天在立法院提出法案.",
+		URL:         "https://test.com/news/saver-1",
+		AuthorName:  "saver author",
+		PublishedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+		Category:    "政治新聞",
+	})
+	require.NoError(t, err)
+
+	var news entity.News
+	require.NoError(t, ormDB.Preload("Author").First(&news, "news_id = ? AND media_id = ?", "saver-1", 2).Error)
+	assert.Equal(t, "politics", news.CategoryKey)
+	assert.Equal(t, string(category.SourceMapping), news.CategorySource)
+	assert.Equal(t, "saver author", news.Author.Name)
+
+	var entityIDList []string
+	require.NoError(t, ormDB.Model(&entity.NewsEntity{}).Where("news_id = ? AND media_id = ?", "saver-1", 2).
+		Order("entity_id").Pluck("entity_id", &entityIDList).Error)
+	assert.Equal(t, []string{"dpp", "legislative-yuan"}, entityIDList)
+}
@@ -3,17 +3,20 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strconv"
-	"time"
 
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
-	"gorm.io/gorm"
 
 	"itmrchow/tw-media-analytics-service/domain/ai"
+	"itmrchow/tw-media-analytics-service/domain/ai/dto"
+	"itmrchow/tw-media-analytics-service/domain/clickbait"
 	"itmrchow/tw-media-analytics-service/domain/news/entity"
 	"itmrchow/tw-media-analytics-service/domain/news/repository"
 	"itmrchow/tw-media-analytics-service/domain/queue"
@@ -30,33 +33,68 @@ type NewsServiceImpl struct {
 
 	// repo
 	newsRepo     repository.NewsRepository
-	authorRepo   repository.AuthorRepository
 	analysisRepo repository.AnalysisRepository
-	// db
-	db *gorm.DB
+	summaryRepo  repository.NewsSummaryRepository
+	// 儲存新聞
+	saver *NewsSaver
 	// ai model
 	aiModel ai.AiModel
+	// 政治立場與框架分析, nil 代表不分析
+	framing ai.FramingModel
+	// 摘要與事實陳述, nil 代表不產生
+	summarizer ai.SummaryModel
+	// 規則式標題評分
+	clickbait *clickbait.Analyzer
+	// 人工審核佇列
+	reviewQueue ReviewQueue
 }
 
 func NewNewsServiceImpl(
 	logger *zerolog.Logger,
 	tracer trace.Tracer,
 	newsRepo repository.NewsRepository,
-	authorRepo repository.AuthorRepository,
 	analysisRepo repository.AnalysisRepository,
+	summaryRepo repository.NewsSummaryRepository,
+	saver *NewsSaver,
 	publisher message.Publisher,
-	db *gorm.DB,
 	aiModel ai.AiModel,
+	framing ai.FramingModel,
+	summarizer ai.SummaryModel,
+	clickbait *clickbait.Analyzer,
+	reviewQueue ReviewQueue,
 ) *NewsServiceImpl {
-	return &NewsServiceImpl{
+	s := &NewsServiceImpl{
 		logger:       logger,
 		newsRepo:     newsRepo,
-		authorRepo:   authorRepo,
 		analysisRepo: analysisRepo,
+		summaryRepo:  summaryRepo,
+		saver:        saver,
 		publisher:    publisher,
-		db:           db,
 		aiModel:      aiModel,
+		framing:      framing,
+		summarizer:   summarizer,
+		clickbait:    clickbait,
+		reviewQueue:  reviewQueue,
 	}
+
+	// metrics
+	_, err := otel.Meter("domain/news").Int64ObservableGauge(
+		"news.analysis.backlog",
+		metric.WithDescription("Number of saved articles waiting for analysis"),
+		metric.WithInt64Callback(func(ctx context.Context, o metric.Int64Observer) error {
+			count, err := newsRepo.CountNonAnalysisNews(ctx)
+			if err != nil {
+				return err
+			}
+			o.Observe(count)
+			return nil
+		}),
+	)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create news.analysis.backlog gauge")
+	}
+
+	return s
 }
 
 // CheckNewsExist 檢查文章是否存在.
@@ -65,7 +103,7 @@ func (s *NewsServiceImpl) CheckNewsExist(ctx context.Context, checkNews utils.Ev
 	s.logger.Info().Msg("check news exist start")
 
 	// check news id exist in db
-	nonExistingNewsIDs, err := s.newsRepo.FindNonExistingNewsIDs(checkNews.MediaID, checkNews.NewsIDList)
+	nonExistingNewsIDs, err := s.newsRepo.FindNonExistingNewsIDs(ctx, checkNews.MediaID, checkNews.NewsIDList)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("failed to find non existing news ids")
 		return err
@@ -87,6 +125,8 @@ func (s *NewsServiceImpl) CheckNewsExist(ctx context.Context, checkNews utils.Ev
 		scrapingContentEvent := utils.EventArticleContentScraping{
 			MediaID: checkNews.MediaID,
 			NewsID:  newsID,
+			RunID:   checkNews.RunID,
+			RunSize: len(nonExistingNewsIDs),
 		}
 
 		jsonData, err := json.Marshal(scrapingContentEvent)
@@ -116,45 +156,7 @@ func (s *NewsServiceImpl) CheckNewsExist(ctx context.Context, checkNews utils.Ev
 
 // 保存新聞sub handler
 func (s *NewsServiceImpl) SaveNews(ctx context.Context, saveNews utils.EventNewsSave) error {
-
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	// get or create author
-	author := &entity.Author{
-		MediaID: saveNews.MediaID,
-		Name:    saveNews.AuthorName,
-	}
-	if err := s.authorRepo.FirstOrCreate(ctx, author); err != nil {
-		s.logger.Error().Err(err).Msg("failed to get or create author")
-		return err
-	}
-
-	// event dto to news entity
-	news := &entity.News{
-		MediaID:     saveNews.MediaID,
-		NewsID:      saveNews.NewsID,
-		Title:       saveNews.Title,
-		Content:     saveNews.Content,
-		URL:         saveNews.URL,
-		AuthorID:    author.ID,
-		PublishedAt: saveNews.PublishedAt,
-		Category:    saveNews.Category,
-	}
-
-	// save news
-	if err := s.newsRepo.SaveNews(news); err != nil {
-		s.logger.Error().Err(err).Msg("failed to save news")
-		return err
-	}
-
-	s.logger.Info().
-		Str("media_id", strconv.Itoa(int(saveNews.MediaID))).
-		Str("news_id", news.NewsID).
-		Str("title", news.Title[:min(10, len(news.Title))]).
-		Msg("save news")
-
-	return nil
+	return s.saver.SaveNews(ctx, saveNews)
 }
 
 // 分析新聞sub handler
@@ -163,7 +165,7 @@ func (s *NewsServiceImpl) AnalysisNews(ctx context.Context, analysisNews utils.E
 	s.logger.Info().Msgf("news analysis start , analysis num: %d", analysisNews.AnalysisNum)
 
 	// get news is not analysis
-	nonAnalysisNews, err := s.newsRepo.FindNonAnalysisNews(analysisNews.AnalysisNum)
+	nonAnalysisNews, err := s.newsRepo.FindNonAnalysisNews(ctx, analysisNews.AnalysisNum)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("failed to find non analysis news")
 		return err
@@ -175,7 +177,11 @@ func (s *NewsServiceImpl) AnalysisNews(ctx context.Context, analysisNews utils.E
 		s.logger.Info().Msgf("analysis news to ai model: %s", news.Title)
 
 		// send msg to ai model
-		analysis, analysisErr := s.aiModel.AnalyzeNews(news.Title, news.Content)
+		analysis, analysisErr := s.aiModel.AnalyzeNews(ctx, news.Title, news.Content)
+		if errors.Is(analysisErr, ai.ErrBudgetExceeded) {
+			s.logger.Warn().Err(analysisErr).Msg("ai budget exceeded, pause news analysis")
+			break
+		}
 		if analysisErr != nil {
 			s.logger.Error().Err(analysisErr).Msg("failed to analyze news")
 			continue
@@ -183,6 +189,16 @@ func (s *NewsServiceImpl) AnalysisNews(ctx context.Context, analysisNews utils.E
 
 		s.logger.Debug().Interface("analysis", analysis).Msg("ai model analysis news")
 
+		// 標記疑似 prompt injection 的新聞
+		injectionPatterns := ai.DetectNewsInjection(news.Title, news.Content)
+		injectionSuspected := len(injectionPatterns) > 0
+		if injectionSuspected {
+			s.logger.Warn().
+				Str("news_id", news.NewsID).
+				Strs("patterns", injectionPatterns).
+				Msg("news content suspected of prompt injection")
+		}
+
 		// to entity
 		titleAnalysis := entity.Analysis{
 			NewsID:              news.NewsID,
@@ -190,6 +206,7 @@ func (s *NewsServiceImpl) AnalysisNews(ctx context.Context, analysisNews utils.E
 			Type:                entity.AnalysisTypeTitle,
 			Score:               decimal.NewFromFloat(analysis.TitleAnalytics.Score),
 			Reason:              analysis.TitleAnalytics.Reason,
+			InjectionSuspected:  injectionSuspected,
 			AnalysisMetricsList: []entity.AnalysisMetric{},
 		}
 		for _, metric := range analysis.TitleAnalytics.MetricList {
@@ -197,16 +214,40 @@ func (s *NewsServiceImpl) AnalysisNews(ctx context.Context, analysisNews utils.E
 				MetricKey: metric.MetricKey,
 				Score:     decimal.NewFromFloat(metric.Score),
 				Reason:    metric.Reason,
+				Variance:  decimal.NewFromFloat(metric.Variance),
 			})
 		}
+		titleAnalysis.RunList = toAnalysisRunList(analysis.RunList, func(run dto.ModelRun) dto.Analytics {
+			return run.TitleAnalytics
+		})
 		analysisList = append(analysisList, titleAnalysis)
 
+		// 規則式標題評分, 與 LLM 結果並存以便比對
+		titleRule := s.clickbait.Analyze(news.Title)
+		titleRuleAnalysis := entity.Analysis{
+			NewsID:              news.NewsID,
+			MediaID:             news.MediaID,
+			Type:                entity.AnalysisTypeTitleRule,
+			Score:               decimal.NewFromFloat(titleRule.Score),
+			Reason:              titleRule.Reason(),
+			AnalysisMetricsList: []entity.AnalysisMetric{},
+		}
+		for _, metric := range titleRule.MetricList {
+			titleRuleAnalysis.AnalysisMetricsList = append(titleRuleAnalysis.AnalysisMetricsList, entity.AnalysisMetric{
+				MetricKey: metric.MetricKey,
+				Score:     decimal.NewFromFloat(metric.Score),
+				Reason:    metric.Reason(),
+			})
+		}
+		analysisList = append(analysisList, titleRuleAnalysis)
+
 		contentAnalysis := entity.Analysis{
 			NewsID:              news.NewsID,
 			MediaID:             news.MediaID,
 			Type:                entity.AnalysisTypeContent,
 			Score:               decimal.NewFromFloat(analysis.ContentAnalytics.Score),
 			Reason:              analysis.ContentAnalytics.Reason,
+			InjectionSuspected:  injectionSuspected,
 			AnalysisMetricsList: []entity.AnalysisMetric{},
 		}
 		for _, metric := range analysis.ContentAnalytics.MetricList {
@@ -214,18 +255,141 @@ func (s *NewsServiceImpl) AnalysisNews(ctx context.Context, analysisNews utils.E
 				MetricKey: metric.MetricKey,
 				Score:     decimal.NewFromFloat(metric.Score),
 				Reason:    metric.Reason,
+				Variance:  decimal.NewFromFloat(metric.Variance),
 			})
 		}
+		contentAnalysis.RunList = toAnalysisRunList(analysis.RunList, func(run dto.ModelRun) dto.Analytics {
+			return run.ContentAnalytics
+		})
 		analysisList = append(analysisList, contentAnalysis)
+
+		// 框架分析另存為 framing, 失敗不影響品質評分
+		if framingAnalysis := s.analyzeFraming(ctx, news, injectionSuspected); framingAnalysis != nil {
+			analysisList = append(analysisList, *framingAnalysis)
+		}
 	}
 
 	// save analysis to db
 
-	err = s.analysisRepo.SaveAnalysisList(analysisList)
+	err = s.analysisRepo.SaveAnalysisList(ctx, analysisList)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("failed to save analysis")
 		return err
 	}
 
+	// 產生摘要與事實陳述, 失敗不影響分析結果
+	if s.summarizer != nil {
+		analyzed := map[string]bool{}
+		for _, analysis := range analysisList {
+			analyzed[strconv.Itoa(int(analysis.MediaID))+"-"+analysis.NewsID] = true
+		}
+		for _, news := range nonAnalysisNews {
+			if analyzed[strconv.Itoa(int(news.MediaID))+"-"+news.NewsID] {
+				s.summarizeNews(ctx, news)
+			}
+		}
+	}
+
+	// 低信心的分析加入人工審核佇列, 失敗不影響分析結果
+	newsAnalysisMap := map[string][]entity.Analysis{}
+	var newsKeys []string
+	for _, analysis := range analysisList {
+		key := strconv.Itoa(int(analysis.MediaID)) + "-" + analysis.NewsID
+		if _, ok := newsAnalysisMap[key]; !ok {
+			newsKeys = append(newsKeys, key)
+		}
+		newsAnalysisMap[key] = append(newsAnalysisMap[key], analysis)
+	}
+	for _, key := range newsKeys {
+		if err = s.reviewQueue.EnqueueLowConfidence(ctx, newsAnalysisMap[key]); err != nil {
+			s.logger.Error().Err(err).Ctx(ctx).Str("news", key).Msg("failed to enqueue low confidence analysis")
+		}
+	}
+
 	return nil
 }
+
+// analyzeFraming 分析新聞的政治立場與框架, 未啟用或分析失敗時回傳 nil.
+func (s *NewsServiceImpl) analyzeFraming(ctx context.Context, news *entity.News, injectionSuspected bool) *entity.Analysis {
+	if s.framing == nil {
+		return nil
+	}
+
+	framing, err := s.framing.AnalyzeFraming(ctx, news.Title, news.Content)
+	if err != nil {
+		s.logger.Error().Err(err).Ctx(ctx).Str("news_id", news.NewsID).Msg("failed to analyze news framing")
+		return nil
+	}
+
+	analysis := &entity.Analysis{
+		NewsID:              news.NewsID,
+		MediaID:             news.MediaID,
+		Type:                entity.AnalysisTypeFraming,
+		Score:               decimal.NewFromFloat(framing.Score),
+		Reason:              framing.Reason,
+		InjectionSuspected:  injectionSuspected,
+		AnalysisMetricsList: []entity.AnalysisMetric{},
+	}
+	for _, metric := range framing.MetricList {
+		analysis.AnalysisMetricsList = append(analysis.AnalysisMetricsList, entity.AnalysisMetric{
+			MetricKey: metric.MetricKey,
+			Score:     decimal.NewFromFloat(metric.Score),
+			Reason:    metric.Reason,
+		})
+	}
+	return analysis
+}
+
+// summarizeNews 產生新聞的中立摘要與可查核的事實陳述並儲存, 找出引文在內容中的位置.
+func (s *NewsServiceImpl) summarizeNews(ctx context.Context, news *entity.News) {
+	result, err := s.summarizer.SummarizeNews(ctx, news.Title, news.Content)
+	if err != nil {
+		s.logger.Error().Err(err).Ctx(ctx).Str("news_id", news.NewsID).Msg("failed to summarize news")
+		return
+	}
+
+	summary := &entity.NewsSummary{
+		NewsID:    news.NewsID,
+		MediaID:   news.MediaID,
+		Summary:   result.Summary,
+		ClaimList: make([]entity.NewsClaim, 0, len(result.ClaimList)),
+	}
+	for i, claim := range result.ClaimList {
+		newsClaim := entity.NewsClaim{
+			Seq:   i + 1,
+			Claim: claim.Claim,
+			Quote: claim.Quote,
+		}
+		// 儲存實際比對到的文字, 讓 quote 與 quote_start, quote_end 指向的內容一致
+		if located, start, end, ok := ai.LocateQuote(news.Content, claim.Quote); ok {
+			newsClaim.Quote = located
+			newsClaim.QuoteStart, newsClaim.QuoteEnd = &start, &end
+		}
+		summary.ClaimList = append(summary.ClaimList, newsClaim)
+	}
+
+	if err = s.summaryRepo.ReplaceNewsSummary(ctx, summary); err != nil {
+		s.logger.Error().Err(err).Ctx(ctx).Str("news_id", news.NewsID).Msg("failed to save news summary")
+	}
+}
+
+// toAnalysisRunList 多模型評分的各次結果轉為 entity, analytics 取出標題或內容的評分.
+func toAnalysisRunList(runList []dto.ModelRun, analytics func(run dto.ModelRun) dto.Analytics) []entity.AnalysisRun {
+	if len(runList) == 0 {
+		return nil
+	}
+
+	result := make([]entity.AnalysisRun, 0, len(runList))
+	for _, run := range runList {
+		a := analytics(run)
+		metricList, _ := json.Marshal(a.MetricList)
+		result = append(result, entity.AnalysisRun{
+			Model:      run.Model,
+			Run:        run.Run,
+			Score:      decimal.NewFromFloat(a.Score),
+			Reason:     a.Reason,
+			MetricList: string(metricList),
+		})
+	}
+	return result
+}
@@ -3,9 +3,16 @@ package service
 import (
 	"context"
 
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
 	"itmrchow/tw-media-analytics-service/domain/utils"
 )
 
+// ReviewQueue 將低信心的分析加入人工審核佇列.
+type ReviewQueue interface {
+	// analysisList 為同一篇新聞已儲存的分析
+	EnqueueLowConfidence(ctx context.Context, analysisList []entity.Analysis) error
+}
+
 type NewsService interface {
 
 	// 檢查新聞是否存在
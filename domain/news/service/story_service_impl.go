@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+	"itmrchow/tw-media-analytics-service/domain/story"
+	"itmrchow/tw-media-analytics-service/domain/utils"
+)
+
+// StoryView 事件與各媒體的報導, 依發布時間排序.
+type StoryView struct {
+	ID               uint               `json:"id"`
+	Title            string             `json:"title"`
+	FirstPublishedAt time.Time          `json:"firstPublishedAt"`
+	LastPublishedAt  time.Time          `json:"lastPublishedAt"`
+	MediaCount       int                `json:"mediaCount"`
+	Articles         []StoryArticleView `json:"articles"`
+}
+
+// StoryArticleView 單一媒體對事件的報導.
+type StoryArticleView struct {
+	MediaID      uint      `json:"mediaId"`
+	MediaName    string    `json:"mediaName"`
+	NewsID       string    `json:"newsId"`
+	Headline     string    `json:"headline"`
+	URL          string    `json:"url"`
+	PublishedAt  time.Time `json:"publishedAt"`
+	LagMinutes   int       `json:"lagMinutes"` // 與事件首次報導的時間差
+	Similarity   float64   `json:"similarity"`
+	TitleScore   *float64  `json:"titleScore,omitempty"`   // 尚未分析時為 nil
+	ContentScore *float64  `json:"contentScore,omitempty"` // 尚未分析時為 nil
+}
+
+var _ StoryService = &StoryServiceImpl{}
+
+type StoryServiceImpl struct {
+	logger    *zerolog.Logger
+	tracer    trace.Tracer
+	storyRepo repository.StoryRepository
+	cfg       story.Config
+	now       func() time.Time
+}
+
+func NewStoryServiceImpl(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	storyRepo repository.StoryRepository,
+) *StoryServiceImpl {
+	return &StoryServiceImpl{
+		logger:    logger,
+		tracer:    tracer,
+		storyRepo: storyRepo,
+		cfg:       story.NewConfig(),
+		now:       time.Now,
+	}
+}
+
+// ClusterStories 分群最近 Lookback 內發布的新聞, 已歸入事件的新聞不會改變.
+func (s *StoryServiceImpl) ClusterStories(ctx context.Context, _ utils.EventStoryClustering) error {
+	ctx, span := s.tracer.Start(ctx, "domain/news/service/story_service_impl/ClusterStories: Cluster Stories")
+	defer span.End()
+
+	rows, err := s.storyRepo.FindStoryCandidates(ctx, s.now().Add(-s.cfg.Lookback))
+	if err != nil {
+		return err
+	}
+
+	articles := make([]*story.Article, 0, len(rows))
+	for _, row := range rows {
+		articles = append(articles, &story.Article{
+			NewsID:      row.NewsID,
+			MediaID:     row.MediaID,
+			Title:       row.Title,
+			Content:     row.Content,
+			PublishedAt: row.PublishedAt,
+			StoryID:     row.StoryID,
+		})
+	}
+
+	clusters := story.Group(articles, s.cfg)
+	for _, c := range clusters {
+		members := c.NewMembers()
+		articleList := make([]entity.StoryArticle, 0, len(members))
+		for _, m := range members {
+			articleList = append(articleList, entity.StoryArticle{
+				NewsID:     m.Article.NewsID,
+				MediaID:    m.Article.MediaID,
+				Similarity: math.Round(m.Similarity*1000) / 1000,
+			})
+		}
+
+		if _, err = s.storyRepo.AddStoryArticles(ctx, c.StoryID, articleList); err != nil {
+			return fmt.Errorf("failed to save story %d: %w", c.StoryID, err)
+		}
+	}
+
+	s.logger.Info().Ctx(ctx).Int("articles", len(articles)).Int("stories", len(clusters)).Msg("stories clustered")
+	return nil
+}
+
+func (s *StoryServiceImpl) ListStories(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	minMedia int,
+	limit int,
+) ([]StoryView, error) {
+	ctx, span := s.tracer.Start(ctx, "domain/news/service/story_service_impl/ListStories: List Stories")
+	defer span.End()
+
+	stories, err := s.storyRepo.FindStories(ctx, from, to, minMedia, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toStoryViews(ctx, stories)
+}
+
+func (s *StoryServiceImpl) GetStory(ctx context.Context, storyID uint) (*StoryView, error) {
+	ctx, span := s.tracer.Start(ctx, "domain/news/service/story_service_impl/GetStory: Get Story")
+	defer span.End()
+
+	st, err := s.storyRepo.GetStory(ctx, storyID)
+	if err != nil || st == nil {
+		return nil, err
+	}
+
+	views, err := s.toStoryViews(ctx, []*entity.Story{st})
+	if err != nil {
+		return nil, err
+	}
+	return &views[0], nil
+}
+
+func (s *StoryServiceImpl) toStoryViews(ctx context.Context, stories []*entity.Story) ([]StoryView, error) {
+	views := make([]StoryView, 0, len(stories))
+	if len(stories) == 0 {
+		return views, nil
+	}
+
+	idList := make([]uint, 0, len(stories))
+	for _, st := range stories {
+		idList = append(idList, st.ID)
+	}
+	rows, err := s.storyRepo.FindStoryArticles(ctx, idList)
+	if err != nil {
+		return nil, err
+	}
+
+	byStory := map[uint][]*entity.StoryArticleRow{}
+	for _, row := range rows {
+		byStory[row.StoryID] = append(byStory[row.StoryID], row)
+	}
+
+	for _, st := range stories {
+		views = append(views, toStoryView(st, byStory[st.ID]))
+	}
+	return views, nil
+}
+
+// toStoryView 組合事件與報導, rows 需依發布時間排序.
+func toStoryView(st *entity.Story, rows []*entity.StoryArticleRow) StoryView {
+	view := StoryView{
+		ID:               st.ID,
+		Title:            st.Title,
+		FirstPublishedAt: st.FirstPublishedAt,
+		LastPublishedAt:  st.LastPublishedAt,
+		MediaCount:       st.MediaCount,
+		Articles:         make([]StoryArticleView, 0, len(rows)),
+	}
+
+	for _, row := range rows {
+		view.Articles = append(view.Articles, StoryArticleView{
+			MediaID:      row.MediaID,
+			MediaName:    row.MediaName,
+			NewsID:       row.NewsID,
+			Headline:     row.Title,
+			URL:          row.URL,
+			PublishedAt:  row.PublishedAt,
+			LagMinutes:   int(row.PublishedAt.Sub(st.FirstPublishedAt).Minutes()),
+			Similarity:   row.Similarity,
+			TitleScore:   nullScore(row.TitleScore),
+			ContentScore: nullScore(row.ContentScore),
+		})
+	}
+
+	return view
+}
+
+func nullScore(score decimal.NullDecimal) *float64 {
+	if !score.Valid {
+		return nil
+	}
+	f := score.Decimal.InexactFloat64()
+	return &f
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"itmrchow/tw-media-analytics-service/domain/utils"
+)
+
+type StoryService interface {
+	// 將最近發布的新聞分群為跨媒體事件
+	ClusterStories(ctx context.Context, event utils.EventStoryClustering) error
+
+	// 期間內首次報導且至少 minMedia 家媒體報導的事件
+	ListStories(ctx context.Context, from time.Time, to time.Time, minMedia int, limit int) ([]StoryView, error)
+
+	// 單一事件, 不存在時回傳 nil
+	GetStory(ctx context.Context, storyID uint) (*StoryView, error)
+}
@@ -26,6 +26,12 @@ const (
 	// analysis news flow
 	TopicGetAnalysis  QueueTopic = "analysis_get"  // 取得分析
 	TopicAnalysisSave QueueTopic = "analysis_save" // 分析保存
+
+	// embedding flow
+	TopicNewsEmbedding QueueTopic = "news_embedding" // 新聞向量
+
+	// story clustering flow
+	TopicStoryClustering QueueTopic = "story_clustering" // 跨媒體事件分群
 )
 
 func GetTopics() []QueueTopic {
@@ -36,5 +42,7 @@ func GetTopics() []QueueTopic {
 		TopicNewsSave,
 		TopicGetAnalysis,
 		TopicAnalysisSave,
+		TopicNewsEmbedding,
+		TopicStoryClustering,
 	}
 }
@@ -0,0 +1,245 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/review/dto"
+	"itmrchow/tw-media-analytics-service/domain/review/entity"
+	"itmrchow/tw-media-analytics-service/domain/review/service"
+	"itmrchow/tw-media-analytics-service/domain/utils/server"
+)
+
+const defaultQueueLimit = 50
+
+type ReviewHandler struct {
+	tracer  trace.Tracer
+	logger  *zerolog.Logger
+	service service.ReviewService
+
+	// 前置驗證 proxy 設定的使用者 (REVIEW_AUTH_HEADER), 作為審核者
+	auth *server.Authenticator
+}
+
+func NewReviewHandler(logger *zerolog.Logger, tracer trace.Tracer, service service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		tracer:  tracer,
+		logger:  logger,
+		service: service,
+		auth:    server.NewAuthenticator("REVIEW_AUTH_HEADER"),
+	}
+}
+
+// Register 註冊審核 API, 所有端點都需要驗證 header.
+func (h *ReviewHandler) Register(mux *http.ServeMux) {
+	mux.Handle("GET /api/reviews/queue", h.auth.Authenticate(h.GetQueue))
+	mux.Handle("POST /api/reviews/items/{id}/resolve", h.auth.Authenticate(h.Resolve))
+	mux.Handle("GET /api/reviews/analyses/{id}", h.auth.Authenticate(h.GetEffectiveAnalysis))
+	mux.Handle("GET /api/reviews/analyses/{id}/audits", h.auth.Authenticate(h.GetAudits))
+	mux.Handle("POST /api/reviews/analyses/{id}/dispute", h.auth.Authenticate(h.Dispute))
+	mux.Handle("PUT /api/reviews/analyses/{id}/metrics/{key}", h.auth.Authenticate(h.OverrideMetric))
+	mux.Handle("DELETE /api/reviews/analyses/{id}/metrics/{key}", h.auth.Authenticate(h.RevertMetric))
+	mux.Handle("GET /api/reviews/export", h.auth.Authenticate(h.Export))
+}
+
+// GetQueue 取得審核佇列.
+// GET /api/reviews/queue?status=pending&limit=50
+func (h *ReviewHandler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/review/delivery/review_handler/GetQueue: Get Review Queue")
+	defer span.End()
+
+	status := entity.ReviewStatus(r.URL.Query().Get("status"))
+	if status == "" {
+		status = entity.ReviewStatusPending
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultQueueLimit
+	}
+
+	items, err := h.service.Queue(ctx, status, limit)
+	h.writeResult(w, r, items, err)
+}
+
+// Resolve 完成審核.
+// POST /api/reviews/items/{id}/resolve
+func (h *ReviewHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/review/delivery/review_handler/Resolve: Resolve Review Item")
+	defer span.End()
+
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.ReviewerRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	req.Reviewer = server.AuthenticatedUser(r.Context())
+
+	item, err := h.service.Resolve(ctx, id, req)
+	h.writeResult(w, r, item, err)
+}
+
+// GetEffectiveAnalysis 取得有效分數, 人工分數優先.
+// GET /api/reviews/analyses/{id}
+func (h *ReviewHandler) GetEffectiveAnalysis(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/review/delivery/review_handler/GetEffectiveAnalysis: Get Effective Analysis")
+	defer span.End()
+
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	analysis, err := h.service.EffectiveAnalysis(ctx, id)
+	h.writeResult(w, r, analysis, err)
+}
+
+// GetAudits 取得審核紀錄.
+// GET /api/reviews/analyses/{id}/audits
+func (h *ReviewHandler) GetAudits(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/review/delivery/review_handler/GetAudits: Get Review Audits")
+	defer span.End()
+
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	audits, err := h.service.Audits(ctx, id)
+	h.writeResult(w, r, audits, err)
+}
+
+// Dispute 提出異議.
+// POST /api/reviews/analyses/{id}/dispute
+func (h *ReviewHandler) Dispute(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/review/delivery/review_handler/Dispute: Dispute Analysis")
+	defer span.End()
+
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.ReviewerRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	req.Reviewer = server.AuthenticatedUser(r.Context())
+
+	item, err := h.service.Dispute(ctx, id, req)
+	h.writeResult(w, r, item, err)
+}
+
+// OverrideMetric 人工修正指標分數.
+// PUT /api/reviews/analyses/{id}/metrics/{key}
+func (h *ReviewHandler) OverrideMetric(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/review/delivery/review_handler/OverrideMetric: Override Metric")
+	defer span.End()
+
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.OverrideRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	req.Reviewer = server.AuthenticatedUser(r.Context())
+
+	analysis, err := h.service.OverrideMetric(ctx, id, r.PathValue("key"), req)
+	h.writeResult(w, r, analysis, err)
+}
+
+// RevertMetric 移除人工分數.
+// DELETE /api/reviews/analyses/{id}/metrics/{key}
+func (h *ReviewHandler) RevertMetric(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/review/delivery/review_handler/RevertMetric: Revert Metric")
+	defer span.End()
+
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.ReviewerRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	req.Reviewer = server.AuthenticatedUser(r.Context())
+
+	analysis, err := h.service.RevertMetric(ctx, id, r.PathValue("key"), req)
+	h.writeResult(w, r, analysis, err)
+}
+
+// Export 匯出人工分數為評估資料 (JSONL), 預設為最近 30 天.
+// GET /api/reviews/export?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z
+func (h *ReviewHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "domain/review/delivery/review_handler/Export: Export Overrides")
+	defer span.End()
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	for name, t := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "invalid "+name+", use RFC3339", http.StatusBadRequest)
+			return
+		}
+		*t = parsed
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	n, err := h.service.Export(ctx, from, to, w)
+	if err != nil {
+		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to export overrides")
+		return
+	}
+	h.logger.Info().Ctx(ctx).Int("samples", n).Msg("overrides exported")
+}
+
+func (h *ReviewHandler) pathID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 0)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *ReviewHandler) decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func (h *ReviewHandler) writeResult(w http.ResponseWriter, r *http.Request, result any, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrInvalidRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		h.logger.Error().Err(err).Ctx(r.Context()).Str("path", r.URL.Path).Msg("review api failed")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error().Err(err).Ctx(r.Context()).Msg("failed to write response")
+	}
+}
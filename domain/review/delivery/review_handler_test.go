@@ -0,0 +1,56 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/review/dto"
+	"itmrchow/tw-media-analytics-service/domain/review/entity"
+	"itmrchow/tw-media-analytics-service/domain/review/service"
+	"itmrchow/tw-media-analytics-service/domain/utils/server"
+)
+
+// fakeReviewService 只實作 Dispute, 記錄收到的請求.
+type fakeReviewService struct {
+	service.ReviewService
+
+	req *dto.ReviewerRequest
+}
+
+func (s *fakeReviewService) Dispute(_ context.Context, analysisID uint, req dto.ReviewerRequest) (*entity.ReviewItem, error) {
+	s.req = &req
+	return &entity.ReviewItem{AnalysisID: analysisID}, nil
+}
+
+func TestReviewHandler_Authenticate(t *testing.T) {
+	logger := zerolog.Nop()
+	svc := &fakeReviewService{}
+	mux := http.NewServeMux()
+	NewReviewHandler(&logger, otel.Tracer("tw-media-analytics-service_test"), svc).Register(mux)
+
+	// 缺少驗證 header
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/reviews/analyses/1/dispute",
+		strings.NewReader(`{"reviewer":"someone","note":"分數偏高"}`)))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Nil(t, svc.req)
+
+	// 審核者取自驗證 header, 忽略 body 中的 reviewer
+	req := httptest.NewRequest(http.MethodPost, "/api/reviews/analyses/1/dispute",
+		strings.NewReader(`{"reviewer":"someone","note":"分數偏高"}`))
+	req.Header.Set(server.DefaultAuthHeader, "editor@example.com")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.NotNil(t, svc.req) {
+		assert.Equal(t, "editor@example.com", svc.req.Reviewer)
+		assert.Equal(t, "分數偏高", svc.req.Note)
+	}
+}
@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// OverrideRequest 人工修正指標分數.
+type OverrideRequest struct {
+	Score    float64 `json:"score"`
+	Reason   string  `json:"reason"` // 空白代表沿用 AI 評語
+	Reviewer string  `json:"-"`      // 由驗證 header 取得, 不接受 request body
+	Comment  string  `json:"comment"`
+}
+
+// ReviewerRequest 異議, 完成審核與移除人工分數的請求.
+type ReviewerRequest struct {
+	Reviewer string `json:"-"` // 由驗證 header 取得, 不接受 request body
+	Note     string `json:"note"`
+}
+
+// EffectiveMetric 指標的有效分數, 有人工分數時優先使用.
+type EffectiveMetric struct {
+	MetricKey  string     `json:"metricKey"`
+	Score      float64    `json:"score"`
+	Reason     string     `json:"reason"`
+	AiScore    float64    `json:"aiScore"`
+	AiReason   string     `json:"aiReason"`
+	Overridden bool       `json:"overridden"`
+	Reviewer   string     `json:"reviewer,omitempty"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+}
+
+// EffectiveAnalysis 分析的有效分數, 有人工修正時總分以有效指標平均重新計算 (框架分析維持 AI 總分).
+type EffectiveAnalysis struct {
+	AnalysisID         uint              `json:"analysisId"`
+	NewsID             string            `json:"newsId"`
+	MediaID            uint              `json:"mediaId"`
+	Type               string            `json:"type"`
+	Score              float64           `json:"score"`
+	AiScore            float64           `json:"aiScore"`
+	Reason             string            `json:"reason"`
+	Overridden         bool              `json:"overridden"`
+	InjectionSuspected bool              `json:"injectionSuspected"`
+	Metrics            []EffectiveMetric `json:"metrics"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	newsEntity "itmrchow/tw-media-analytics-service/domain/news/entity"
+)
+
+// MetricOverride 編輯人工修正的指標分數與評語, 每個分析指標最多一筆, 歷次修改記錄於 ReviewAudit.
+type MetricOverride struct {
+	ID         uint            `json:"id" gorm:"primaryKey"`
+	AnalysisID uint            `json:"analysis_id" gorm:"not null;uniqueIndex:idx_override_analysis_metric"`
+	MetricKey  string          `json:"metric_key" gorm:"type:varchar(255);not null;uniqueIndex:idx_override_analysis_metric"`
+	Score      decimal.Decimal `json:"score" gorm:"type:decimal(10,2);not null"`
+	Reason     string          `json:"reason" gorm:"type:text;not null"`
+	Reviewer   string          `json:"reviewer" gorm:"type:varchar(255);not null"`
+	ReviewedAt time.Time       `json:"reviewed_at" gorm:"not null;index"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+
+	// Relations
+	Analysis newsEntity.Analysis `json:"-" gorm:"foreignKey:AnalysisID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
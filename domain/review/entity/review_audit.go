@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AuditAction 審核紀錄的動作.
+type AuditAction string
+
+const (
+	AuditActionOverride AuditAction = "override" // 新增或修改人工分數
+	AuditActionRevert   AuditAction = "revert"   // 移除人工分數, 恢復 AI 分數
+	AuditActionDispute  AuditAction = "dispute"  // 提出異議, 加入審核佇列
+	AuditActionResolve  AuditAction = "resolve"  // 審核完成
+)
+
+// ReviewAudit 審核的每一次變更, 只新增不修改.
+type ReviewAudit struct {
+	ID         uint                `json:"id" gorm:"primaryKey"`
+	AnalysisID uint                `json:"analysis_id" gorm:"not null;index"`
+	MetricKey  string              `json:"metric_key,omitempty" gorm:"type:varchar(255);not null;default:''"`
+	Action     AuditAction         `json:"action" gorm:"type:varchar(32);not null"`
+	Reviewer   string              `json:"reviewer" gorm:"type:varchar(255);not null"`
+	OldScore   decimal.NullDecimal `json:"old_score" gorm:"type:decimal(10,2)"`
+	NewScore   decimal.NullDecimal `json:"new_score" gorm:"type:decimal(10,2)"`
+	OldReason  string              `json:"old_reason,omitempty" gorm:"type:text"`
+	NewReason  string              `json:"new_reason,omitempty" gorm:"type:text"`
+	Comment    string              `json:"comment,omitempty" gorm:"type:text"`
+	CreatedAt  time.Time           `json:"created_at" gorm:"not null;index"`
+}
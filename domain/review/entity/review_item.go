@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// ReviewReason 加入審核佇列的原因.
+type ReviewReason string
+
+const (
+	ReviewReasonDisputed           ReviewReason = "disputed"            // 編輯提出異議
+	ReviewReasonInjectionSuspected ReviewReason = "injection_suspected" // 內容疑似 prompt injection
+	ReviewReasonRuleDisagreement   ReviewReason = "rule_disagreement"   // LLM 與規則式標題評分差距過大
+	ReviewReasonMissingMetrics     ReviewReason = "missing_metrics"     // AI 回應缺少指標
+	ReviewReasonModelDisagreement  ReviewReason = "model_disagreement"  // 多模型評分差異過大
+)
+
+// ReviewStatus 審核狀態.
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusResolved ReviewStatus = "resolved"
+)
+
+// ReviewItem 審核佇列, 同一分析同一原因只有一筆, 再次加入時重新開啟.
+type ReviewItem struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	AnalysisID  uint         `json:"analysis_id" gorm:"not null;uniqueIndex:idx_review_analysis_reason"`
+	Reason      ReviewReason `json:"reason" gorm:"type:varchar(64);not null;uniqueIndex:idx_review_analysis_reason"`
+	Status      ReviewStatus `json:"status" gorm:"type:varchar(32);not null;index"`
+	Note        string       `json:"note,omitempty" gorm:"type:text"`
+	RequestedBy string       `json:"requested_by,omitempty" gorm:"type:varchar(255);not null;default:''"` // 空白代表系統
+	ResolvedBy  string       `json:"resolved_by,omitempty" gorm:"type:varchar(255);not null;default:''"`
+	ResolvedAt  *time.Time   `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at" gorm:"index"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
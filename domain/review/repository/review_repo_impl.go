@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	newsEntity "itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/review/entity"
+)
+
+var _ ReviewRepository = &ReviewRepositoryImpl{}
+
+type ReviewRepositoryImpl struct {
+	logger *zerolog.Logger
+	db     *gorm.DB
+}
+
+func NewReviewRepositoryImpl(logger *zerolog.Logger, db *gorm.DB) *ReviewRepositoryImpl {
+	return &ReviewRepositoryImpl{logger: logger, db: db}
+}
+
+func (r *ReviewRepositoryImpl) GetAnalysis(ctx context.Context, analysisID uint) (*newsEntity.Analysis, error) {
+	var analysis newsEntity.Analysis
+
+	err := r.db.WithContext(ctx).Preload("AnalysisMetricsList").Preload("News").First(&analysis, analysisID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis: %w", err)
+	}
+
+	return &analysis, nil
+}
+
+func (r *ReviewRepositoryImpl) FindAnalysesByNews(
+	ctx context.Context,
+	newsID string,
+	mediaID uint,
+) ([]*newsEntity.Analysis, error) {
+	var analysisList []*newsEntity.Analysis
+
+	err := r.db.WithContext(ctx).
+		Preload("AnalysisMetricsList").
+		Where("news_id = ? AND media_id = ?", newsID, mediaID).
+		Order("id").
+		Find(&analysisList).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find analyses by news: %w", err)
+	}
+
+	return analysisList, nil
+}
+
+func (r *ReviewRepositoryImpl) GetOverride(
+	ctx context.Context,
+	analysisID uint,
+	metricKey string,
+) (*entity.MetricOverride, error) {
+	var override entity.MetricOverride
+
+	err := r.db.WithContext(ctx).
+		Where("analysis_id = ? AND metric_key = ?", analysisID, metricKey).
+		First(&override).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metric override: %w", err)
+	}
+
+	return &override, nil
+}
+
+func (r *ReviewRepositoryImpl) FindOverrides(ctx context.Context, analysisIDs ...uint) ([]*entity.MetricOverride, error) {
+	var overrides []*entity.MetricOverride
+	if len(analysisIDs) == 0 {
+		return overrides, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("analysis_id IN ?", analysisIDs).
+		Order("analysis_id, metric_key").
+		Find(&overrides).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find metric overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+func (r *ReviewRepositoryImpl) FindOverridesReviewedBetween(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) ([]*entity.MetricOverride, error) {
+	var overrides []*entity.MetricOverride
+
+	err := r.db.WithContext(ctx).
+		Preload("Analysis.News").
+		Where("reviewed_at >= ? AND reviewed_at < ?", from, to).
+		Order("analysis_id, metric_key").
+		Find(&overrides).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find metric overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+func (r *ReviewRepositoryImpl) SaveOverride(
+	ctx context.Context,
+	override *entity.MetricOverride,
+	audit *entity.ReviewAudit,
+) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "analysis_id"}, {Name: "metric_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"score", "reason", "reviewer", "reviewed_at", "updated_at"}),
+		}).Create(override).Error
+		if err != nil {
+			return fmt.Errorf("failed to save metric override: %w", err)
+		}
+
+		if err = tx.Create(audit).Error; err != nil {
+			return fmt.Errorf("failed to create review audit: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Ctx(ctx).Uint("analysis_id", override.AnalysisID).Msg("failed to save override")
+		return err
+	}
+
+	return nil
+}
+
+func (r *ReviewRepositoryImpl) DeleteOverride(
+	ctx context.Context,
+	analysisID uint,
+	metricKey string,
+	audit *entity.ReviewAudit,
+) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("analysis_id = ? AND metric_key = ?", analysisID, metricKey).
+			Delete(&entity.MetricOverride{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete metric override: %w", err)
+		}
+
+		if err = tx.Create(audit).Error; err != nil {
+			return fmt.Errorf("failed to create review audit: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Ctx(ctx).Uint("analysis_id", analysisID).Msg("failed to delete override")
+		return err
+	}
+
+	return nil
+}
+
+func (r *ReviewRepositoryImpl) FindAudits(ctx context.Context, analysisID uint) ([]*entity.ReviewAudit, error) {
+	var audits []*entity.ReviewAudit
+
+	err := r.db.WithContext(ctx).
+		Where("analysis_id = ?", analysisID).
+		Order("created_at, id").
+		Find(&audits).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review audits: %w", err)
+	}
+
+	return audits, nil
+}
+
+func (r *ReviewRepositoryImpl) EnqueueReview(
+	ctx context.Context,
+	item *entity.ReviewItem,
+	audit *entity.ReviewAudit,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "analysis_id"}, {Name: "reason"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"status":       entity.ReviewStatusPending,
+				"note":         item.Note,
+				"requested_by": item.RequestedBy,
+				"resolved_by":  "",
+				"resolved_at":  nil,
+				"updated_at":   item.UpdatedAt,
+			}),
+		}).Create(item).Error
+		if err != nil {
+			return fmt.Errorf("failed to enqueue review: %w", err)
+		}
+
+		if audit == nil {
+			return nil
+		}
+		if err = tx.Create(audit).Error; err != nil {
+			return fmt.Errorf("failed to create review audit: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *ReviewRepositoryImpl) GetReviewItem(ctx context.Context, id uint) (*entity.ReviewItem, error) {
+	var item entity.ReviewItem
+
+	err := r.db.WithContext(ctx).First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review item: %w", err)
+	}
+
+	return &item, nil
+}
+
+func (r *ReviewRepositoryImpl) FindReviewItems(
+	ctx context.Context,
+	status entity.ReviewStatus,
+	limit int,
+) ([]*entity.ReviewItem, error) {
+	var items []*entity.ReviewItem
+
+	err := r.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("created_at, id").
+		Limit(limit).
+		Find(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review items: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *ReviewRepositoryImpl) ResolveReviewItem(
+	ctx context.Context,
+	item *entity.ReviewItem,
+	audit *entity.ReviewAudit,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(item).Updates(map[string]any{
+			"status":      entity.ReviewStatusResolved,
+			"resolved_by": item.ResolvedBy,
+			"resolved_at": item.ResolvedAt,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to resolve review item: %w", err)
+		}
+
+		if err = tx.Create(audit).Error; err != nil {
+			return fmt.Errorf("failed to create review audit: %w", err)
+		}
+		return nil
+	})
+}
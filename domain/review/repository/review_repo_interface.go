@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	newsEntity "itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/review/entity"
+)
+
+type ReviewRepository interface {
+	// GetAnalysis 取得分析與指標, 不存在時回傳 nil
+	GetAnalysis(ctx context.Context, analysisID uint) (*newsEntity.Analysis, error)
+	// FindAnalysesByNews 取得新聞的所有分析與指標
+	FindAnalysesByNews(ctx context.Context, newsID string, mediaID uint) ([]*newsEntity.Analysis, error)
+
+	// GetOverride 取得人工分數, 不存在時回傳 nil
+	GetOverride(ctx context.Context, analysisID uint, metricKey string) (*entity.MetricOverride, error)
+	// FindOverrides 取得分析的所有人工分數
+	FindOverrides(ctx context.Context, analysisIDs ...uint) ([]*entity.MetricOverride, error)
+	// FindOverridesReviewedBetween 取得 [from, to) 期間修改的人工分數, 含分析與新聞
+	FindOverridesReviewedBetween(ctx context.Context, from time.Time, to time.Time) ([]*entity.MetricOverride, error)
+	// SaveOverride 新增或修改人工分數並寫入審核紀錄
+	SaveOverride(ctx context.Context, override *entity.MetricOverride, audit *entity.ReviewAudit) error
+	// DeleteOverride 移除人工分數並寫入審核紀錄
+	DeleteOverride(ctx context.Context, analysisID uint, metricKey string, audit *entity.ReviewAudit) error
+
+	// FindAudits 取得分析的審核紀錄, 依時間排序
+	FindAudits(ctx context.Context, analysisID uint) ([]*entity.ReviewAudit, error)
+
+	// EnqueueReview 加入審核佇列, 已存在時重新開啟, audit 可為 nil
+	EnqueueReview(ctx context.Context, item *entity.ReviewItem, audit *entity.ReviewAudit) error
+	// GetReviewItem 取得審核項目, 不存在時回傳 nil
+	GetReviewItem(ctx context.Context, id uint) (*entity.ReviewItem, error)
+	// FindReviewItems 依狀態取得審核佇列, 依建立時間排序
+	FindReviewItems(ctx context.Context, status entity.ReviewStatus, limit int) ([]*entity.ReviewItem, error)
+	// ResolveReviewItem 完成審核並寫入審核紀錄
+	ResolveReviewItem(ctx context.Context, item *entity.ReviewItem, audit *entity.ReviewAudit) error
+}
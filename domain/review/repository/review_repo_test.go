@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-testfixtures/testfixtures/v3"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/review/entity"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
+)
+
+func TestReviewRepoSuite(t *testing.T) {
+	suite.Run(t, new(ReviewTestSuite))
+}
+
+type ReviewTestSuite struct {
+	suite.Suite
+	reviewRepo ReviewRepository
+	now        time.Time
+}
+
+func (s *ReviewTestSuite) SetupTest() {
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	logger := zerolog.New(os.Stdout).Level(zerolog.DebugLevel)
+
+	ormDB := testdb.New(context.Background(), &logger, tracer)
+
+	sqlDB, err := ormDB.DB()
+	s.Require().NoError(err)
+
+	// init test data
+	fixtures, err := testfixtures.New(
+		testfixtures.Database(sqlDB),
+		testfixtures.Dialect(ormDB.Dialector.Name()),
+		testfixtures.Directory("testdata"),
+		testfixtures.DangerousSkipTestDatabaseCheck(),
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(fixtures.Load())
+
+	s.reviewRepo = NewReviewRepositoryImpl(&logger, ormDB)
+	s.now = time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (s *ReviewTestSuite) TestGetAnalysis() {
+	analysis, err := s.reviewRepo.GetAnalysis(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Require().NotNil(analysis)
+	s.Len(analysis.AnalysisMetricsList, 2)
+	s.Equal("震驚！颱風竟然轉向", analysis.News.Title)
+
+	analysis, err = s.reviewRepo.GetAnalysis(context.Background(), 999)
+	s.NoError(err)
+	s.Nil(analysis)
+}
+
+func (s *ReviewTestSuite) TestSaveOverride() {
+	ctx := context.Background()
+
+	for i, score := range []float64{2, 1.5} {
+		override := &entity.MetricOverride{
+			AnalysisID: 1,
+			MetricKey:  "clarity",
+			Score:      decimal.NewFromFloat(score),
+			Reason:     "human clarity",
+			Reviewer:   "editor",
+			ReviewedAt: s.now.Add(time.Duration(i) * time.Hour),
+		}
+		audit := &entity.ReviewAudit{
+			AnalysisID: 1,
+			MetricKey:  "clarity",
+			Action:     entity.AuditActionOverride,
+			Reviewer:   "editor",
+			NewScore:   decimal.NewNullDecimal(decimal.NewFromFloat(score)),
+			CreatedAt:  s.now.Add(time.Duration(i) * time.Hour),
+		}
+		s.Require().NoError(s.reviewRepo.SaveOverride(ctx, override, audit))
+	}
+
+	// 同一指標只保留最新的人工分數
+	overrides, err := s.reviewRepo.FindOverrides(ctx, 1, 2)
+	s.Require().NoError(err)
+	s.Require().Len(overrides, 1)
+	s.Equal("1.5", overrides[0].Score.String())
+
+	// 每次修改都有審核紀錄
+	audits, err := s.reviewRepo.FindAudits(ctx, 1)
+	s.Require().NoError(err)
+	s.Require().Len(audits, 2)
+	s.Equal("2", audits[0].NewScore.Decimal.String())
+	s.False(audits[0].OldScore.Valid)
+
+	// 匯出查詢包含分析與新聞
+	overrides, err = s.reviewRepo.FindOverridesReviewedBetween(ctx, s.now, s.now.Add(24*time.Hour))
+	s.Require().NoError(err)
+	s.Require().Len(overrides, 1)
+	s.Equal("n1", overrides[0].Analysis.NewsID)
+	s.Equal("震驚！颱風竟然轉向", overrides[0].Analysis.News.Title)
+
+	// 移除人工分數
+	s.Require().NoError(s.reviewRepo.DeleteOverride(ctx, 1, "clarity", &entity.ReviewAudit{
+		AnalysisID: 1, MetricKey: "clarity", Action: entity.AuditActionRevert, Reviewer: "editor", CreatedAt: s.now,
+	}))
+	override, err := s.reviewRepo.GetOverride(ctx, 1, "clarity")
+	s.NoError(err)
+	s.Nil(override)
+}
+
+func (s *ReviewTestSuite) TestReviewQueue() {
+	ctx := context.Background()
+
+	item := &entity.ReviewItem{
+		AnalysisID:  1,
+		Reason:      entity.ReviewReasonDisputed,
+		Status:      entity.ReviewStatusPending,
+		RequestedBy: "editor",
+		CreatedAt:   s.now,
+		UpdatedAt:   s.now,
+	}
+	s.Require().NoError(s.reviewRepo.EnqueueReview(ctx, item, nil))
+
+	items, err := s.reviewRepo.FindReviewItems(ctx, entity.ReviewStatusPending, 10)
+	s.Require().NoError(err)
+	s.Require().Len(items, 1)
+
+	// 完成審核
+	resolvedAt := s.now.Add(time.Hour)
+	items[0].ResolvedBy = "chief"
+	items[0].ResolvedAt = &resolvedAt
+	s.Require().NoError(s.reviewRepo.ResolveReviewItem(ctx, items[0], &entity.ReviewAudit{
+		AnalysisID: 1, Action: entity.AuditActionResolve, Reviewer: "chief", CreatedAt: resolvedAt,
+	}))
+
+	items, err = s.reviewRepo.FindReviewItems(ctx, entity.ReviewStatusPending, 10)
+	s.Require().NoError(err)
+	s.Empty(items)
+
+	// 再次提出異議時重新開啟
+	s.Require().NoError(s.reviewRepo.EnqueueReview(ctx, &entity.ReviewItem{
+		AnalysisID:  1,
+		Reason:      entity.ReviewReasonDisputed,
+		Status:      entity.ReviewStatusPending,
+		Note:        "again",
+		RequestedBy: "editor2",
+		CreatedAt:   s.now.Add(2 * time.Hour),
+		UpdatedAt:   s.now.Add(2 * time.Hour),
+	}, nil))
+
+	items, err = s.reviewRepo.FindReviewItems(ctx, entity.ReviewStatusPending, 10)
+	s.Require().NoError(err)
+	s.Require().Len(items, 1)
+	s.Equal("again", items[0].Note)
+	s.Equal("editor2", items[0].RequestedBy)
+	s.Empty(items[0].ResolvedBy)
+	s.Nil(items[0].ResolvedAt)
+}
@@ -0,0 +1,533 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/ai"
+	"itmrchow/tw-media-analytics-service/domain/ai/eval"
+	newsEntity "itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/review/dto"
+	"itmrchow/tw-media-analytics-service/domain/review/entity"
+	"itmrchow/tw-media-analytics-service/domain/review/repository"
+)
+
+const (
+	defaultRuleGap     = 2.0
+	defaultMaxVariance = 1.0
+	maxScore           = 5.0
+)
+
+var (
+	// ErrNotFound 分析, 指標或審核項目不存在.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidRequest 請求內容錯誤.
+	ErrInvalidRequest = errors.New("invalid request")
+)
+
+var _ ReviewService = &ReviewServiceImpl{}
+
+type ReviewServiceImpl struct {
+	logger *zerolog.Logger
+	tracer trace.Tracer
+	repo   repository.ReviewRepository
+	now    func() time.Time
+
+	lowConfidence LowConfidenceConfig
+}
+
+// LowConfidenceConfig 自動加入審核佇列的門檻, 0 代表不檢查.
+type LowConfidenceConfig struct {
+	RuleGap     float64 // LLM 與規則式標題總分差距
+	MaxVariance float64 // 多模型評分任一指標的變異數
+}
+
+// NewLowConfidenceConfig 從 viper 讀取審核門檻, 未設定時使用預設值.
+func NewLowConfidenceConfig() LowConfidenceConfig {
+	cfg := LowConfidenceConfig{
+		RuleGap:     defaultRuleGap,
+		MaxVariance: defaultMaxVariance,
+	}
+	if viper.IsSet("REVIEW_RULE_GAP") {
+		cfg.RuleGap = viper.GetFloat64("REVIEW_RULE_GAP")
+	}
+	if viper.IsSet("REVIEW_MAX_VARIANCE") {
+		cfg.MaxVariance = viper.GetFloat64("REVIEW_MAX_VARIANCE")
+	}
+	return cfg
+}
+
+func NewReviewServiceImpl(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	repo repository.ReviewRepository,
+) *ReviewServiceImpl {
+	return &ReviewServiceImpl{
+		logger:        logger,
+		tracer:        tracer,
+		repo:          repo,
+		now:           time.Now,
+		lowConfidence: NewLowConfidenceConfig(),
+	}
+}
+
+// OverrideMetric 人工修正指標分數, 並記錄修改前後的值.
+func (s *ReviewServiceImpl) OverrideMetric(
+	ctx context.Context,
+	analysisID uint,
+	metricKey string,
+	req dto.OverrideRequest,
+) (*dto.EffectiveAnalysis, error) {
+	ctx, span := s.tracer.Start(ctx, "domain/review/service/review_service_impl/OverrideMetric: Override Metric")
+	defer span.End()
+
+	if req.Reviewer == "" {
+		return nil, fmt.Errorf("%w: reviewer is required", ErrInvalidRequest)
+	}
+
+	analysis, err := s.getAnalysis(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	metric, ok := findMetric(analysis, metricKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: metric %s of analysis %d", ErrNotFound, metricKey, analysisID)
+	}
+	if minScore, maxScore := scoreRange(analysis.Type, metricKey); req.Score < minScore || req.Score > maxScore {
+		return nil, fmt.Errorf("%w: %s score must be between %g and %g", ErrInvalidRequest, metricKey, minScore, maxScore)
+	}
+
+	// 修改前的值: 已有人工分數時為人工分數, 否則為 AI 分數
+	oldScore, oldReason := metric.Score, metric.Reason
+	existing, err := s.repo.GetOverride(ctx, analysisID, metricKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		oldScore, oldReason = existing.Score, existing.Reason
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = oldReason
+	}
+
+	now := s.now()
+	score := decimal.NewFromFloat(req.Score)
+	override := &entity.MetricOverride{
+		AnalysisID: analysisID,
+		MetricKey:  metricKey,
+		Score:      score,
+		Reason:     reason,
+		Reviewer:   req.Reviewer,
+		ReviewedAt: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	audit := &entity.ReviewAudit{
+		AnalysisID: analysisID,
+		MetricKey:  metricKey,
+		Action:     entity.AuditActionOverride,
+		Reviewer:   req.Reviewer,
+		OldScore:   decimal.NewNullDecimal(oldScore),
+		NewScore:   decimal.NewNullDecimal(score),
+		OldReason:  oldReason,
+		NewReason:  reason,
+		Comment:    req.Comment,
+		CreatedAt:  now,
+	}
+	if err = s.repo.SaveOverride(ctx, override, audit); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().Ctx(ctx).
+		Uint("analysis_id", analysisID).
+		Str("metric_key", metricKey).
+		Str("reviewer", req.Reviewer).
+		Str("old_score", oldScore.String()).
+		Str("new_score", score.String()).
+		Msg("metric overridden")
+
+	return s.EffectiveAnalysis(ctx, analysisID)
+}
+
+// RevertMetric 移除人工分數, 恢復 AI 分數.
+func (s *ReviewServiceImpl) RevertMetric(
+	ctx context.Context,
+	analysisID uint,
+	metricKey string,
+	req dto.ReviewerRequest,
+) (*dto.EffectiveAnalysis, error) {
+	ctx, span := s.tracer.Start(ctx, "domain/review/service/review_service_impl/RevertMetric: Revert Metric")
+	defer span.End()
+
+	if req.Reviewer == "" {
+		return nil, fmt.Errorf("%w: reviewer is required", ErrInvalidRequest)
+	}
+
+	analysis, err := s.getAnalysis(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	metric, ok := findMetric(analysis, metricKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: metric %s of analysis %d", ErrNotFound, metricKey, analysisID)
+	}
+
+	existing, err := s.repo.GetOverride(ctx, analysisID, metricKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("%w: override of metric %s", ErrNotFound, metricKey)
+	}
+
+	audit := &entity.ReviewAudit{
+		AnalysisID: analysisID,
+		MetricKey:  metricKey,
+		Action:     entity.AuditActionRevert,
+		Reviewer:   req.Reviewer,
+		OldScore:   decimal.NewNullDecimal(existing.Score),
+		NewScore:   decimal.NewNullDecimal(metric.Score),
+		OldReason:  existing.Reason,
+		NewReason:  metric.Reason,
+		Comment:    req.Note,
+		CreatedAt:  s.now(),
+	}
+	if err = s.repo.DeleteOverride(ctx, analysisID, metricKey, audit); err != nil {
+		return nil, err
+	}
+
+	return s.EffectiveAnalysis(ctx, analysisID)
+}
+
+// EffectiveAnalysis 取得有效分數, 人工分數優先.
+func (s *ReviewServiceImpl) EffectiveAnalysis(ctx context.Context, analysisID uint) (*dto.EffectiveAnalysis, error) {
+	analysis, err := s.getAnalysis(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.repo.FindOverrides(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	return Effective(analysis, overrides), nil
+}
+
+// Audits 取得審核紀錄.
+func (s *ReviewServiceImpl) Audits(ctx context.Context, analysisID uint) ([]*entity.ReviewAudit, error) {
+	return s.repo.FindAudits(ctx, analysisID)
+}
+
+// Dispute 對分析提出異議並加入審核佇列.
+func (s *ReviewServiceImpl) Dispute(
+	ctx context.Context,
+	analysisID uint,
+	req dto.ReviewerRequest,
+) (*entity.ReviewItem, error) {
+	ctx, span := s.tracer.Start(ctx, "domain/review/service/review_service_impl/Dispute: Dispute Analysis")
+	defer span.End()
+
+	if req.Reviewer == "" {
+		return nil, fmt.Errorf("%w: reviewer is required", ErrInvalidRequest)
+	}
+	if _, err := s.getAnalysis(ctx, analysisID); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	item := &entity.ReviewItem{
+		AnalysisID:  analysisID,
+		Reason:      entity.ReviewReasonDisputed,
+		Status:      entity.ReviewStatusPending,
+		Note:        req.Note,
+		RequestedBy: req.Reviewer,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	audit := &entity.ReviewAudit{
+		AnalysisID: analysisID,
+		Action:     entity.AuditActionDispute,
+		Reviewer:   req.Reviewer,
+		Comment:    req.Note,
+		CreatedAt:  now,
+	}
+	if err := s.repo.EnqueueReview(ctx, item, audit); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// Resolve 完成審核.
+func (s *ReviewServiceImpl) Resolve(ctx context.Context, itemID uint, req dto.ReviewerRequest) (*entity.ReviewItem, error) {
+	ctx, span := s.tracer.Start(ctx, "domain/review/service/review_service_impl/Resolve: Resolve Review Item")
+	defer span.End()
+
+	if req.Reviewer == "" {
+		return nil, fmt.Errorf("%w: reviewer is required", ErrInvalidRequest)
+	}
+
+	item, err := s.repo.GetReviewItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("%w: review item %d", ErrNotFound, itemID)
+	}
+
+	now := s.now()
+	item.Status = entity.ReviewStatusResolved
+	item.ResolvedBy = req.Reviewer
+	item.ResolvedAt = &now
+	audit := &entity.ReviewAudit{
+		AnalysisID: item.AnalysisID,
+		Action:     entity.AuditActionResolve,
+		Reviewer:   req.Reviewer,
+		Comment:    req.Note,
+		CreatedAt:  now,
+	}
+	if err = s.repo.ResolveReviewItem(ctx, item, audit); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// Queue 取得審核佇列.
+func (s *ReviewServiceImpl) Queue(ctx context.Context, status entity.ReviewStatus, limit int) ([]*entity.ReviewItem, error) {
+	return s.repo.FindReviewItems(ctx, status, limit)
+}
+
+// EnqueueLowConfidence 將低信心的分析加入審核佇列, analysisList 為同一篇新聞剛儲存的分析.
+func (s *ReviewServiceImpl) EnqueueLowConfidence(ctx context.Context, analysisList []newsEntity.Analysis) error {
+	var errs error
+	for _, candidate := range LowConfidence(analysisList, s.lowConfidence) {
+		now := s.now()
+		err := s.repo.EnqueueReview(ctx, &entity.ReviewItem{
+			AnalysisID: candidate.AnalysisID,
+			Reason:     candidate.Reason,
+			Status:     entity.ReviewStatusPending,
+			Note:       candidate.Note,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}, nil)
+		errs = errors.Join(errs, err)
+	}
+
+	return errs
+}
+
+// Export 將 [from, to) 期間的人工分數匯出為 eval 評估資料格式, 每篇新聞一行, 回傳筆數.
+func (s *ReviewServiceImpl) Export(ctx context.Context, from time.Time, to time.Time, w io.Writer) (int, error) {
+	overrides, err := s.repo.FindOverridesReviewedBetween(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	var samples []*eval.Sample
+	sampleMap := map[string]*eval.Sample{}
+	for _, override := range overrides {
+		analysis := override.Analysis
+		if analysis.Type != newsEntity.AnalysisTypeTitle && analysis.Type != newsEntity.AnalysisTypeContent {
+			continue
+		}
+
+		id := strconv.Itoa(int(analysis.MediaID)) + "-" + analysis.NewsID
+		sample, ok := sampleMap[id]
+		if !ok {
+			sample = &eval.Sample{
+				ID:            id,
+				Title:         analysis.News.Title,
+				Content:       analysis.News.Content,
+				TitleScores:   map[string]float64{},
+				ContentScores: map[string]float64{},
+			}
+			sampleMap[id] = sample
+			samples = append(samples, sample)
+		}
+
+		scores := sample.ContentScores
+		if analysis.Type == newsEntity.AnalysisTypeTitle {
+			scores = sample.TitleScores
+		}
+		scores[override.MetricKey] = override.Score.InexactFloat64()
+	}
+
+	encoder := json.NewEncoder(w)
+	for _, sample := range samples {
+		if err = encoder.Encode(sample); err != nil {
+			return 0, fmt.Errorf("failed to write sample: %w", err)
+		}
+	}
+
+	return len(samples), nil
+}
+
+func (s *ReviewServiceImpl) getAnalysis(ctx context.Context, analysisID uint) (*newsEntity.Analysis, error) {
+	analysis, err := s.repo.GetAnalysis(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	if analysis == nil {
+		return nil, fmt.Errorf("%w: analysis %d", ErrNotFound, analysisID)
+	}
+	return analysis, nil
+}
+
+func findMetric(analysis *newsEntity.Analysis, metricKey string) (newsEntity.AnalysisMetric, bool) {
+	for _, metric := range analysis.AnalysisMetricsList {
+		if metric.MetricKey == metricKey {
+			return metric, true
+		}
+	}
+	return newsEntity.AnalysisMetric{}, false
+}
+
+// Effective 合併 AI 分數與人工分數; 有人工分數時總分以有效指標平均 (四捨五入至小數點下一位) 重新計算, 框架分析除外.
+func Effective(analysis *newsEntity.Analysis, overrides []*entity.MetricOverride) *dto.EffectiveAnalysis {
+	overrideMap := map[string]*entity.MetricOverride{}
+	for _, override := range overrides {
+		if override.AnalysisID == analysis.ID {
+			overrideMap[override.MetricKey] = override
+		}
+	}
+
+	result := &dto.EffectiveAnalysis{
+		AnalysisID:         analysis.ID,
+		NewsID:             analysis.NewsID,
+		MediaID:            analysis.MediaID,
+		Type:               string(analysis.Type),
+		Score:              analysis.Score.InexactFloat64(),
+		AiScore:            analysis.Score.InexactFloat64(),
+		Reason:             analysis.Reason,
+		InjectionSuspected: analysis.InjectionSuspected,
+		Metrics:            make([]dto.EffectiveMetric, 0, len(analysis.AnalysisMetricsList)),
+	}
+
+	var total float64
+	for _, metric := range analysis.AnalysisMetricsList {
+		effective := dto.EffectiveMetric{
+			MetricKey: metric.MetricKey,
+			Score:     metric.Score.InexactFloat64(),
+			Reason:    metric.Reason,
+			AiScore:   metric.Score.InexactFloat64(),
+			AiReason:  metric.Reason,
+		}
+		if override, ok := overrideMap[metric.MetricKey]; ok {
+			reviewedAt := override.ReviewedAt
+			effective.Score = override.Score.InexactFloat64()
+			effective.Reason = override.Reason
+			effective.Overridden = true
+			effective.Reviewer = override.Reviewer
+			effective.ReviewedAt = &reviewedAt
+			result.Overridden = true
+		}
+		total += effective.Score
+		result.Metrics = append(result.Metrics, effective)
+	}
+
+	// 框架分析的指標範圍不同 (立場與語氣可為負數), 平均沒有意義, 總分維持 AI 分數
+	if result.Overridden && analysis.Type != newsEntity.AnalysisTypeFraming {
+		result.Score = math.Round(total/float64(len(result.Metrics))*10) / 10
+	}
+
+	return result
+}
+
+// scoreRange 指標可覆寫的分數範圍, 框架分析依各指標定義, 其他分析為 0 ~ 5.
+func scoreRange(analysisType newsEntity.AnalysisType, metricKey string) (float64, float64) {
+	if analysisType == newsEntity.AnalysisTypeFraming {
+		for _, m := range ai.FramingMetrics {
+			if m.Key == metricKey {
+				return m.Min, m.Max
+			}
+		}
+	}
+	return 0, maxScore
+}
+
+// LowConfidenceCandidate 需要人工審核的分析.
+type LowConfidenceCandidate struct {
+	AnalysisID uint
+	Reason     entity.ReviewReason
+	Note       string
+}
+
+// LowConfidence 找出同一篇新聞中需要人工審核的分析:
+// 內容疑似 prompt injection, AI 回應缺少指標, 多模型評分分歧, 或 LLM 與規則式標題總分差距過大.
+func LowConfidence(analysisList []newsEntity.Analysis, cfg LowConfidenceConfig) []LowConfidenceCandidate {
+	var candidates []LowConfidenceCandidate
+	var title, titleRule *newsEntity.Analysis
+
+	for i := range analysisList {
+		analysis := &analysisList[i]
+		switch analysis.Type {
+		case newsEntity.AnalysisTypeTitle:
+			title = analysis
+		case newsEntity.AnalysisTypeTitleRule:
+			titleRule = analysis
+			continue
+		}
+
+		if analysis.InjectionSuspected {
+			candidates = append(candidates, LowConfidenceCandidate{
+				AnalysisID: analysis.ID,
+				Reason:     entity.ReviewReasonInjectionSuspected,
+			})
+		}
+		if len(analysis.AnalysisMetricsList) == 0 {
+			candidates = append(candidates, LowConfidenceCandidate{
+				AnalysisID: analysis.ID,
+				Reason:     entity.ReviewReasonMissingMetrics,
+			})
+		}
+
+		if cfg.MaxVariance > 0 {
+			var disagreements []string
+			for _, metric := range analysis.AnalysisMetricsList {
+				if metric.Variance.InexactFloat64() >= cfg.MaxVariance {
+					disagreements = append(disagreements, metric.MetricKey+" variance: "+metric.Variance.String())
+				}
+			}
+			if len(disagreements) > 0 {
+				candidates = append(candidates, LowConfidenceCandidate{
+					AnalysisID: analysis.ID,
+					Reason:     entity.ReviewReasonModelDisagreement,
+					Note:       strings.Join(disagreements, ", "),
+				})
+			}
+		}
+	}
+
+	if cfg.RuleGap > 0 && title != nil && titleRule != nil {
+		gap := title.Score.Sub(titleRule.Score).Abs().InexactFloat64()
+		if gap >= cfg.RuleGap {
+			candidates = append(candidates, LowConfidenceCandidate{
+				AnalysisID: title.ID,
+				Reason:     entity.ReviewReasonRuleDisagreement,
+				Note: strings.Join([]string{
+					"llm: " + title.Score.String(),
+					"rule: " + titleRule.Score.String(),
+					"rule evidence: " + titleRule.Reason,
+				}, ", "),
+			})
+		}
+	}
+
+	return candidates
+}
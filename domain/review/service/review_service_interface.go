@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"io"
+	"time"
+
+	newsEntity "itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/review/dto"
+	"itmrchow/tw-media-analytics-service/domain/review/entity"
+)
+
+type ReviewService interface {
+	// 人工修正指標分數
+	OverrideMetric(ctx context.Context, analysisID uint, metricKey string, req dto.OverrideRequest) (*dto.EffectiveAnalysis, error)
+	// 移除人工分數, 恢復 AI 分數
+	RevertMetric(ctx context.Context, analysisID uint, metricKey string, req dto.ReviewerRequest) (*dto.EffectiveAnalysis, error)
+	// 取得有效分數
+	EffectiveAnalysis(ctx context.Context, analysisID uint) (*dto.EffectiveAnalysis, error)
+	// 取得審核紀錄
+	Audits(ctx context.Context, analysisID uint) ([]*entity.ReviewAudit, error)
+
+	// 提出異議並加入審核佇列
+	Dispute(ctx context.Context, analysisID uint, req dto.ReviewerRequest) (*entity.ReviewItem, error)
+	// 完成審核
+	Resolve(ctx context.Context, itemID uint, req dto.ReviewerRequest) (*entity.ReviewItem, error)
+	// 取得審核佇列
+	Queue(ctx context.Context, status entity.ReviewStatus, limit int) ([]*entity.ReviewItem, error)
+	// 將低信心的分析加入審核佇列
+	EnqueueLowConfidence(ctx context.Context, analysisList []newsEntity.Analysis) error
+
+	// 匯出 [from, to) 期間的人工分數為評估資料 (JSONL)
+	Export(ctx context.Context, from time.Time, to time.Time, w io.Writer) (int, error)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-testfixtures/testfixtures/v3"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/ai/eval"
+	newsEntity "itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/review/dto"
+	"itmrchow/tw-media-analytics-service/domain/review/entity"
+	"itmrchow/tw-media-analytics-service/domain/review/repository"
+	"itmrchow/tw-media-analytics-service/internal/testdb"
+)
+
+func newTestReviewService(t *testing.T) *ReviewServiceImpl {
+	t.Helper()
+
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	logger := zerolog.New(os.Stdout).Level(zerolog.InfoLevel)
+
+	ormDB := testdb.New(context.Background(), &logger, tracer)
+	sqlDB, err := ormDB.DB()
+	require.NoError(t, err)
+
+	// 與 repository 共用測試資料
+	fixtures, err := testfixtures.New(
+		testfixtures.Database(sqlDB),
+		testfixtures.Dialect(ormDB.Dialector.Name()),
+		testfixtures.Directory("../repository/testdata"),
+		testfixtures.DangerousSkipTestDatabaseCheck(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, fixtures.Load())
+
+	s := NewReviewServiceImpl(&logger, tracer, repository.NewReviewRepositoryImpl(&logger, ormDB))
+	s.now = func() time.Time { return time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	return s
+}
+
+func TestReviewService_OverrideMetric(t *testing.T) {
+	s := newTestReviewService(t)
+	ctx := context.Background()
+
+	_, err := s.OverrideMetric(ctx, 1, "clarity", dto.OverrideRequest{Score: 1})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = s.OverrideMetric(ctx, 1, "clarity", dto.OverrideRequest{Score: 6, Reviewer: "editor"})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = s.OverrideMetric(ctx, 1, "unknown", dto.OverrideRequest{Score: 1, Reviewer: "editor"})
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = s.OverrideMetric(ctx, 999, "clarity", dto.OverrideRequest{Score: 1, Reviewer: "editor"})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	analysis, err := s.OverrideMetric(ctx, 1, "clarity", dto.OverrideRequest{
+		Score: 1, Reason: "標題聳動", Reviewer: "editor", Comment: "含震驚",
+	})
+	require.NoError(t, err)
+
+	// 有效分數優先使用人工分數, 總分重新計算 (4 + 1) / 2
+	assert.True(t, analysis.Overridden)
+	assert.Equal(t, 2.5, analysis.Score)
+	assert.Equal(t, 4.0, analysis.AiScore)
+	require.Len(t, analysis.Metrics, 2)
+	assert.Equal(t, 1.0, analysis.Metrics[1].Score)
+	assert.Equal(t, 4.0, analysis.Metrics[1].AiScore)
+	assert.Equal(t, "標題聳動", analysis.Metrics[1].Reason)
+	assert.Equal(t, "editor", analysis.Metrics[1].Reviewer)
+
+	// 匯出為評估資料
+	var buf bytes.Buffer
+	n, err := s.Export(ctx, s.now().Add(-time.Hour), s.now().Add(time.Hour), &buf)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	var sample eval.Sample
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &sample))
+	assert.Equal(t, "1-n1", sample.ID)
+	assert.Equal(t, "震驚！颱風竟然轉向", sample.Title)
+	assert.Equal(t, map[string]float64{"clarity": 1}, sample.TitleScores)
+	assert.Empty(t, sample.ContentScores)
+
+	// 恢復 AI 分數
+	analysis, err = s.RevertMetric(ctx, 1, "clarity", dto.ReviewerRequest{Reviewer: "chief", Note: "維持 AI 評分"})
+	require.NoError(t, err)
+	assert.False(t, analysis.Overridden)
+	assert.Equal(t, 4.0, analysis.Score)
+	_, err = s.RevertMetric(ctx, 1, "clarity", dto.ReviewerRequest{Reviewer: "chief"})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// 審核紀錄
+	audits, err := s.Audits(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, audits, 2)
+	assert.Equal(t, entity.AuditActionOverride, audits[0].Action)
+	assert.Equal(t, "4", audits[0].OldScore.Decimal.String())
+	assert.Equal(t, "AI clarity", audits[0].OldReason)
+	assert.Equal(t, entity.AuditActionRevert, audits[1].Action)
+	assert.Equal(t, "1", audits[1].OldScore.Decimal.String())
+	assert.Equal(t, "4", audits[1].NewScore.Decimal.String())
+}
+
+func TestReviewService_OverrideFramingMetric(t *testing.T) {
+	s := newTestReviewService(t)
+	ctx := context.Background()
+
+	// 立場為 -2 ~ 2, loaded_terms 為 0 ~ 5
+	_, err := s.OverrideMetric(ctx, 3, "stance_dpp", dto.OverrideRequest{Score: 3, Reviewer: "editor"})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = s.OverrideMetric(ctx, 3, "loaded_terms", dto.OverrideRequest{Score: -1, Reviewer: "editor"})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+
+	analysis, err := s.OverrideMetric(ctx, 3, "stance_dpp", dto.OverrideRequest{Score: -1.5, Reviewer: "editor"})
+	require.NoError(t, err)
+	analysis, err = s.OverrideMetric(ctx, 3, "loaded_terms", dto.OverrideRequest{Score: 5, Reviewer: "editor"})
+	require.NoError(t, err)
+
+	// 總分維持 AI 的框架強度, 不以指標平均重新計算
+	assert.True(t, analysis.Overridden)
+	assert.Equal(t, 2.0, analysis.Score)
+	assert.Equal(t, 2.0, analysis.AiScore)
+	for _, metric := range analysis.Metrics {
+		switch metric.MetricKey {
+		case "stance_dpp":
+			assert.Equal(t, -1.5, metric.Score)
+		case "loaded_terms":
+			assert.Equal(t, 5.0, metric.Score)
+		}
+	}
+}
+
+func TestReviewService_DisputeAndResolve(t *testing.T) {
+	s := newTestReviewService(t)
+	ctx := context.Background()
+
+	item, err := s.Dispute(ctx, 2, dto.ReviewerRequest{Reviewer: "editor", Note: "內容有誤"})
+	require.NoError(t, err)
+
+	queue, err := s.Queue(ctx, entity.ReviewStatusPending, 10)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, entity.ReviewReasonDisputed, queue[0].Reason)
+
+	resolved, err := s.Resolve(ctx, item.ID, dto.ReviewerRequest{Reviewer: "chief"})
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReviewStatusResolved, resolved.Status)
+
+	queue, err = s.Queue(ctx, entity.ReviewStatusPending, 10)
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+
+	_, err = s.Resolve(ctx, 999, dto.ReviewerRequest{Reviewer: "chief"})
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLowConfidence(t *testing.T) {
+	metrics := []newsEntity.AnalysisMetric{{MetricKey: "accuracy"}}
+	newAnalysis := func(id uint, analysisType newsEntity.AnalysisType, score float64) newsEntity.Analysis {
+		a := newsEntity.Analysis{Type: analysisType, Score: decimal.NewFromFloat(score), AnalysisMetricsList: metrics}
+		a.ID = id
+		return a
+	}
+
+	tests := []struct {
+		name         string
+		analysisList []newsEntity.Analysis
+		want         map[uint]entity.ReviewReason
+	}{
+		{
+			name: "分數一致",
+			analysisList: []newsEntity.Analysis{
+				newAnalysis(1, newsEntity.AnalysisTypeTitle, 4),
+				newAnalysis(2, newsEntity.AnalysisTypeTitleRule, 3),
+				newAnalysis(3, newsEntity.AnalysisTypeContent, 4),
+			},
+			want: map[uint]entity.ReviewReason{},
+		},
+		{
+			name: "LLM 與規則差距過大",
+			analysisList: []newsEntity.Analysis{
+				newAnalysis(1, newsEntity.AnalysisTypeTitle, 4.5),
+				newAnalysis(2, newsEntity.AnalysisTypeTitleRule, 2.5),
+				newAnalysis(3, newsEntity.AnalysisTypeContent, 4),
+			},
+			want: map[uint]entity.ReviewReason{1: entity.ReviewReasonRuleDisagreement},
+		},
+		{
+			name: "多模型評分分歧",
+			analysisList: func() []newsEntity.Analysis {
+				content := newAnalysis(3, newsEntity.AnalysisTypeContent, 3)
+				content.AnalysisMetricsList = []newsEntity.AnalysisMetric{
+					{MetricKey: "accuracy", Variance: decimal.NewFromFloat(0.5)},
+					{MetricKey: "objectivity", Variance: decimal.NewFromFloat(1.25)},
+				}
+				return []newsEntity.Analysis{content}
+			}(),
+			want: map[uint]entity.ReviewReason{3: entity.ReviewReasonModelDisagreement},
+		},
+		{
+			name: "疑似注入與缺少指標",
+			analysisList: func() []newsEntity.Analysis {
+				content := newAnalysis(3, newsEntity.AnalysisTypeContent, 5)
+				content.InjectionSuspected = true
+				title := newAnalysis(1, newsEntity.AnalysisTypeTitle, 4)
+				title.AnalysisMetricsList = nil
+				return []newsEntity.Analysis{title, content}
+			}(),
+			want: map[uint]entity.ReviewReason{
+				1: entity.ReviewReasonMissingMetrics,
+				3: entity.ReviewReasonInjectionSuspected,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[uint]entity.ReviewReason{}
+			for _, c := range LowConfidence(tt.analysisList, LowConfidenceConfig{RuleGap: 2, MaxVariance: 1}) {
+				got[c.AnalysisID] = c.Reason
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -147,6 +147,7 @@ func (h *SpiderEventHandler) ArticleListScrapingHandle(ctx context.Context, msg
 	checkNewsEvent := utils.EventNewsCheck{
 		MediaID:    h.spider.GetMediaID(),
 		NewsIDList: newsIDList,
+		RunID:      watermill.NewUUID(),
 	}
 
 	jsonData, err := json.Marshal(checkNewsEvent)
@@ -188,6 +189,8 @@ func (h *SpiderEventHandler) ArticleContentScrapingHandle(ctx context.Context, m
 		return err
 	}
 
+	// 同一次文章列表的文章計為一個批次計算抽取失敗率
+	ctx = spider.WithCrawlRun(ctx, spider.CrawlRun{ID: event.RunID, Size: event.RunSize})
 	news, err := h.spider.GetNews(ctx, event.NewsID)
 	if err != nil {
 		h.logger.Error().Err(err).Ctx(ctx).Msg("failed to get news")
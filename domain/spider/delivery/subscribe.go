@@ -4,12 +4,12 @@ import (
 	"context"
 
 	"github.com/ThreeDotsLabs/watermill-googlecloud/pkg/googlecloud"
-	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 
 	"itmrchow/tw-media-analytics-service/domain/queue"
+	"itmrchow/tw-media-analytics-service/domain/utils/mq"
 )
 
 // InitSpiderSubscribe 初始化爬蟲相關訂閱.
@@ -46,7 +46,7 @@ func InitSpiderSubscribe(
 				return err
 			}
 
-			go process(logger, articleListScrapingMsg, spiderHandler.ArticleListScrapingHandle)
+			go mq.Process(logger, string(queue.TopicArticleListScraping), articleListScrapingMsg, spiderHandler.ArticleListScrapingHandle)
 
 			return nil
 		})
@@ -63,23 +63,8 @@ func InitSpiderSubscribe(
 			return err
 		}
 
-		go process(logger, articleContentScrapingMsg, handler.ArticleContentScrapingHandle)
+		go mq.Process(logger, string(queue.TopicArticleContentScraping), articleContentScrapingMsg, handler.ArticleContentScrapingHandle)
 
 		return nil
 	})
 }
-
-// TODO: 移到 utils
-func process(
-	logger *zerolog.Logger,
-	messages <-chan *message.Message,
-	handler func(ctx context.Context, msg []byte) error,
-) {
-	for msg := range messages {
-		if err := handler(msg.Context(), msg.Payload); err != nil {
-			logger.Error().Ctx(msg.Context()).Err(err).Bytes("payload", msg.Payload).Msg("failed to process message")
-			return
-		}
-		msg.Ack()
-	}
-}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// RawResponse 爬取新聞時的原始回應, 用於封存與重新解析.
+type RawResponse struct {
+	MediaID     uint      `json:"mediaId"`
+	NewsID      string    `json:"newsId"`
+	URL         string    `json:"url"`
+	StatusCode  int       `json:"statusCode"`
+	ContentType string    `json:"contentType"`
+	ContentHash string    `json:"contentHash"` // sha256(Body)
+	FetchedAt   time.Time `json:"fetchedAt"`
+
+	Body []byte `json:"-"`
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+	"itmrchow/tw-media-analytics-service/domain/utils/blob"
+)
+
+var _ ArchiveRepository = &ArchiveRepositoryImpl{}
+
+const (
+	objectPrefix   = "objects/sha256"
+	responsePrefix = "responses"
+	fetchedAtFmt   = "20060102T150405.000000000Z"
+)
+
+// ArchiveRepositoryImpl 以物件儲存封存原始回應.
+//
+// 物件配置:
+//
+//	objects/sha256/<hash[:2]>/<hash>.gz                  壓縮後的回應內容 (content-addressed)
+//	responses/<mediaID>/<newsID>/<fetchedAt>.json         回應 metadata, 指向內容 hash
+type ArchiveRepositoryImpl struct {
+	logger *zerolog.Logger
+	store  blob.Store
+}
+
+func NewArchiveRepositoryImpl(logger *zerolog.Logger, store blob.Store) *ArchiveRepositoryImpl {
+	return &ArchiveRepositoryImpl{logger: logger, store: store}
+}
+
+func (r *ArchiveRepositoryImpl) SaveResponse(ctx context.Context, resp *entity.RawResponse) error {
+	sum := sha256.Sum256(resp.Body)
+	resp.ContentHash = hex.EncodeToString(sum[:])
+
+	// 內容相同則不重複寫入
+	objectKey := r.objectKey(resp.ContentHash)
+	exists, err := r.store.Exists(ctx, objectKey)
+	if err != nil {
+		return fmt.Errorf("failed to check archived object: %w", err)
+	}
+	if !exists {
+		compressed, gzErr := gzipBytes(resp.Body)
+		if gzErr != nil {
+			return gzErr
+		}
+		if err = r.store.Put(ctx, objectKey, compressed); err != nil {
+			return fmt.Errorf("failed to archive object: %w", err)
+		}
+	}
+
+	meta, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response meta: %w", err)
+	}
+
+	metaKey := fmt.Sprintf("%s%s.json", r.newsPrefix(resp.MediaID, resp.NewsID), resp.FetchedAt.UTC().Format(fetchedAtFmt))
+	if err = r.store.Put(ctx, metaKey, meta); err != nil {
+		return fmt.Errorf("failed to archive response meta: %w", err)
+	}
+
+	r.logger.Debug().Ctx(ctx).
+		Uint("media_id", resp.MediaID).
+		Str("news_id", resp.NewsID).
+		Str("content_hash", resp.ContentHash).
+		Bool("deduplicated", exists).
+		Msg("response archived")
+
+	return nil
+}
+
+func (r *ArchiveRepositoryImpl) FindLatestResponse(
+	ctx context.Context,
+	mediaID uint,
+	newsID string,
+) (*entity.RawResponse, error) {
+	keys, err := r.store.List(ctx, r.newsPrefix(mediaID, newsID))
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: media %d news %s", blob.ErrNotFound, mediaID, newsID)
+	}
+
+	// fetchedAt 格式可依字典序排序, 最後一筆即為最新
+	meta, err := r.store.Get(ctx, keys[len(keys)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	var resp entity.RawResponse
+	if err = json.Unmarshal(meta, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response meta: %w", err)
+	}
+
+	compressed, err := r.store.Get(ctx, r.objectKey(resp.ContentHash))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Body, err = gunzipBytes(compressed); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (r *ArchiveRepositoryImpl) FindNewsIDs(ctx context.Context, mediaID uint) ([]string, error) {
+	prefix := fmt.Sprintf("%s/%d/", responsePrefix, mediaID)
+	keys, err := r.store.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	newsIDs := make([]string, 0, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		newsID, _, ok := strings.Cut(strings.TrimPrefix(key, prefix), "/")
+		if !ok {
+			continue
+		}
+		if _, exists := seen[newsID]; exists {
+			continue
+		}
+		seen[newsID] = struct{}{}
+		newsIDs = append(newsIDs, newsID)
+	}
+
+	return newsIDs, nil
+}
+
+func (r *ArchiveRepositoryImpl) objectKey(hash string) string {
+	return fmt.Sprintf("%s/%s/%s.gz", objectPrefix, hash[:2], hash)
+}
+
+func (r *ArchiveRepositoryImpl) newsPrefix(mediaID uint, newsID string) string {
+	return fmt.Sprintf("%s/%d/%s/", responsePrefix, mediaID, newsID)
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzipBytes(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+	defer reader.Close()
+
+	out, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+)
+
+type ArchiveRepository interface {
+	// SaveResponse 壓縮並封存原始回應, 內容以 sha256 定址, 相同內容只保存一份
+	// Args:
+	//   resp: 原始回應, ContentHash 由此方法計算並回填
+	// Returns:
+	//   error: 錯誤資訊
+	SaveResponse(ctx context.Context, resp *entity.RawResponse) error
+
+	// FindLatestResponse 取得新聞最後一次爬取的原始回應
+	FindLatestResponse(ctx context.Context, mediaID uint, newsID string) (*entity.RawResponse, error)
+
+	// FindNewsIDs 取得媒體所有已封存的新聞ID
+	FindNewsIDs(ctx context.Context, mediaID uint) ([]string, error)
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+	"itmrchow/tw-media-analytics-service/domain/utils/blob"
+)
+
+func TestArchiveRepoSuite(t *testing.T) {
+	suite.Run(t, new(ArchiveTestSuite))
+}
+
+type ArchiveTestSuite struct {
+	suite.Suite
+	store       blob.Store
+	archiveRepo ArchiveRepository
+}
+
+func (s *ArchiveTestSuite) SetupTest() {
+	logger := zerolog.Nop()
+
+	store, err := blob.NewLocalStore(s.T().TempDir())
+	s.Require().NoError(err)
+
+	s.store = store
+	s.archiveRepo = NewArchiveRepositoryImpl(&logger, store)
+}
+
+func (s *ArchiveTestSuite) TestSaveResponse_FindLatestResponse() {
+	ctx := context.Background()
+	fetchedAt := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	first := &entity.RawResponse{
+		MediaID:   2,
+		NewsID:    "1600001",
+		URL:       "https://www.setn.com/News.aspx?NewsID=1600001",
+		FetchedAt: fetchedAt,
+		Body:      []byte("<html>v1</html>"),
+	}
+	second := &entity.RawResponse{
+		MediaID:   2,
+		NewsID:    "1600001",
+		URL:       "https://www.setn.com/News.aspx?NewsID=1600001",
+		FetchedAt: fetchedAt.Add(time.Hour),
+		Body:      []byte("<html>v2</html>"),
+	}
+
+	s.Require().NoError(s.archiveRepo.SaveResponse(ctx, first))
+	s.Require().NoError(s.archiveRepo.SaveResponse(ctx, second))
+	s.NotEqual(first.ContentHash, second.ContentHash)
+
+	latest, err := s.archiveRepo.FindLatestResponse(ctx, 2, "1600001")
+	s.Require().NoError(err)
+	s.Equal("<html>v2</html>", string(latest.Body))
+	s.Equal(second.ContentHash, latest.ContentHash)
+	s.True(second.FetchedAt.Equal(latest.FetchedAt))
+}
+
+func (s *ArchiveTestSuite) TestSaveResponse_ContentAddressed() {
+	ctx := context.Background()
+	body := []byte("<html>same</html>")
+
+	for i, newsID := range []string{"1", "2"} {
+		err := s.archiveRepo.SaveResponse(ctx, &entity.RawResponse{
+			MediaID:   1,
+			NewsID:    newsID,
+			FetchedAt: time.Date(2025, 5, 1, 10, i, 0, 0, time.UTC),
+			Body:      body,
+		})
+		s.Require().NoError(err)
+	}
+
+	objects, err := s.store.List(ctx, "objects/")
+	s.Require().NoError(err)
+	s.Len(objects, 1)
+
+	newsIDs, err := s.archiveRepo.FindNewsIDs(ctx, 1)
+	s.Require().NoError(err)
+	s.Equal([]string{"1", "2"}, newsIDs)
+}
+
+func (s *ArchiveTestSuite) TestFindLatestResponse_NotFound() {
+	_, err := s.archiveRepo.FindLatestResponse(context.Background(), 1, "not-exist")
+	s.ErrorIs(err, blob.ErrNotFound)
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+	"itmrchow/tw-media-analytics-service/domain/spider/repository"
+	"itmrchow/tw-media-analytics-service/domain/utils/alert"
+	"itmrchow/tw-media-analytics-service/domain/utils/blob"
+	"itmrchow/tw-media-analytics-service/domain/utils/crawler"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// newFixtureServer 以 testdata/<media> 內錄製的 sitemap 與文章 HTML 建立測試伺服器.
+//
+// 路徑對應:
+//
+//	/robots.txt            -> 404 (允許所有路徑)
+//	/path?NewsID=<id>      -> testdata/<media>/news/<id>.html
+//	/news/items/<id>       -> testdata/<media>/news/items/<id>.html
+//	/rss/sitemap-news.xml  -> testdata/<media>/rss/sitemap-news.xml
+func newFixtureServer(t *testing.T, media string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		if newsID := r.URL.Query().Get("NewsID"); newsID != "" {
+			name = "news/" + newsID
+		}
+		if filepath.Ext(name) == "" {
+			name += ".html"
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", media, filepath.FromSlash(name)))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		if strings.HasSuffix(name, ".xml") {
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// newTestCrawler 建立不延遲, 不重試的 collector 工廠.
+func newTestCrawler(logger *zerolog.Logger) *crawler.Factory {
+	return crawler.NewFactoryWithConfig(logger, crawler.Config{
+		UserAgent:      "tw-media-analytics-service_test",
+		Parallelism:    4,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  time.Millisecond,
+	})
+}
+
+// newTestArchiveRepo 建立以暫存目錄為儲存的封存 repository.
+func newTestArchiveRepo(t *testing.T, logger *zerolog.Logger) repository.ArchiveRepository {
+	t.Helper()
+
+	store, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	return repository.NewArchiveRepositoryImpl(logger, store)
+}
+
+// fakeNotifier 記錄收到的告警.
+type fakeNotifier struct {
+	mu     sync.Mutex
+	alerts []alert.Alert
+}
+
+func (n *fakeNotifier) Notify(_ context.Context, a alert.Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.alerts = append(n.alerts, a)
+	return nil
+}
+
+func (n *fakeNotifier) Alerts() []alert.Alert {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]alert.Alert(nil), n.alerts...)
+}
+
+// newTestQualityMonitor 建立使用 fakeNotifier 的品質監控.
+func newTestQualityMonitor(logger *zerolog.Logger, notifier alert.Notifier) *QualityMonitor {
+	return NewQualityMonitorWithConfig(logger, notifier, otel.Meter("tw-media-analytics-service_test"), QualityConfig{
+		MinBodyLength:    20,
+		RunTimeout:       time.Hour,
+		MinSamples:       2,
+		FailureThreshold: 0.5,
+		MaxFutureSkew:    time.Hour,
+	})
+}
+
+// newTestSpiders 建立指向測試伺服器的爬蟲.
+func newTestSpiders(t *testing.T) (*CtiNewsSpider, *SetnSpider) {
+	t.Helper()
+
+	logger := zerolog.Nop()
+	return newTestSpidersWithArchive(t, newTestArchiveRepo(t, &logger))
+}
+
+// newTestSpidersWithArchive 建立指向測試伺服器且使用指定封存 repository 的爬蟲.
+func newTestSpidersWithArchive(
+	t *testing.T,
+	archiveRepo repository.ArchiveRepository,
+) (*CtiNewsSpider, *SetnSpider) {
+	t.Helper()
+
+	logger := zerolog.Nop()
+	tracer := otel.Tracer("tw-media-analytics-service_test")
+	factory := newTestCrawler(&logger)
+	quality := newTestQualityMonitor(&logger, &fakeNotifier{})
+
+	cti := NewCtiNewsSpider(&logger, tracer, factory, archiveRepo, quality, WithBaseURL(newFixtureServer(t, "ctinews").URL))
+	setn := NewSetnSpider(&logger, tracer, factory, archiveRepo, quality, WithBaseURL(newFixtureServer(t, "setn").URL))
+
+	return cti, setn
+}
+
+// assertGolden 比對 news 與 testdata/<media>/golden/<newsID>.json, 執行時間不列入比對.
+// 使用 go test -update 重新產生 golden file.
+func assertGolden(t *testing.T, media string, news *entity.News) {
+	t.Helper()
+
+	got := *news
+	got.ElapsedTime = 0
+
+	actual, err := json.MarshalIndent(got, "", "  ")
+	require.NoError(t, err)
+	actual = append(actual, '\n')
+
+	path := filepath.Join("testdata", media, "golden", news.NewsID+".json")
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(path, actual, 0o600))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(actual))
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+)
+
+// scrapeMetrics 爬蟲共用的 metric instruments.
+type scrapeMetrics struct {
+	discovered   metric.Int64Counter
+	scraped      metric.Int64Counter
+	duration     metric.Float64Histogram
+	responseSize metric.Int64Histogram
+}
+
+func newScrapeMetrics(meter metric.Meter) (*scrapeMetrics, error) {
+	var m scrapeMetrics
+	var err error
+
+	if m.discovered, err = meter.Int64Counter(
+		"spider.articles.discovered",
+		metric.WithDescription("Number of article ids found in news lists"),
+	); err != nil {
+		return nil, err
+	}
+	if m.scraped, err = meter.Int64Counter(
+		"spider.articles.scraped",
+		metric.WithDescription("Number of articles scraped"),
+	); err != nil {
+		return nil, err
+	}
+	if m.duration, err = meter.Float64Histogram(
+		"spider.scrape.duration",
+		metric.WithDescription("Article scrape latency"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, err
+	}
+	if m.responseSize, err = meter.Int64Histogram(
+		"spider.response.size",
+		metric.WithDescription("Article response body size"),
+		metric.WithUnit("By"),
+	); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// recordDiscovered 記錄新聞列表找到的文章數.
+func (m *scrapeMetrics) recordDiscovered(ctx context.Context, mediaID uint, n int) {
+	m.discovered.Add(ctx, int64(n), metric.WithAttributes(attribute.Int64("media_id", int64(mediaID))))
+}
+
+// recordScraped 記錄成功爬取的文章, 耗時與回應大小.
+func (m *scrapeMetrics) recordScraped(ctx context.Context, mediaID uint, news *entity.News) {
+	attrs := metric.WithAttributes(attribute.Int64("media_id", int64(mediaID)))
+	m.scraped.Add(ctx, 1, attrs)
+	m.duration.Record(ctx, news.ElapsedTime.Seconds(), attrs)
+	m.responseSize.Record(ctx, int64(news.ResponseSize), attrs)
+}
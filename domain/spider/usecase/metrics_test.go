@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+)
+
+func TestScrapeMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	m, err := newScrapeMetrics(provider.Meter("test"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	m.recordDiscovered(ctx, 1, 3)
+	m.recordScraped(ctx, 1, &entity.News{ElapsedTime: 500 * time.Millisecond, ResponseSize: 1024})
+	m.recordScraped(ctx, 2, &entity.News{ElapsedTime: time.Second, ResponseSize: 2048})
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	got := map[string]metricdata.Aggregation{}
+	for _, metric := range rm.ScopeMetrics[0].Metrics {
+		got[metric.Name] = metric.Data
+	}
+
+	discovered := got["spider.articles.discovered"].(metricdata.Sum[int64])
+	require.Len(t, discovered.DataPoints, 1)
+	assert.Equal(t, int64(3), discovered.DataPoints[0].Value)
+
+	scraped := got["spider.articles.scraped"].(metricdata.Sum[int64])
+	assert.Len(t, scraped.DataPoints, 2)
+
+	size := got["spider.response.size"].(metricdata.Histogram[int64])
+	var total int64
+	for _, dp := range size.DataPoints {
+		total += dp.Sum
+	}
+	assert.Equal(t, int64(3072), total)
+}
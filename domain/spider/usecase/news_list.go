@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"golang.org/x/sync/errgroup"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+)
+
+// fetchNewsList 以有限的併發數爬取多篇新聞, 單篇失敗不影響其他新聞.
+// 回傳成功的新聞 (依 newsIDList 順序) 與失敗新聞ID對應的錯誤, 全部成功時錯誤 map 為 nil.
+// ctx 未附加爬取批次時, newsIDList 計為一個批次.
+func fetchNewsList(
+	ctx context.Context,
+	newsIDList []string,
+	concurrency int,
+	getNews func(ctx context.Context, newsID string) (*entity.News, error),
+) ([]*entity.News, map[string]error) {
+	if _, ok := crawlRunFrom(ctx); !ok {
+		ctx = WithCrawlRun(ctx, CrawlRun{ID: watermill.NewUUID(), Size: len(newsIDList)})
+	}
+
+	results := make([]*entity.News, len(newsIDList))
+
+	var mu sync.Mutex
+	var errMap map[string]error
+	setErr := func(newsID string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if errMap == nil {
+			errMap = make(map[string]error)
+		}
+		errMap[newsID] = err
+	}
+
+	var group errgroup.Group
+	group.SetLimit(max(concurrency, 1))
+
+	for i, newsID := range newsIDList {
+		group.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				setErr(newsID, err)
+				return nil
+			}
+
+			news, err := getNews(ctx, newsID)
+			if err != nil {
+				setErr(newsID, err)
+				return nil
+			}
+
+			results[i] = news
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	newsDataList := make([]*entity.News, 0, len(newsIDList))
+	for _, news := range results {
+		if news != nil {
+			newsDataList = append(newsDataList, news)
+		}
+	}
+
+	return newsDataList, errMap
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+)
+
+func TestFetchNewsList(t *testing.T) {
+	errNotFound := errors.New("not found")
+
+	var running, maxRunning atomic.Int32
+	getNews := func(_ context.Context, newsID string) (*entity.News, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if newsID == "2" || newsID == "4" {
+			return nil, errNotFound
+		}
+		return &entity.News{NewsID: newsID}, nil
+	}
+
+	newsList, errMap := fetchNewsList(context.Background(), []string{"1", "2", "3", "4", "5"}, 2, getNews)
+
+	ids := make([]string, 0, len(newsList))
+	for _, news := range newsList {
+		ids = append(ids, news.NewsID)
+	}
+	assert.Equal(t, []string{"1", "3", "5"}, ids)
+	assert.Len(t, errMap, 2)
+	assert.ErrorIs(t, errMap["2"], errNotFound)
+	assert.ErrorIs(t, errMap["4"], errNotFound)
+	assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+}
+
+func TestFetchNewsList_AllSuccess(t *testing.T) {
+	getNews := func(_ context.Context, newsID string) (*entity.News, error) {
+		return &entity.News{NewsID: newsID}, nil
+	}
+
+	newsList, errMap := fetchNewsList(context.Background(), []string{"1", "2"}, 4, getNews)
+
+	assert.Len(t, newsList, 2)
+	assert.Nil(t, errMap)
+}
+
+func TestFetchNewsList_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	getNews := func(_ context.Context, newsID string) (*entity.News, error) {
+		return &entity.News{NewsID: newsID}, nil
+	}
+
+	newsList, errMap := fetchNewsList(ctx, []string{"1", "2"}, 1, getNews)
+
+	assert.Empty(t, newsList)
+	assert.ErrorIs(t, errMap["1"], context.Canceled)
+	assert.ErrorIs(t, errMap["2"], context.Canceled)
+}
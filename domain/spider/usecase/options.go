@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Option 爬蟲設定選項.
+type Option func(*options)
+
+type options struct {
+	baseURL string
+}
+
+// WithBaseURL 指定爬蟲的網站根網址, 用於測試時替換為 httptest.Server.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+func newOptions(defaultBaseURL string, opts ...Option) options {
+	o := options{baseURL: defaultBaseURL}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// host 取得 baseURL 的 host, 用於套用 per-host LimitRule.
+func (o options) host() string {
+	u, err := url.Parse(o.baseURL)
+	if err != nil {
+		return o.baseURL
+	}
+	return u.Host
+}
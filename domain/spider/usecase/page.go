@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocolly/colly"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+	"itmrchow/tw-media-analytics-service/domain/utils/crawler"
+)
+
+// fetchPage 透過共用的 collector 工廠取得頁面原始回應.
+func fetchPage(ctx context.Context, factory *crawler.Factory, host string, url string) (*entity.RawResponse, error) {
+	collector, err := factory.NewCollector(host)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *entity.RawResponse
+	collector.OnResponse(func(r *colly.Response) {
+		resp = &entity.RawResponse{
+			URL:         r.Request.URL.String(),
+			StatusCode:  r.StatusCode,
+			ContentType: r.Headers.Get("Content-Type"),
+			FetchedAt:   time.Now(),
+			Body:        r.Body,
+		}
+	})
+
+	if err = factory.Visit(ctx, collector, url); err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("empty response: %s", url)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,401 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+	"itmrchow/tw-media-analytics-service/domain/utils/alert"
+)
+
+const (
+	defaultQualityMinBodyLength    = 100
+	defaultQualityRunTimeout       = 30 * time.Minute
+	defaultQualityMinSamples       = 5
+	defaultQualityFailureThreshold = 0.3
+	defaultQualityMaxFutureSkew    = 1 * time.Hour
+)
+
+// ErrExtractionFailed 解析結果缺少標題或內文, 不應儲存.
+var ErrExtractionFailed = errors.New("news extraction failed")
+
+// QualityIssue 抽取品質問題.
+type QualityIssue string
+
+const (
+	QualityIssueParseError         QualityIssue = "parse_error"
+	QualityIssueEmptyTitle         QualityIssue = "empty_title"
+	QualityIssueEmptyBody          QualityIssue = "empty_body"
+	QualityIssueShortBody          QualityIssue = "short_body"
+	QualityIssueInvalidPublishedAt QualityIssue = "invalid_published_at"
+)
+
+// critical 代表問題嚴重到此篇新聞不可用.
+func (i QualityIssue) critical() bool {
+	switch i {
+	case QualityIssueParseError, QualityIssueEmptyTitle, QualityIssueEmptyBody:
+		return true
+	default:
+		return false
+	}
+}
+
+// QualityConfig 抽取品質檢查設定.
+type QualityConfig struct {
+	MinBodyLength    int           // 內文最少字數
+	RunTimeout       time.Duration // 爬取批次超過此時間未完成即以已檢查的文章計算失敗率
+	MinSamples       int           // 批次文章數達到此值才判斷是否降級
+	FailureThreshold float64       // 批次失敗率超過此值即標記降級
+	MaxFutureSkew    time.Duration // 發布時間可超前現在的上限
+}
+
+// NewQualityConfig 從 viper 讀取抽取品質檢查設定, 未設定則使用預設值.
+func NewQualityConfig() QualityConfig {
+	cfg := QualityConfig{
+		MinBodyLength:    viper.GetInt("SPIDER_QUALITY_MIN_BODY_LENGTH"),
+		RunTimeout:       viper.GetDuration("SPIDER_QUALITY_RUN_TIMEOUT"),
+		MinSamples:       viper.GetInt("SPIDER_QUALITY_MIN_SAMPLES"),
+		FailureThreshold: viper.GetFloat64("SPIDER_QUALITY_FAILURE_THRESHOLD"),
+		MaxFutureSkew:    defaultQualityMaxFutureSkew,
+	}
+
+	if cfg.MinBodyLength <= 0 {
+		cfg.MinBodyLength = defaultQualityMinBodyLength
+	}
+	if cfg.RunTimeout <= 0 {
+		cfg.RunTimeout = defaultQualityRunTimeout
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = defaultQualityMinSamples
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaultQualityFailureThreshold
+	}
+
+	return cfg
+}
+
+// CheckQuality 檢查單篇新聞的抽取品質, 回傳發現的問題.
+func CheckQuality(news *entity.News, cfg QualityConfig, now time.Time) []QualityIssue {
+	if news == nil {
+		return []QualityIssue{QualityIssueParseError}
+	}
+
+	var issues []QualityIssue
+
+	if strings.TrimSpace(news.Headline) == "" {
+		issues = append(issues, QualityIssueEmptyTitle)
+	}
+
+	body := strings.TrimSpace(news.NewsContext)
+	switch {
+	case body == "":
+		issues = append(issues, QualityIssueEmptyBody)
+	case utf8.RuneCountInString(body) < cfg.MinBodyLength:
+		issues = append(issues, QualityIssueShortBody)
+	}
+
+	if news.DatePublished.IsZero() || news.DatePublished.After(now.Add(cfg.MaxFutureSkew)) {
+		issues = append(issues, QualityIssueInvalidPublishedAt)
+	}
+
+	return issues
+}
+
+// CrawlRun 同一次爬取 (同一份文章列表) 的文章, 失敗率以批次為單位計算.
+type CrawlRun struct {
+	ID   string
+	Size int // 此批次要檢查的文章數
+}
+
+type crawlRunKey struct{}
+
+// WithCrawlRun 將爬取批次附加至 ctx, 品質檢查時計入該批次.
+// 未附加批次的文章只檢查單篇品質, 不影響降級判斷.
+func WithCrawlRun(ctx context.Context, run CrawlRun) context.Context {
+	return context.WithValue(ctx, crawlRunKey{}, run)
+}
+
+func crawlRunFrom(ctx context.Context) (CrawlRun, bool) {
+	run, ok := ctx.Value(crawlRunKey{}).(CrawlRun)
+	return run, ok && run.ID != "" && run.Size > 0
+}
+
+// QualityStatus 單一媒體的爬蟲品質狀態, Samples 與 FailureRatio 為最近一次完成的批次.
+type QualityStatus struct {
+	MediaID       uint      `json:"mediaId"`
+	Samples       int       `json:"samples"`
+	FailureRatio  float64   `json:"failureRatio"`
+	Degraded      bool      `json:"degraded"`
+	LastSuccessAt time.Time `json:"lastSuccessAt"` // 最後一次成功爬取 (無嚴重問題) 的時間
+}
+
+// runState 進行中的爬取批次.
+type runState struct {
+	size      int
+	checked   int
+	failures  int
+	startedAt time.Time
+}
+
+func (r *runState) ratio() float64 {
+	if r.checked == 0 {
+		return 0
+	}
+	return float64(r.failures) / float64(r.checked)
+}
+
+// qualityState 單一媒體進行中的批次與最近一次完成批次的結果.
+type qualityState struct {
+	runs          map[string]*runState
+	samples       int
+	ratio         float64
+	degraded      bool
+	lastSuccessAt time.Time
+}
+
+// finish 以完成的批次更新失敗率, 文章數達 minSamples 才判斷是否降級.
+func (s *qualityState) finish(run *runState, cfg QualityConfig) {
+	s.samples = run.checked
+	s.ratio = run.ratio()
+	if run.checked >= cfg.MinSamples {
+		s.degraded = s.ratio > cfg.FailureThreshold
+	}
+}
+
+// QualityMonitor 記錄爬蟲抽取品質, 失敗率超過門檻時將爬蟲標記為降級並發出告警.
+type QualityMonitor struct {
+	logger   *zerolog.Logger
+	notifier alert.Notifier
+	cfg      QualityConfig
+
+	checked      metric.Int64Counter
+	issues       metric.Int64Counter
+	failureRatio metric.Float64Gauge
+	degraded     metric.Int64Gauge
+
+	mu     sync.Mutex
+	states map[uint]*qualityState
+}
+
+// NewQualityMonitor 以 viper 設定建立品質監控.
+func NewQualityMonitor(logger *zerolog.Logger, notifier alert.Notifier) *QualityMonitor {
+	return NewQualityMonitorWithConfig(logger, notifier, otel.Meter("domain/spider"), NewQualityConfig())
+}
+
+// NewQualityMonitorWithConfig 以指定 meter 與設定建立品質監控.
+func NewQualityMonitorWithConfig(
+	logger *zerolog.Logger,
+	notifier alert.Notifier,
+	meter metric.Meter,
+	cfg QualityConfig,
+) *QualityMonitor {
+	m := &QualityMonitor{
+		logger:   logger,
+		notifier: notifier,
+		cfg:      cfg,
+		states:   make(map[uint]*qualityState),
+	}
+
+	var err error
+	if m.checked, err = meter.Int64Counter(
+		"spider.extraction.checked",
+		metric.WithDescription("Number of scraped articles checked for extraction quality"),
+	); err != nil {
+		logger.Fatal().Err(err).Msg("failed to create spider.extraction.checked counter")
+	}
+	if m.issues, err = meter.Int64Counter(
+		"spider.extraction.issues",
+		metric.WithDescription("Number of extraction quality issues by type"),
+	); err != nil {
+		logger.Fatal().Err(err).Msg("failed to create spider.extraction.issues counter")
+	}
+	if m.failureRatio, err = meter.Float64Gauge(
+		"spider.extraction.failure_ratio",
+		metric.WithDescription("Extraction failure ratio of the last finished crawl run"),
+	); err != nil {
+		logger.Fatal().Err(err).Msg("failed to create spider.extraction.failure_ratio gauge")
+	}
+	if m.degraded, err = meter.Int64Gauge(
+		"spider.degraded",
+		metric.WithDescription("1 if the spider is marked degraded, otherwise 0"),
+	); err != nil {
+		logger.Fatal().Err(err).Msg("failed to create spider.degraded gauge")
+	}
+
+	return m
+}
+
+// Record 檢查並記錄一篇新聞的抽取品質, news 為 nil 代表解析失敗.
+// ctx 附加 CrawlRun 時計入該批次, 批次完成或逾時時更新失敗率與降級狀態.
+// 若新聞缺少標題或內文, 回傳 ErrExtractionFailed.
+func (m *QualityMonitor) Record(ctx context.Context, mediaID uint, news *entity.News) error {
+	issues := CheckQuality(news, m.cfg, time.Now())
+
+	mediaAttr := attribute.Int64("media_id", int64(mediaID))
+	result := "ok"
+	if len(issues) > 0 {
+		result = "failed"
+	}
+	m.checked.Add(ctx, 1, metric.WithAttributes(mediaAttr, attribute.String("result", result)))
+
+	var critical []string
+	for _, issue := range issues {
+		m.issues.Add(ctx, 1, metric.WithAttributes(mediaAttr, attribute.String("issue", string(issue))))
+		if issue.critical() {
+			critical = append(critical, string(issue))
+		}
+	}
+
+	run, _ := crawlRunFrom(ctx)
+	status, changed := m.update(mediaID, run, len(issues) > 0, len(critical) == 0)
+
+	m.failureRatio.Record(ctx, status.FailureRatio, metric.WithAttributes(mediaAttr))
+	var degraded int64
+	if status.Degraded {
+		degraded = 1
+	}
+	m.degraded.Record(ctx, degraded, metric.WithAttributes(mediaAttr))
+
+	if len(issues) > 0 {
+		event := m.logger.Warn().Ctx(ctx).Uint("media_id", mediaID).Interface("issues", issues)
+		if news != nil {
+			event = event.Str("news_id", news.NewsID)
+		}
+		event.Msg("extraction quality issues")
+	}
+
+	if changed {
+		m.notify(ctx, status)
+	}
+
+	if len(critical) > 0 {
+		return fmt.Errorf("%w: %s", ErrExtractionFailed, strings.Join(critical, ","))
+	}
+
+	return nil
+}
+
+// Degraded 回傳媒體爬蟲是否被標記為降級.
+func (m *QualityMonitor) Degraded(mediaID uint) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[mediaID]
+	return ok && state.degraded
+}
+
+// Status 回傳所有已記錄媒體的品質狀態.
+func (m *QualityMonitor) Status() []QualityStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statusList := make([]QualityStatus, 0, len(m.states))
+	for mediaID, state := range m.states {
+		statusList = append(statusList, state.status(mediaID))
+	}
+	sort.Slice(statusList, func(i, j int) bool { return statusList[i].MediaID < statusList[j].MediaID })
+
+	return statusList
+}
+
+func (s *qualityState) status(mediaID uint) QualityStatus {
+	return QualityStatus{
+		MediaID:       mediaID,
+		Samples:       s.samples,
+		FailureRatio:  s.ratio,
+		Degraded:      s.degraded,
+		LastSuccessAt: s.lastSuccessAt,
+	}
+}
+
+// update 更新媒體的檢查結果, 回傳最新狀態以及降級狀態是否改變.
+// usable 代表此篇新聞可用, 會更新最後成功時間.
+func (m *QualityMonitor) update(mediaID uint, run CrawlRun, failed bool, usable bool) (QualityStatus, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	state, ok := m.states[mediaID]
+	if !ok {
+		state = &qualityState{runs: make(map[string]*runState)}
+		m.states[mediaID] = state
+	}
+	if usable {
+		state.lastSuccessAt = now
+	}
+	degraded := state.degraded
+
+	if run.ID != "" {
+		current, ok := state.runs[run.ID]
+		if !ok {
+			current = &runState{size: run.Size, startedAt: now}
+			state.runs[run.ID] = current
+		}
+		current.checked++
+		if failed {
+			current.failures++
+		}
+		if current.checked >= current.size {
+			state.finish(current, m.cfg)
+			delete(state.runs, run.ID)
+		}
+	}
+
+	// 部分文章爬取失敗 (未進入品質檢查) 的批次, 逾時後依開始時間以已檢查的文章計算
+	var expired []string
+	for id, r := range state.runs {
+		if now.Sub(r.startedAt) >= m.cfg.RunTimeout {
+			expired = append(expired, id)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return state.runs[expired[i]].startedAt.Before(state.runs[expired[j]].startedAt)
+	})
+	for _, id := range expired {
+		state.finish(state.runs[id], m.cfg)
+		delete(state.runs, id)
+	}
+
+	return state.status(mediaID), degraded != state.degraded
+}
+
+// notify 發送降級 / 恢復告警, 失敗只記錄 log.
+func (m *QualityMonitor) notify(ctx context.Context, status QualityStatus) {
+	a := alert.Alert{
+		Source: "spider",
+		Time:   time.Now(),
+		Fields: map[string]any{
+			"media_id":      status.MediaID,
+			"samples":       status.Samples,
+			"failure_ratio": status.FailureRatio,
+			"threshold":     m.cfg.FailureThreshold,
+		},
+	}
+
+	if status.Degraded {
+		a.Status = "degraded"
+		a.Text = fmt.Sprintf("spider media_id=%d degraded: extraction failure ratio %.2f exceeds %.2f",
+			status.MediaID, status.FailureRatio, m.cfg.FailureThreshold)
+	} else {
+		a.Status = "recovered"
+		a.Text = fmt.Sprintf("spider media_id=%d recovered: extraction failure ratio %.2f",
+			status.MediaID, status.FailureRatio)
+	}
+
+	if err := m.notifier.Notify(ctx, a); err != nil {
+		m.logger.Error().Err(err).Ctx(ctx).Uint("media_id", status.MediaID).Msg("failed to send spider quality alert")
+	}
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+)
+
+func TestCheckQuality(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	cfg := QualityConfig{MinBodyLength: 10, MaxFutureSkew: time.Hour}
+
+	tests := []struct {
+		name string
+		news *entity.News
+		want []QualityIssue
+	}{
+		{
+			name: "正常",
+			news: &entity.News{Headline: "標題", NewsContext: "這是一段足夠長的新聞內文", DatePublished: now},
+			want: nil,
+		},
+		{
+			name: "解析失敗",
+			news: nil,
+			want: []QualityIssue{QualityIssueParseError},
+		},
+		{
+			name: "空標題與空內文",
+			news: &entity.News{Headline: " ", NewsContext: "\n", DatePublished: now},
+			want: []QualityIssue{QualityIssueEmptyTitle, QualityIssueEmptyBody},
+		},
+		{
+			name: "內文過短",
+			news: &entity.News{Headline: "標題", NewsContext: "太短", DatePublished: now},
+			want: []QualityIssue{QualityIssueShortBody},
+		},
+		{
+			name: "無發布時間",
+			news: &entity.News{Headline: "標題", NewsContext: "這是一段足夠長的新聞內文"},
+			want: []QualityIssue{QualityIssueInvalidPublishedAt},
+		},
+		{
+			name: "發布時間在未來",
+			news: &entity.News{Headline: "標題", NewsContext: "這是一段足夠長的新聞內文", DatePublished: now.Add(2 * time.Hour)},
+			want: []QualityIssue{QualityIssueInvalidPublishedAt},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CheckQuality(tt.news, cfg, now))
+		})
+	}
+}
+
+func TestQualityMonitor_DegradeAndRecover(t *testing.T) {
+	logger := zerolog.Nop()
+	notifier := &fakeNotifier{}
+	monitor := newTestQualityMonitor(&logger, notifier)
+	ctx := context.Background()
+
+	good := &entity.News{NewsID: "good", Headline: "標題", NewsContext: "這是一段足夠長的新聞內文, 用於品質檢查", DatePublished: time.Now()}
+	empty := &entity.News{NewsID: "empty"}
+
+	// 先前的批次都正常
+	run1 := WithCrawlRun(ctx, CrawlRun{ID: "run-1", Size: 4})
+	for range 4 {
+		require.NoError(t, monitor.Record(run1, 1, good))
+	}
+	assert.False(t, monitor.Degraded(1))
+
+	// 未附加批次的文章不影響降級判斷
+	require.ErrorIs(t, monitor.Record(ctx, 1, empty), ErrExtractionFailed)
+	assert.False(t, monitor.Degraded(1))
+
+	// 批次完成前不判斷, 完成後以該批次 2/2 失敗降級, 不被先前正常的批次稀釋
+	run2 := WithCrawlRun(ctx, CrawlRun{ID: "run-2", Size: 2})
+	require.ErrorIs(t, monitor.Record(run2, 1, empty), ErrExtractionFailed)
+	assert.False(t, monitor.Degraded(1))
+	require.ErrorIs(t, monitor.Record(run2, 1, empty), ErrExtractionFailed)
+	assert.True(t, monitor.Degraded(1))
+	assert.False(t, monitor.Degraded(2))
+
+	// 樣本數不足的批次不改變狀態
+	run3 := WithCrawlRun(ctx, CrawlRun{ID: "run-3", Size: 1})
+	require.NoError(t, monitor.Record(run3, 1, good))
+	assert.True(t, monitor.Degraded(1))
+
+	// 4 篇中 2 篇失敗, 未超過門檻, 恢復
+	run4 := WithCrawlRun(ctx, CrawlRun{ID: "run-4", Size: 4})
+	require.NoError(t, monitor.Record(run4, 1, good))
+	require.ErrorIs(t, monitor.Record(run4, 1, empty), ErrExtractionFailed)
+	require.NoError(t, monitor.Record(run4, 1, good))
+	require.ErrorIs(t, monitor.Record(run4, 1, empty), ErrExtractionFailed)
+	assert.False(t, monitor.Degraded(1))
+
+	alerts := notifier.Alerts()
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "degraded", alerts[0].Status)
+	assert.Equal(t, "recovered", alerts[1].Status)
+
+	status := monitor.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, uint(1), status[0].MediaID)
+	assert.Equal(t, 4, status[0].Samples)
+	assert.InDelta(t, 0.5, status[0].FailureRatio, 0.001)
+	assert.False(t, status[0].Degraded)
+	assert.WithinDuration(t, time.Now(), status[0].LastSuccessAt, time.Second)
+}
+
+func TestQualityMonitor_RunTimeout(t *testing.T) {
+	logger := zerolog.Nop()
+	monitor := NewQualityMonitorWithConfig(&logger, &fakeNotifier{}, otel.Meter("tw-media-analytics-service_test"), QualityConfig{
+		MinBodyLength:    20,
+		RunTimeout:       time.Millisecond,
+		MinSamples:       2,
+		FailureThreshold: 0.5,
+		MaxFutureSkew:    time.Hour,
+	})
+	ctx := context.Background()
+	empty := &entity.News{NewsID: "empty"}
+
+	// 5 篇中只有 2 篇進入檢查 (其餘爬取失敗), 逾時後以已檢查的文章判斷
+	run := WithCrawlRun(ctx, CrawlRun{ID: "run-1", Size: 5})
+	require.Error(t, monitor.Record(run, 1, empty))
+	require.Error(t, monitor.Record(run, 1, empty))
+	assert.False(t, monitor.Degraded(1))
+
+	time.Sleep(2 * time.Millisecond)
+	require.Error(t, monitor.Record(ctx, 1, empty))
+	assert.True(t, monitor.Degraded(1))
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+	"itmrchow/tw-media-analytics-service/domain/spider/repository"
+)
+
+// Reparser 以封存的原始回應重新執行爬蟲解析, 不對新聞網站發出請求.
+type Reparser struct {
+	tracer      trace.Tracer
+	logger      *zerolog.Logger
+	archiveRepo repository.ArchiveRepository
+	quality     *QualityMonitor
+	spiderMap   map[uint]Spider
+}
+
+func NewReparser(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	archiveRepo repository.ArchiveRepository,
+	quality *QualityMonitor,
+	spiders []Spider,
+) *Reparser {
+	m := make(map[uint]Spider, len(spiders))
+	for _, s := range spiders {
+		m[s.GetMediaID()] = s
+	}
+
+	return &Reparser{
+		tracer:      tracer,
+		logger:      logger,
+		archiveRepo: archiveRepo,
+		quality:     quality,
+		spiderMap:   m,
+	}
+}
+
+// Reparse 重新解析媒體的封存新聞, newsIDList 為空時解析該媒體所有已封存的新聞.
+// 與爬取相同經過抽取品質檢查, 缺少標題或內文的新聞不回傳, 本次重新解析計為一個批次.
+// 單篇失敗不影響其他新聞, 失敗新聞ID對應的錯誤以 map 回傳.
+func (r *Reparser) Reparse(
+	ctx context.Context,
+	mediaID uint,
+	newsIDList []string,
+) ([]*entity.News, map[string]error, error) {
+	// Trace
+	ctx, span := r.tracer.Start(ctx, "domain/spider/usecase/reparse/Reparse: Reparse Archived News")
+	r.logger.Info().Ctx(ctx).Uint("media_id", mediaID).Msg("Reparse: start")
+	defer func() {
+		r.logger.Info().Ctx(ctx).Uint("media_id", mediaID).Msg("Reparse: end")
+		span.End()
+	}()
+
+	spider, ok := r.spiderMap[mediaID]
+	if !ok {
+		return nil, nil, fmt.Errorf("spider not found, mediaID: %v", mediaID)
+	}
+
+	if len(newsIDList) == 0 {
+		var err error
+		if newsIDList, err = r.archiveRepo.FindNewsIDs(ctx, mediaID); err != nil {
+			r.logger.Error().Err(err).Ctx(ctx).Msg("failed to find archived news ids")
+			return nil, nil, err
+		}
+	}
+
+	ctx = WithCrawlRun(ctx, CrawlRun{ID: watermill.NewUUID(), Size: len(newsIDList)})
+
+	newsList := make([]*entity.News, 0, len(newsIDList))
+	var errMap map[string]error
+	for _, newsID := range newsIDList {
+		news, err := r.reparseNews(ctx, spider, newsID)
+		if err != nil {
+			if errMap == nil {
+				errMap = make(map[string]error)
+			}
+			errMap[newsID] = err
+			r.logger.Error().Err(err).Ctx(ctx).Str("news_id", newsID).Msg("failed to reparse news")
+			continue
+		}
+		newsList = append(newsList, news)
+	}
+
+	return newsList, errMap, nil
+}
+
+func (r *Reparser) reparseNews(ctx context.Context, spider Spider, newsID string) (*entity.News, error) {
+	resp, err := r.archiveRepo.FindLatestResponse(ctx, spider.GetMediaID(), newsID)
+	if err != nil {
+		return nil, err
+	}
+
+	news, err := spider.ParseNews(ctx, newsID, resp.Body)
+	if err != nil {
+		_ = r.quality.Record(ctx, spider.GetMediaID(), nil)
+		return nil, err
+	}
+
+	if err = r.quality.Record(ctx, spider.GetMediaID(), news); err != nil {
+		return nil, err
+	}
+
+	return news, nil
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+)
+
+func TestReparser_Reparse(t *testing.T) {
+	logger := zerolog.Nop()
+	archiveRepo := newTestArchiveRepo(t, &logger)
+	cti, setn := newTestSpidersWithArchive(t, archiveRepo)
+
+	// 先爬取一次, 產生封存
+	_, errMap := setn.GetNewsList(context.Background(), []string{"1600001", "1600002"})
+	require.Nil(t, errMap)
+
+	// 爬蟲指向不存在的網站, 確保重新解析不會發出網路請求
+	offlineSetn := NewSetnSpider(&logger, setn.tracer, setn.crawler, archiveRepo, setn.quality, WithBaseURL("http://127.0.0.1:0"))
+	reparser := NewReparser(&logger, otel.Tracer("tw-media-analytics-service_test"), archiveRepo, setn.quality, []Spider{cti, offlineSetn})
+
+	newsList, errMap, err := reparser.Reparse(context.Background(), setn.GetMediaID(), nil)
+	require.NoError(t, err)
+	assert.Nil(t, errMap)
+	require.Len(t, newsList, 2)
+
+	for _, news := range newsList {
+		assertGolden(t, "setn", news)
+	}
+}
+
+func TestReparser_Reparse_NotArchived(t *testing.T) {
+	logger := zerolog.Nop()
+	archiveRepo := newTestArchiveRepo(t, &logger)
+	cti, setn := newTestSpidersWithArchive(t, archiveRepo)
+	reparser := NewReparser(&logger, otel.Tracer("tw-media-analytics-service_test"), archiveRepo, setn.quality, []Spider{cti, setn})
+
+	newsList, errMap, err := reparser.Reparse(context.Background(), cti.GetMediaID(), []string{"a1B2c3D4e5"})
+	require.NoError(t, err)
+	assert.Empty(t, newsList)
+	assert.Error(t, errMap["a1B2c3D4e5"])
+}
+
+func TestReparser_Reparse_QualityGate(t *testing.T) {
+	logger := zerolog.Nop()
+	archiveRepo := newTestArchiveRepo(t, &logger)
+	cti, setn := newTestSpidersWithArchive(t, archiveRepo)
+	reparser := NewReparser(&logger, otel.Tracer("tw-media-analytics-service_test"), archiveRepo, setn.quality, []Spider{cti, setn})
+
+	// 封存的頁面已改版, 解析不到標題與內文
+	require.NoError(t, archiveRepo.SaveResponse(context.Background(), &entity.RawResponse{
+		MediaID:   setn.GetMediaID(),
+		NewsID:    "broken",
+		FetchedAt: time.Now(),
+		Body:      []byte("<html><body>redesigned</body></html>"),
+	}))
+
+	newsList, errMap, err := reparser.Reparse(context.Background(), setn.GetMediaID(), []string{"broken"})
+	require.NoError(t, err)
+	assert.Empty(t, newsList)
+	assert.ErrorIs(t, errMap["broken"], ErrExtractionFailed)
+}
+
+func TestReparser_Reparse_UnknownMedia(t *testing.T) {
+	logger := zerolog.Nop()
+	archiveRepo := newTestArchiveRepo(t, &logger)
+	reparser := NewReparser(&logger, otel.Tracer("tw-media-analytics-service_test"), archiveRepo, newTestQualityMonitor(&logger, &fakeNotifier{}), nil)
+
+	_, _, err := reparser.Reparse(context.Background(), 99, nil)
+	require.Error(t, err)
+}
@@ -1,18 +1,23 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 
 	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+	"itmrchow/tw-media-analytics-service/domain/spider/repository"
+	"itmrchow/tw-media-analytics-service/domain/utils/crawler"
 )
 
 var _ Spider = &CtiNewsSpider{}
@@ -20,21 +25,47 @@ var _ Spider = &CtiNewsSpider{}
 type CtiNewsSpider struct {
 	tracer          trace.Tracer
 	logger          *zerolog.Logger
+	crawler         *crawler.Factory
+	archiveRepo     repository.ArchiveRepository
+	quality         *QualityMonitor
+	metrics         *scrapeMetrics
+	host            string
+	concurrency     int
 	newsPageURL     string
 	newsListPageURL string
 	goquerySelector string
 	mediaID         uint
 }
 
-func NewCtiNewsSpider(logger *zerolog.Logger, tracer trace.Tracer) *CtiNewsSpider {
+func NewCtiNewsSpider(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	crawler *crawler.Factory,
+	archiveRepo repository.ArchiveRepository,
+	quality *QualityMonitor,
+	opts ...Option,
+) *CtiNewsSpider {
+	o := newOptions("https://ctinews.com", opts...)
+
 	var spider = &CtiNewsSpider{
 		tracer:          tracer,
 		logger:          logger,
-		newsPageURL:     "https://ctinews.com/news/items/%s",
-		newsListPageURL: "https://ctinews.com/rss/sitemap-news.xml",
+		crawler:         crawler,
+		archiveRepo:     archiveRepo,
+		quality:         quality,
+		host:            o.host(),
+		newsPageURL:     o.baseURL + "/news/items/%s",
+		newsListPageURL: o.baseURL + "/rss/sitemap-news.xml",
 		goquerySelector: "script[type='application/ld+json']",
 		mediaID:         1,
 	}
+	spider.concurrency = crawler.Concurrency(viper.GetInt("SPIDER_CTINEWS_CONCURRENCY"))
+
+	metrics, err := newScrapeMetrics(otel.Meter("domain/spider"))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create spider metrics")
+	}
+	spider.metrics = metrics
 
 	return spider
 }
@@ -52,39 +83,67 @@ func (c *CtiNewsSpider) GetNews(ctx context.Context, newsID string) (*entity.New
 
 	c.logger.Info().Ctx(ctx).Uint("media_id", c.mediaID).Msg("GetNews: start")
 
-	// 建立新的收集器
-	collector := colly.NewCollector()
-
-	// 設定請求頭
-	collector.OnRequest(func(r *colly.Request) {
-		r.Headers.Set(
-			"User-Agent",
-			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
-		)
-	})
-
 	// 記錄開始時間
 	startTime := time.Now()
 
-	// 儲存回應大小
-	var responseSize int
+	// 開始抓取
+	url := fmt.Sprintf(c.newsPageURL, newsID)
+	resp, err := fetchPage(ctx, c.crawler, c.host, url)
+	if err != nil {
+		c.logger.Error().Err(err).Ctx(ctx).Msgf("訪問 URL 錯誤: %v, URL: %s", err, url)
+		return nil, err
+	}
+	resp.MediaID = c.mediaID
+	resp.NewsID = newsID
+
+	// 封存原始回應, 失敗不影響爬取
+	if err = c.archiveRepo.SaveResponse(ctx, resp); err != nil {
+		c.logger.Error().Err(err).Ctx(ctx).Msg("封存原始回應錯誤")
+	}
+
+	newsData, err := c.ParseNews(ctx, newsID, resp.Body)
+	if err != nil {
+		_ = c.quality.Record(ctx, c.mediaID, nil)
+		c.logger.Error().Err(err).Ctx(ctx).Msg("解析 HTML 錯誤")
+		return nil, err
+	}
+
+	// 檢查抽取品質, 缺少標題或內文則不回傳
+	if err = c.quality.Record(ctx, c.mediaID, newsData); err != nil {
+		c.logger.Error().Err(err).Ctx(ctx).Str("news_id", newsID).Msg("抽取品質檢查失敗")
+		return nil, err
+	}
+
+	// 計算執行時間
+	elapsedTime := time.Since(startTime)
+
+	newsData.ElapsedTime = elapsedTime
+	c.metrics.recordScraped(ctx, c.mediaID, newsData)
+
+	c.logger.Info().Ctx(ctx).
+		Str("id", newsData.NewsID).
+		Str("title", newsData.Headline[:min(10, len(newsData.Headline))]).
+		Dur("elapsed_time", elapsedTime).
+		Int("response_size", newsData.ResponseSize).
+		Msg("News scraping completed")
+
+	return newsData, nil
+}
+
+// ParseNews 從文章 HTML 解析新聞, 不發出任何網路請求.
+func (c *CtiNewsSpider) ParseNews(ctx context.Context, newsID string, body []byte) (*entity.News, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html: %w", err)
+	}
 
 	// 儲存新聞資料
 	var newsData entity.News
 	newsData.NewsID = newsID
-
-	// 處理回應
-	collector.OnResponse(func(r *colly.Response) {
-		responseSize = len(r.Body)
-	})
-
-	// 處理錯誤
-	collector.OnError(func(r *colly.Response, err error) {
-		c.logger.Error().Err(err).Ctx(ctx).Msg("錯誤")
-	})
+	newsData.ResponseSize = len(body)
 
 	// 處理 JSON 資料
-	collector.OnHTML("script[type='application/ld+json']", func(e *colly.HTMLElement) {
+	doc.Find(c.goquerySelector).Each(func(_ int, sel *goquery.Selection) {
 
 		// 先解析 type
 		var NewsArticle struct {
@@ -101,7 +160,7 @@ func (c *CtiNewsSpider) GetNews(ctx context.Context, newsID string) (*entity.New
 			DateModified  time.Time `json:"dateModified"`
 		}
 
-		err := json.Unmarshal([]byte(e.Text), &NewsArticle)
+		err := json.Unmarshal([]byte(sel.Text()), &NewsArticle)
 		if err != nil {
 			c.logger.Error().Err(err).Ctx(ctx).Msg("解析 JSON 錯誤")
 			return
@@ -112,7 +171,6 @@ func (c *CtiNewsSpider) GetNews(ctx context.Context, newsID string) (*entity.New
 		}
 
 		// 解析 JSON
-		newsData.NewsID = newsID
 		newsData.Headline = NewsArticle.Headline
 		newsData.Author.Type = NewsArticle.Author.Type
 		newsData.Author.Name = NewsArticle.Author.Name
@@ -121,36 +179,12 @@ func (c *CtiNewsSpider) GetNews(ctx context.Context, newsID string) (*entity.New
 		newsData.NewsContext = NewsArticle.Content
 		newsData.Category = NewsArticle.Category
 		newsData.URL = NewsArticle.URL
-
-		newsData.ResponseSize = responseSize
-		newsData.ElapsedTime = time.Since(startTime)
 	})
 
-	// 開始抓取
-	url := fmt.Sprintf(c.newsPageURL, newsID)
-	err := collector.Visit(url)
-	if err != nil {
-		c.logger.Error().Err(err).Ctx(ctx).Msgf("訪問 URL 錯誤: %v, URL: %s", err, url)
-		return nil, err
-	}
-
-	// 計算執行時間
-	elapsedTime := time.Since(startTime)
-
-	newsData.ElapsedTime = elapsedTime
-	newsData.ResponseSize = responseSize
-
-	c.logger.Info().Ctx(ctx).
-		Str("id", newsData.NewsID).
-		Str("title", newsData.Headline[:min(10, len(newsData.Headline))]).
-		Dur("elapsed_time", elapsedTime).
-		Int("response_size", responseSize).
-		Msg("News scraping completed")
-
 	return &newsData, nil
 }
 
-func (c *CtiNewsSpider) GetNewsList(ctx context.Context, newsIDList []string) ([]*entity.News, error) {
+func (c *CtiNewsSpider) GetNewsList(ctx context.Context, newsIDList []string) ([]*entity.News, map[string]error) {
 	// Trace
 	ctx, span := c.tracer.Start(ctx, "domain/spider/usecase/spider_ctinews/GetNewsList: Get News List")
 	defer func() {
@@ -159,17 +193,17 @@ func (c *CtiNewsSpider) GetNewsList(ctx context.Context, newsIDList []string) ([
 	}()
 
 	c.logger.Info().Ctx(ctx).Msg("GetNewsList: start")
-	newsDataList := make([]*entity.News, 0)
-
-	for _, newsID := range newsIDList {
-		newsData, err := c.GetNews(ctx, newsID)
-		if err != nil {
-			return nil, err
-		}
-		newsDataList = append(newsDataList, newsData)
+	newsDataList, errMap := fetchNewsList(ctx, newsIDList, c.concurrency, c.GetNews)
+
+	if len(errMap) > 0 {
+		c.logger.Warn().Ctx(ctx).
+			Uint("media_id", c.mediaID).
+			Int("success", len(newsDataList)).
+			Int("failed", len(errMap)).
+			Msg("GetNewsList: partial failure")
 	}
 
-	return newsDataList, nil
+	return newsDataList, errMap
 }
 
 func (c *CtiNewsSpider) GetNewsIdList(ctx context.Context) ([]string, error) {
@@ -182,22 +216,18 @@ func (c *CtiNewsSpider) GetNewsIdList(ctx context.Context) ([]string, error) {
 	}()
 
 	// 建立新的收集器
-	collector := colly.NewCollector()
+	collector, err := c.crawler.NewCollector(c.host)
+	if err != nil {
+		c.logger.Error().Err(err).Ctx(ctx).Msg("建立收集器錯誤")
+		return nil, err
+	}
 
 	// 儲存新聞ID列表
 	var newsIDs []string
 
-	// 設定請求頭
-	collector.OnRequest(func(r *colly.Request) {
-		r.Headers.Set(
-			"User-Agent",
-			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
-		)
-	})
-
 	// 處理錯誤
 	collector.OnError(func(r *colly.Response, err error) {
-		log.Printf("取得網站地圖錯誤: %v", err)
+		c.logger.Error().Err(err).Ctx(ctx).Msgf("取得網站地圖錯誤: %v", err)
 	})
 
 	// 處理 XML
@@ -215,11 +245,12 @@ func (c *CtiNewsSpider) GetNewsIdList(ctx context.Context) ([]string, error) {
 	})
 
 	// 開始抓取
-	err := collector.Visit(c.newsListPageURL)
+	err = c.crawler.Visit(ctx, collector, c.newsListPageURL)
 	if err != nil {
 		return nil, fmt.Errorf("訪問網站地圖錯誤: %v", err)
 	}
 
+	c.metrics.recordDiscovered(ctx, c.mediaID, len(newsIDs))
 	c.logger.Info().Msgf("中天找到 %d 篇新聞文章", len(newsIDs))
 
 	return newsIDs, nil
@@ -9,8 +9,10 @@ import (
 type Spider interface {
 	// 爬取新聞
 	GetNews(ctx context.Context, newsID string) (*entity.News, error)
-	// 爬取多個新聞
-	GetNewsList(ctx context.Context, newsIDList []string) ([]*entity.News, error)
+	// 爬取多個新聞, 回傳成功的新聞與失敗新聞ID對應的錯誤
+	GetNewsList(ctx context.Context, newsIDList []string) ([]*entity.News, map[string]error)
+	// 從原始 HTML 解析新聞, 不發出網路請求
+	ParseNews(ctx context.Context, newsID string, body []byte) (*entity.News, error)
 	// 爬取新聞ID列表
 	GetNewsIdList(ctx context.Context) ([]string, error)
 	// 爬取媒體ID
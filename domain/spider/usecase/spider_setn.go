@@ -1,17 +1,23 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 
 	"itmrchow/tw-media-analytics-service/domain/spider/entity"
+	"itmrchow/tw-media-analytics-service/domain/spider/repository"
+	"itmrchow/tw-media-analytics-service/domain/utils/crawler"
 )
 
 var _ Spider = &SetnSpider{}
@@ -23,21 +29,47 @@ var _ Spider = &SetnSpider{}
 type SetnSpider struct {
 	tracer          trace.Tracer
 	logger          *zerolog.Logger
+	crawler         *crawler.Factory
+	archiveRepo     repository.ArchiveRepository
+	quality         *QualityMonitor
+	metrics         *scrapeMetrics
+	host            string
+	concurrency     int
 	newsPageURL     string
 	newsListPageURL string
 	goquerySelector string
 	mediaID         uint
 }
 
-func NewSetnSpider(logger *zerolog.Logger, tracer trace.Tracer) *SetnSpider {
+func NewSetnSpider(
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	crawler *crawler.Factory,
+	archiveRepo repository.ArchiveRepository,
+	quality *QualityMonitor,
+	opts ...Option,
+) *SetnSpider {
+	o := newOptions("https://www.setn.com", opts...)
+
 	var spider = &SetnSpider{
 		tracer:          tracer,
 		logger:          logger,
-		newsPageURL:     "https://www.setn.com/News.aspx?NewsID=%d",
-		newsListPageURL: "https://www.setn.com/sitemapGoogleNews.xml",
+		crawler:         crawler,
+		archiveRepo:     archiveRepo,
+		quality:         quality,
+		host:            o.host(),
+		newsPageURL:     o.baseURL + "/News.aspx?NewsID=%s",
+		newsListPageURL: o.baseURL + "/sitemapGoogleNews.xml",
 		goquerySelector: "script[type='application/ld+json']",
 		mediaID:         2,
 	}
+	spider.concurrency = crawler.Concurrency(viper.GetInt("SPIDER_SETN_CONCURRENCY"))
+
+	metrics, err := newScrapeMetrics(otel.Meter("domain/spider"))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create spider metrics")
+	}
+	spider.metrics = metrics
 
 	return spider
 }
@@ -55,51 +87,79 @@ func (s *SetnSpider) GetNews(ctx context.Context, newsID string) (*entity.News,
 
 	s.logger.Info().Ctx(ctx).Uint("media_id", s.mediaID).Msg("GetNews: start")
 
-	// 建立新的收集器
-	c := colly.NewCollector()
-
-	// 設定請求頭
-	c.OnRequest(func(r *colly.Request) {
-		r.Headers.Set(
-			"User-Agent",
-			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
-		)
-	})
-
 	// 記錄開始時間
 	startTime := time.Now()
 
-	// 儲存回應大小
-	var responseSize int
+	// 開始抓取
+	url := fmt.Sprintf(s.newsPageURL, newsID)
+	resp, err := fetchPage(ctx, s.crawler, s.host, url)
+	if err != nil {
+		s.logger.Error().Err(err).Ctx(ctx).Msgf("Error visiting URL: %v , URL: %s", err, url)
+		return nil, err
+	}
+	resp.MediaID = s.mediaID
+	resp.NewsID = newsID
+
+	// 封存原始回應, 失敗不影響爬取
+	if err = s.archiveRepo.SaveResponse(ctx, resp); err != nil {
+		s.logger.Error().Err(err).Ctx(ctx).Msgf("Error archiving response: %v", err)
+	}
+
+	newsData, err := s.ParseNews(ctx, newsID, resp.Body)
+	if err != nil {
+		_ = s.quality.Record(ctx, s.mediaID, nil)
+		s.logger.Error().Err(err).Ctx(ctx).Msgf("Error parsing HTML: %v", err)
+		return nil, err
+	}
+
+	// 檢查抽取品質, 缺少標題或內文則不回傳
+	if err = s.quality.Record(ctx, s.mediaID, newsData); err != nil {
+		s.logger.Error().Err(err).Ctx(ctx).Str("news_id", newsID).Msg("Extraction quality check failed")
+		return nil, err
+	}
+
+	// 計算執行時間
+	elapsedTime := time.Since(startTime)
+
+	newsData.ElapsedTime = elapsedTime
+	s.metrics.recordScraped(ctx, s.mediaID, newsData)
+
+	s.logger.Info().Ctx(ctx).
+		Str("id", newsData.NewsID).
+		Str("title", newsData.Headline[:min(10, len(newsData.Headline))]).
+		Dur("elapsed_time", elapsedTime).
+		Int("response_size", newsData.ResponseSize).
+		Msg("News scraping completed , send news save event")
+
+	return newsData, nil
+}
+
+// ParseNews 從文章 HTML 解析新聞, 不發出任何網路請求.
+func (s *SetnSpider) ParseNews(ctx context.Context, newsID string, body []byte) (*entity.News, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html: %w", err)
+	}
 
 	// 儲存新聞資料
 	var newsData entity.News
 	newsData.NewsID = newsID
-
-	// 處理回應
-	c.OnResponse(func(r *colly.Response) {
-		responseSize = len(r.Body)
-	})
-
-	// 處理錯誤
-	c.OnError(func(r *colly.Response, err error) {
-		s.logger.Error().Err(err).Ctx(ctx).Msgf("Error: %v", err)
-	})
+	newsData.ResponseSize = len(body)
 
 	// 處理 HTML - 獲取新聞內容
-	c.OnHTML("div#ckuse div#Content1", func(e *colly.HTMLElement) {
+	doc.Find("div#ckuse div#Content1").Each(func(_ int, sel *goquery.Selection) {
 		// 獲取新聞內容
-		newsData.NewsContext = strings.TrimSpace(e.Text)
+		newsData.NewsContext = strings.TrimSpace(sel.Text())
 	})
 
 	// 處理 HTML
-	c.OnHTML("script[type='application/ld+json']", func(e *colly.HTMLElement) {
+	doc.Find(s.goquerySelector).Each(func(_ int, sel *goquery.Selection) {
 		// 先解析 type
 		var typeCheck struct {
 			Type string `json:"@type"`
 		}
 
-		err := json.Unmarshal([]byte(e.Text), &typeCheck)
+		err := json.Unmarshal([]byte(sel.Text()), &typeCheck)
 		if err != nil {
 			s.logger.Error().Err(err).Ctx(ctx).Msgf("Error parsing JSON: %v", err)
 			return
@@ -110,38 +170,17 @@ func (s *SetnSpider) GetNews(ctx context.Context, newsID string) (*entity.News,
 		}
 
 		// 解析 JSON
-		err = json.Unmarshal([]byte(e.Text), &newsData)
+		err = json.Unmarshal([]byte(sel.Text()), &newsData)
 		if err != nil {
 			s.logger.Error().Err(err).Ctx(ctx).Msgf("Error parsing JSON: %v", err)
 			return
 		}
 	})
 
-	// 開始抓取
-	url := fmt.Sprintf("https://www.setn.com/News.aspx?NewsID=%s", newsID)
-	err := c.Visit(url)
-	if err != nil {
-		s.logger.Error().Err(err).Ctx(ctx).Msgf("Error visiting URL: %v , URL: %s", err, url)
-		return nil, err
-	}
-
-	// 計算執行時間
-	elapsedTime := time.Since(startTime)
-
-	newsData.ElapsedTime = elapsedTime
-	newsData.ResponseSize = responseSize
-
-	s.logger.Info().Ctx(ctx).
-		Str("id", newsData.NewsID).
-		Str("title", newsData.Headline[:min(10, len(newsData.Headline))]).
-		Dur("elapsed_time", elapsedTime).
-		Int("response_size", responseSize).
-		Msg("News scraping completed , send news save event")
-
 	return &newsData, nil
 }
 
-func (s *SetnSpider) GetNewsList(ctx context.Context, newsIDList []string) ([]*entity.News, error) {
+func (s *SetnSpider) GetNewsList(ctx context.Context, newsIDList []string) ([]*entity.News, map[string]error) {
 	// Trace
 	ctx, span := s.tracer.Start(ctx, "domain/spider/usecase/spider_setn/GetNewsList: Get News List")
 	defer func() {
@@ -151,17 +190,17 @@ func (s *SetnSpider) GetNewsList(ctx context.Context, newsIDList []string) ([]*e
 
 	s.logger.Info().Ctx(ctx).Uint("media_id", s.mediaID).Msg("GetNewsList: start")
 
-	newsDataList := make([]*entity.News, 0)
+	newsDataList, errMap := fetchNewsList(ctx, newsIDList, s.concurrency, s.GetNews)
 
-	for _, newsID := range newsIDList {
-		newsData, err := s.GetNews(ctx, newsID)
-		if err != nil {
-			return nil, err
-		}
-		newsDataList = append(newsDataList, newsData)
+	if len(errMap) > 0 {
+		s.logger.Warn().Ctx(ctx).
+			Uint("media_id", s.mediaID).
+			Int("success", len(newsDataList)).
+			Int("failed", len(errMap)).
+			Msg("GetNewsList: partial failure")
 	}
 
-	return newsDataList, nil
+	return newsDataList, errMap
 }
 
 func (s *SetnSpider) GetNewsIdList(ctx context.Context) ([]string, error) {
@@ -173,19 +212,15 @@ func (s *SetnSpider) GetNewsIdList(ctx context.Context) ([]string, error) {
 		s.logger.Info().Ctx(ctx).Uint("media_id", s.mediaID).Msg("GetNewsIdList: end")
 	}()
 	// 建立新的收集器
-	c := colly.NewCollector()
+	c, err := s.crawler.NewCollector(s.host)
+	if err != nil {
+		s.logger.Error().Err(err).Ctx(ctx).Msgf("Error creating collector: %v", err)
+		return nil, err
+	}
 
 	// 儲存新聞ID列表
 	var newsIDs []string
 
-	// 設定請求頭
-	c.OnRequest(func(r *colly.Request) {
-		r.Headers.Set(
-			"User-Agent",
-			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
-		)
-	})
-
 	// 處理錯誤
 	c.OnError(func(r *colly.Response, err error) {
 		s.logger.Error().Err(err).Ctx(ctx).Msgf("Error fetching sitemap: %v", err)
@@ -207,12 +242,13 @@ func (s *SetnSpider) GetNewsIdList(ctx context.Context) ([]string, error) {
 	})
 
 	// 開始抓取
-	err := c.Visit("https://www.setn.com/sitemapGoogleNews.xml")
+	err = s.crawler.Visit(ctx, c, s.newsListPageURL)
 	if err != nil {
 		s.logger.Error().Err(err).Ctx(ctx).Msgf("error visiting sitemap: %v", err)
 		return nil, err
 	}
 
+	s.metrics.recordDiscovered(ctx, s.mediaID, len(newsIDs))
 	s.logger.Info().Ctx(ctx).Msgf("三立找到 %d 篇新聞文章", len(newsIDs))
 
 	return newsIDs, nil
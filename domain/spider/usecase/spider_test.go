@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCtiNewsSpider_GetNewsIdList(t *testing.T) {
+	cti, _ := newTestSpiders(t)
+
+	newsIDs, err := cti.GetNewsIdList(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1B2c3D4e5", "NoAuth0001"}, newsIDs)
+}
+
+func TestCtiNewsSpider_GetNews(t *testing.T) {
+	cti, _ := newTestSpiders(t)
+
+	tests := []struct {
+		name   string
+		newsID string
+	}{
+		{name: "多個 ld+json, NewsArticle 非第一個", newsID: "a1B2c3D4e5"},
+		{name: "無作者且含格式錯誤的 ld+json", newsID: "NoAuth0001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			news, err := cti.GetNews(context.Background(), tt.newsID)
+			require.NoError(t, err)
+			assertGolden(t, "ctinews", news)
+		})
+	}
+}
+
+func TestCtiNewsSpider_GetNews_NotFound(t *testing.T) {
+	cti, _ := newTestSpiders(t)
+
+	_, err := cti.GetNews(context.Background(), "not-exist")
+	require.Error(t, err)
+}
+
+func TestSetnSpider_GetNewsIdList(t *testing.T) {
+	_, setn := newTestSpiders(t)
+
+	newsIDs, err := setn.GetNewsIdList(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1600001", "1600002"}, newsIDs)
+}
+
+func TestSetnSpider_GetNews(t *testing.T) {
+	_, setn := newTestSpiders(t)
+
+	tests := []struct {
+		name   string
+		newsID string
+	}{
+		{name: "內容取自 div#ckuse div#Content1", newsID: "1600001"},
+		{name: "無作者", newsID: "1600002"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			news, err := setn.GetNews(context.Background(), tt.newsID)
+			require.NoError(t, err)
+			assertGolden(t, "setn", news)
+		})
+	}
+}
+
+func TestSetnSpider_GetNewsList_PartialFailure(t *testing.T) {
+	_, setn := newTestSpiders(t)
+
+	newsList, errMap := setn.GetNewsList(context.Background(), []string{"1600001", "9999999", "1600002"})
+	require.Len(t, newsList, 2)
+	assert.Equal(t, "1600001", newsList[0].NewsID)
+	assert.Equal(t, "1600002", newsList[1].NewsID)
+	assert.Len(t, errMap, 1)
+	assert.Error(t, errMap["9999999"])
+}
+
+func TestSetnSpider_GetNews_ExtractionFailed(t *testing.T) {
+	_, setn := newTestSpiders(t)
+
+	_, err := setn.GetNews(context.Background(), "1600003")
+	require.ErrorIs(t, err, ErrExtractionFailed)
+}
@@ -0,0 +1,165 @@
+package story
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// minMedia 新事件至少需有幾家媒體報導, 只有單一媒體的新聞留待下次分群.
+const minMedia = 2
+
+// Config 分群設定.
+type Config struct {
+	Window     time.Duration // 與最相似新聞的發布時間差上限
+	Lookback   time.Duration // 每次分群涵蓋最近多久發布的新聞
+	Similarity float64       // 加入事件的最低相似度 (0~1)
+}
+
+// NewConfig 讀取 STORY_WINDOW, STORY_LOOKBACK 與 STORY_SIMILARITY, 未設定時使用預設值.
+func NewConfig() Config {
+	cfg := Config{
+		Window:     viper.GetDuration("STORY_WINDOW"),
+		Lookback:   viper.GetDuration("STORY_LOOKBACK"),
+		Similarity: viper.GetFloat64("STORY_SIMILARITY"),
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 24 * time.Hour
+	}
+	if cfg.Lookback <= 0 {
+		cfg.Lookback = 72 * time.Hour
+	}
+	// 至少涵蓋一個 Window, 否則剛發布的新聞找不到較早的報導
+	cfg.Lookback = max(cfg.Lookback, cfg.Window)
+	if cfg.Similarity <= 0 {
+		cfg.Similarity = 0.3
+	}
+	return cfg
+}
+
+// Article 待分群的新聞.
+type Article struct {
+	NewsID      string
+	MediaID     uint
+	Title       string
+	Content     string
+	PublishedAt time.Time
+	StoryID     uint // 已歸入的事件, 0 為尚未分群
+}
+
+// Member 事件中的新聞.
+type Member struct {
+	Article    *Article
+	Similarity float64 // 加入時與最相似新聞的相似度, 事件的第一篇為 1
+	New        bool    // 本次分群加入
+}
+
+// Cluster 分群結果, StoryID 為 0 時為新事件.
+type Cluster struct {
+	StoryID uint
+	Members []Member
+}
+
+// NewMembers 本次分群加入的新聞.
+func (c Cluster) NewMembers() []Member {
+	var members []Member
+	for _, m := range c.Members {
+		if m.New {
+			members = append(members, m)
+		}
+	}
+	return members
+}
+
+func (c Cluster) mediaCount() int {
+	media := map[uint]bool{}
+	for _, m := range c.Members {
+		media[m.Article.MediaID] = true
+	}
+	return len(media)
+}
+
+type placed struct {
+	article  *Article
+	features features
+	cluster  *Cluster
+}
+
+// Group 依發布時間依序將尚未分群的新聞加入最相似新聞所在的事件,
+// 相似度未達門檻或發布時間差超過 Window 時自成一群.
+// 回傳有新加入新聞的既有事件, 以及至少有兩家媒體報導的新事件.
+func Group(articles []*Article, cfg Config) []*Cluster {
+	sorted := slices.Clone(articles)
+	slices.SortStableFunc(sorted, func(a, b *Article) int {
+		if c := a.PublishedAt.Compare(b.PublishedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.NewsID, b.NewsID)
+	})
+
+	var clusters []*Cluster
+	var done []placed
+
+	// 既有事件
+	byStory := map[uint]*Cluster{}
+	for _, a := range sorted {
+		if a.StoryID == 0 {
+			continue
+		}
+		c, ok := byStory[a.StoryID]
+		if !ok {
+			c = &Cluster{StoryID: a.StoryID}
+			byStory[a.StoryID] = c
+			clusters = append(clusters, c)
+		}
+		c.Members = append(c.Members, Member{Article: a, Similarity: 1})
+		done = append(done, placed{article: a, features: newFeatures(a.Title, a.Content), cluster: c})
+	}
+
+	for _, a := range sorted {
+		if a.StoryID != 0 {
+			continue
+		}
+
+		f := newFeatures(a.Title, a.Content)
+		var best *Cluster
+		bestScore := 0.0
+		for _, p := range done {
+			if absDuration(a.PublishedAt.Sub(p.article.PublishedAt)) > cfg.Window {
+				continue
+			}
+			if score := f.similarity(p.features); score > bestScore {
+				best, bestScore = p.cluster, score
+			}
+		}
+
+		if best == nil || bestScore < cfg.Similarity {
+			best = &Cluster{}
+			clusters = append(clusters, best)
+			bestScore = 1
+		}
+		best.Members = append(best.Members, Member{Article: a, Similarity: bestScore, New: true})
+		done = append(done, placed{article: a, features: f, cluster: best})
+	}
+
+	var result []*Cluster
+	for _, c := range clusters {
+		if c.StoryID != 0 && len(c.NewMembers()) == 0 {
+			continue
+		}
+		if c.StoryID == 0 && c.mediaCount() < minMedia {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
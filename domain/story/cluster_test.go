@@ -0,0 +1,86 @@
+package story
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testConfig = Config{Window: 24 * time.Hour, Lookback: 72 * time.Hour, Similarity: 0.3}
+	baseTime   = time.Date(2025, 7, 24, 8, 0, 0, 0, time.UTC)
+)
+
+func newsIDs(members []Member) []string {
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.Article.NewsID)
+	}
+	return ids
+}
+
+func TestSimilarity(t *testing.T) {
+	a := newFeatures("凱米颱風今晚登陸 北部風雨增強", "")
+	b := newFeatures("颱風凱米登陸在即！北部風雨轉強", "")
+	c := newFeatures("台積電第二季營收創新高", "")
+
+	assert.Greater(t, a.similarity(b), 0.3)
+	assert.Less(t, a.similarity(c), 0.1)
+	assert.InDelta(t, 1.0, a.similarity(a), 0.0001)
+}
+
+func TestGroup(t *testing.T) {
+	typhoon := "中央氣象署表示, 凱米颱風預計今晚在宜蘭登陸, 北部及東北部風雨明顯增強, 民眾應做好防颱準備."
+	articles := []*Article{
+		{NewsID: "a1", MediaID: 1, Title: "凱米颱風今晚登陸 北部風雨增強", Content: typhoon, PublishedAt: baseTime},
+		{NewsID: "b1", MediaID: 2, Title: "颱風凱米登陸在即！北部風雨轉強", Content: typhoon, PublishedAt: baseTime.Add(40 * time.Minute)},
+		// 同一媒體的後續報導
+		{NewsID: "a2", MediaID: 1, Title: "凱米颱風登陸 宜蘭風雨增強", Content: typhoon, PublishedAt: baseTime.Add(3 * time.Hour)},
+		// 只有一家媒體報導
+		{NewsID: "a3", MediaID: 1, Title: "台積電第二季營收創新高", Content: "台積電今日公布第二季財報.", PublishedAt: baseTime},
+		// 超過時間窗口
+		{NewsID: "b2", MediaID: 2, Title: "凱米颱風今晚登陸 北部風雨增強", Content: typhoon, PublishedAt: baseTime.Add(72 * time.Hour)},
+	}
+
+	clusters := Group(articles, testConfig)
+	require.Len(t, clusters, 1)
+
+	c := clusters[0]
+	assert.Zero(t, c.StoryID)
+	assert.Equal(t, []string{"a1", "b1", "a2"}, newsIDs(c.Members))
+	assert.Equal(t, 1.0, c.Members[0].Similarity)
+	assert.Greater(t, c.Members[1].Similarity, testConfig.Similarity)
+	assert.Len(t, c.NewMembers(), 3)
+}
+
+func TestGroup_ExistingStory(t *testing.T) {
+	content := "立法院今日三讀通過預算案, 朝野立委在議場內發生推擠."
+	articles := []*Article{
+		{NewsID: "a1", MediaID: 1, Title: "立法院三讀通過預算案 朝野推擠", Content: content, PublishedAt: baseTime, StoryID: 7},
+		{NewsID: "b1", MediaID: 2, Title: "預算案三讀通過 立法院朝野爆推擠", Content: content, PublishedAt: baseTime.Add(time.Hour), StoryID: 7},
+		// 與既有事件無關且只有一家媒體的既有事件不回傳
+		{NewsID: "a2", MediaID: 1, Title: "台積電第二季營收創新高", PublishedAt: baseTime, StoryID: 8},
+		{NewsID: "c1", MediaID: 3, Title: "立法院三讀預算案 議場推擠", Content: content, PublishedAt: baseTime.Add(2 * time.Hour)},
+	}
+
+	clusters := Group(articles, testConfig)
+	require.Len(t, clusters, 1)
+
+	c := clusters[0]
+	assert.Equal(t, uint(7), c.StoryID)
+	assert.Equal(t, []string{"a1", "b1", "c1"}, newsIDs(c.Members))
+	assert.Equal(t, []string{"c1"}, newsIDs(c.NewMembers()))
+}
+
+func TestGroup_NoContent(t *testing.T) {
+	articles := []*Article{
+		{NewsID: "a1", MediaID: 1, Title: "凱米颱風今晚登陸 北部風雨增強", Content: "颱風消息", PublishedAt: baseTime},
+		{NewsID: "b1", MediaID: 2, Title: "凱米颱風今晚登陸 北部風雨增強", PublishedAt: baseTime},
+	}
+
+	clusters := Group(articles, testConfig)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, 1.0, clusters[0].Members[1].Similarity)
+}
@@ -0,0 +1,72 @@
+package story
+
+import (
+	"unicode"
+)
+
+const (
+	leadRunes   = 200 // 內容只比對開頭 (導言), 避免長文的背景段落拉低相似度
+	titleWeight = 0.6
+)
+
+type features struct {
+	title map[string]struct{}
+	lead  map[string]struct{}
+}
+
+func newFeatures(title string, content string) features {
+	runes := []rune(content)
+	if len(runes) > leadRunes {
+		runes = runes[:leadRunes]
+	}
+	return features{title: bigrams(title), lead: bigrams(string(runes))}
+}
+
+// similarity 標題與導言相似度的加權平均, 任一方沒有內容時只比對標題.
+func (f features) similarity(other features) float64 {
+	title := dice(f.title, other.title)
+	if len(f.lead) == 0 || len(other.lead) == 0 {
+		return title
+	}
+	return titleWeight*title + (1-titleWeight)*dice(f.lead, other.lead)
+}
+
+// bigrams 以相鄰兩字切分, 中文不需斷詞; 標點與空白視為斷點, 單獨一字的片段保留原字.
+func bigrams(s string) map[string]struct{} {
+	set := map[string]struct{}{}
+	var segment []rune
+	flush := func() {
+		if len(segment) == 1 {
+			set[string(segment)] = struct{}{}
+		}
+		for i := 0; i+1 < len(segment); i++ {
+			set[string(segment[i:i+2])] = struct{}{}
+		}
+		segment = segment[:0]
+	}
+
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			segment = append(segment, unicode.ToLower(r))
+			continue
+		}
+		flush()
+	}
+	flush()
+
+	return set
+}
+
+// dice Sørensen–Dice 係數, 對長度不同的標題較 Jaccard 寬鬆.
+func dice(a map[string]struct{}, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if _, ok := b[k]; ok {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
@@ -2,27 +2,224 @@ package ai
 
 import (
 	"context"
+	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 
 	"itmrchow/tw-media-analytics-service/domain/ai"
+	"itmrchow/tw-media-analytics-service/domain/ai/repository"
+	"itmrchow/tw-media-analytics-service/domain/category"
+	"itmrchow/tw-media-analytics-service/domain/utils/health"
 )
 
-func NewGemini(ctx context.Context, logger *zerolog.Logger, tracer trace.Tracer) ai.AiModel {
+const defaultModel = "gemini-2.0-flash-lite-001"
+
+// NewAiModel 建立具預算控管的 AI 模型.
+// AI_BUDGET_ACTION=fallback 時預算用完改用 AI_FALLBACK_MODEL (必填) 較便宜的模型, 否則暫停分析.
+// AI_CACHE_ENABLED 時各模型的分析結果會快取於 DB, 快取位於預算檢查外層, 命中時不受預算限制.
+// 超過 AI_CHUNK_MAX_TOKENS 的長文會切段分析.
+// AI_ENSEMBLE_MODELS 多於一個或 AI_ENSEMBLE_RUNS 大於 1 時, 以多次評分合併為最終結果.
+func NewAiModel(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	tracer trace.Tracer,
+	usage *ai.UsageService,
+	cacheRepo repository.AnalysisCacheRepository,
+) ai.AiModel {
 	// Trace
-	ctx, span := tracer.Start(ctx, "utils/ai/NewGemini: New Gemini")
-	logger.Info().Ctx(ctx).Msg("NewGemini: start")
+	ctx, span := tracer.Start(ctx, "utils/ai/NewAiModel: New AI Model")
+	logger.Info().Ctx(ctx).Msg("NewAiModel: start")
 	defer func() {
-		logger.Info().Ctx(ctx).Msg("NewGemini: end")
+		logger.Info().Ctx(ctx).Msg("NewAiModel: end")
 		span.End()
 	}()
 
+	modelName := viper.GetString("AI_MODEL")
+	if modelName == "" {
+		modelName = defaultModel
+	}
+
+	// prompt 版本為快取 key 的一部分
+	prompt, err := ai.ReadPrompt()
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Msg("InitAIModel: failed to read prompt")
+	}
+	promptVersion := ai.PromptVersion(prompt)
+
+	withCache := func(model ai.AiModel, modelName string) ai.AiModel {
+		if !viper.GetBool("AI_CACHE_ENABLED") {
+			return model
+		}
+		return ai.NewCachedModel(logger, otel.Meter("domain/ai"), cacheRepo, model, modelName, promptVersion)
+	}
+
 	// New Gemini
-	model, err := ai.NewGemini(ctx, logger)
+	ensembleCfg, err := ai.NewEnsembleConfig()
 	if err != nil {
-		logger.Fatal().Err(err).Ctx(ctx).Msg("InitAIModel: failed to create Gemini model")
+		logger.Fatal().Err(err).Ctx(ctx).Msg("InitAIModel: invalid ensemble config")
+	}
+	if len(ensembleCfg.Models) == 0 {
+		ensembleCfg.Models = []string{modelName}
+	}
+
+	var primary ai.AiModel
+	if ensembleCfg.Enabled() {
+		// 快取整體合併結果, 避免同一模型多次評分時都命中同一筆快取
+		members := make([]ai.EnsembleMember, 0, len(ensembleCfg.Models))
+		for _, name := range ensembleCfg.Models {
+			member, err := ai.NewGemini(ctx, logger, name, usage, ai.WithBudget(usage))
+			if err != nil {
+				logger.Fatal().Err(err).Ctx(ctx).Str("model", name).Msg("InitAIModel: failed to create ensemble Gemini model")
+			}
+			members = append(members, ai.EnsembleMember{Name: name, Model: member})
+		}
+		ensemble := ai.NewEnsembleModel(logger, members, ensembleCfg.Runs, ensembleCfg.Aggregate)
+		primary = withCache(ensemble, ensembleCfg.Name())
+	} else {
+		gemini, err := ai.NewGemini(ctx, logger, ensembleCfg.Models[0], usage, ai.WithBudget(usage))
+		if err != nil {
+			logger.Fatal().Err(err).Ctx(ctx).Msg("InitAIModel: failed to create Gemini model")
+		}
+		primary = withCache(gemini, ensembleCfg.Models[0])
+	}
+
+	var fallback ai.AiModel
+	fallbackName := viper.GetString("AI_FALLBACK_MODEL")
+	if viper.GetString("AI_BUDGET_ACTION") == "fallback" {
+		if fallbackName == "" {
+			logger.Fatal().Ctx(ctx).Msg("InitAIModel: AI_BUDGET_ACTION=fallback requires AI_FALLBACK_MODEL")
+		}
+		fallbackModel, err := ai.NewGemini(ctx, logger, fallbackName, usage)
+		if err != nil {
+			logger.Fatal().Err(err).Ctx(ctx).Msg("InitAIModel: failed to create fallback Gemini model")
+		}
+		fallback = withCache(fallbackModel, fallbackName)
 	}
 
-	return model
+	budgeted := ai.NewBudgetedModel(logger, primary, fallback)
+
+	return ai.NewChunkedModel(logger, budgeted, ai.NewChunkConfig())
+}
+
+// HealthCheck AI 服務健康檢查, 結果快取 1 分鐘避免頻繁呼叫.
+func HealthCheck(model ai.AiModel) health.Check {
+	return health.Check{
+		Name:     "ai",
+		CacheTTL: time.Minute,
+		Timeout:  5 * time.Second,
+		Func: func(ctx context.Context) (any, error) {
+			return nil, model.Ping(ctx)
+		},
+	}
+}
+
+// NewCategoryLLM CATEGORY_LLM_ENABLED 時以 AI_MODEL 分類版面無法判斷的新聞, 否則回傳 nil 只使用規則分類.
+// 與品質分析共用預算, 預算用完時無法判斷的新聞歸類為 other.
+func NewCategoryLLM(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	usage *ai.UsageService,
+) category.LLM {
+	if !viper.GetBool("CATEGORY_LLM_ENABLED") {
+		return nil
+	}
+
+	modelName := viper.GetString("AI_MODEL")
+	if modelName == "" {
+		modelName = defaultModel
+	}
+
+	gemini, err := ai.NewGemini(ctx, logger, modelName, usage, ai.WithBudget(usage))
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Msg("NewCategoryLLM: failed to create Gemini model")
+	}
+
+	return gemini
+}
+
+// NewFramingModel AI_FRAMING_ENABLED 時以 AI_MODEL 分析新聞的政治立場與框架, 否則回傳 nil 不分析.
+func NewFramingModel(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	usage *ai.UsageService,
+) ai.FramingModel {
+	if !viper.GetBool("AI_FRAMING_ENABLED") {
+		return nil
+	}
+
+	modelName := viper.GetString("AI_MODEL")
+	if modelName == "" {
+		modelName = defaultModel
+	}
+
+	gemini, err := ai.NewGemini(ctx, logger, modelName, usage, ai.WithBudget(usage))
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Msg("NewFramingModel: failed to create Gemini model")
+	}
+
+	return gemini
+}
+
+// NewSummaryModel AI_SUMMARY_ENABLED 時以 AI_MODEL 產生新聞摘要與事實陳述, 否則回傳 nil 不產生.
+func NewSummaryModel(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	usage *ai.UsageService,
+) ai.SummaryModel {
+	if !viper.GetBool("AI_SUMMARY_ENABLED") {
+		return nil
+	}
+
+	modelName := viper.GetString("AI_MODEL")
+	if modelName == "" {
+		modelName = defaultModel
+	}
+
+	gemini, err := ai.NewGemini(ctx, logger, modelName, usage, ai.WithBudget(usage))
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Msg("NewSummaryModel: failed to create Gemini model")
+	}
+
+	return gemini
+}
+
+const (
+	defaultEmbeddingModel      = "text-embedding-004"
+	defaultEmbeddingDimensions = 256
+)
+
+// NewEmbeddingModel 依 EMBEDDING_PROVIDER 建立新聞向量模型: gemini 使用 EMBEDDING_MODEL,
+// local 使用不需呼叫 AI 的 EMBEDDING_DIMENSIONS 維 bigram 向量, 未設定時回傳 nil 不計算.
+// gemini 的使用量列入 AI 預算, 預算用完時暫停計算.
+func NewEmbeddingModel(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	usage *ai.UsageService,
+) ai.EmbeddingModel {
+	switch provider := viper.GetString("EMBEDDING_PROVIDER"); provider {
+	case "":
+		return nil
+	case "gemini":
+		modelName := viper.GetString("EMBEDDING_MODEL")
+		if modelName == "" {
+			modelName = defaultEmbeddingModel
+		}
+		model, err := ai.NewGeminiEmbedding(ctx, logger, modelName, usage, usage)
+		if err != nil {
+			logger.Fatal().Err(err).Ctx(ctx).Msg("NewEmbeddingModel: failed to create Gemini embedding model")
+		}
+		return model
+	case "local":
+		dimensions := viper.GetInt("EMBEDDING_DIMENSIONS")
+		if dimensions <= 0 {
+			dimensions = defaultEmbeddingDimensions
+		}
+		return ai.NewLocalEmbedding(dimensions)
+	default:
+		logger.Fatal().Ctx(ctx).Str("provider", provider).Msg("NewEmbeddingModel: unknown EMBEDDING_PROVIDER")
+		return nil
+	}
 }
@@ -0,0 +1,85 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+)
+
+// Alert 告警內容.
+type Alert struct {
+	Text   string         `json:"text"` // 相容 Slack / Discord incoming webhook
+	Source string         `json:"source"`
+	Status string         `json:"status"`
+	Fields map[string]any `json:"fields,omitempty"`
+	Time   time.Time      `json:"time"`
+}
+
+// Notifier 告警發送介面.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+var _ Notifier = &WebhookNotifier{}
+
+// WebhookNotifier 以 HTTP POST JSON 發送告警, 未設定 URL 時只記錄 log.
+type WebhookNotifier struct {
+	logger *zerolog.Logger
+	client *http.Client
+	url    string
+}
+
+// NewWebhookNotifier 以 ALERT_WEBHOOK_URL 建立 webhook 告警.
+func NewWebhookNotifier(logger *zerolog.Logger) *WebhookNotifier {
+	return NewWebhookNotifierWithURL(logger, viper.GetString("ALERT_WEBHOOK_URL"))
+}
+
+// NewWebhookNotifierWithURL 以指定 URL 建立 webhook 告警.
+func NewWebhookNotifierWithURL(logger *zerolog.Logger, url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		logger: logger,
+		client: &http.Client{Timeout: 5 * time.Second},
+		url:    url,
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.logger.Warn().Ctx(ctx).
+		Str("source", alert.Source).
+		Str("status", alert.Status).
+		Interface("fields", alert.Fields).
+		Msg(alert.Text)
+
+	if n.url == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("failed to send alert: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var got Alert
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	logger := zerolog.Nop()
+	n := NewWebhookNotifierWithURL(&logger, srv.URL)
+
+	err := n.Notify(context.Background(), Alert{Text: "spider degraded", Source: "spider", Status: "degraded"})
+	require.NoError(t, err)
+	assert.Equal(t, "spider degraded", got.Text)
+	assert.Equal(t, "degraded", got.Status)
+}
+
+func TestWebhookNotifier_Notify_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	logger := zerolog.Nop()
+	n := NewWebhookNotifierWithURL(&logger, srv.URL)
+
+	err := n.Notify(context.Background(), Alert{Text: "spider degraded"})
+	require.ErrorContains(t, err, "status 500")
+}
+
+func TestWebhookNotifier_Notify_NoURL(t *testing.T) {
+	logger := zerolog.Nop()
+	n := NewWebhookNotifierWithURL(&logger, "")
+
+	require.NoError(t, n.Notify(context.Background(), Alert{Text: "spider degraded"}))
+}
@@ -0,0 +1,66 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrNotFound 物件不存在.
+var ErrNotFound = errors.New("blob not found")
+
+// Store 物件儲存介面, key 以 "/" 分隔.
+type Store interface {
+	// Put 寫入物件, 已存在則覆蓋
+	Put(ctx context.Context, key string, data []byte) error
+	// Get 讀取物件, 不存在時回傳 ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Exists 檢查物件是否存在
+	Exists(ctx context.Context, key string) (bool, error)
+	// List 列出 prefix 下所有物件 key, 依字典序排序
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// NewStore 依 ARCHIVE_DRIVER 初始化物件儲存 (local, s3).
+func NewStore(ctx context.Context, logger *zerolog.Logger, tracer trace.Tracer) Store {
+	// Trace
+	ctx, span := tracer.Start(ctx, "domain/utils/blob/NewStore: New Blob Store")
+	logger.Info().Ctx(ctx).Msg("NewStore: start")
+	defer func() {
+		logger.Info().Ctx(ctx).Msg("NewStore: end")
+		span.End()
+	}()
+
+	store, err := newStore(ctx)
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Msg("NewStore: failed to create blob store")
+	}
+
+	return store
+}
+
+func newStore(ctx context.Context) (Store, error) {
+	switch driver := viper.GetString("ARCHIVE_DRIVER"); driver {
+	case "", "local":
+		dir := viper.GetString("ARCHIVE_LOCAL_DIR")
+		if dir == "" {
+			dir = "./archive"
+		}
+		return NewLocalStore(dir)
+	case "s3":
+		return NewS3Store(ctx, S3Config{
+			Endpoint:  viper.GetString("ARCHIVE_S3_ENDPOINT"),
+			Region:    viper.GetString("ARCHIVE_S3_REGION"),
+			Bucket:    viper.GetString("ARCHIVE_S3_BUCKET"),
+			AccessKey: viper.GetString("ARCHIVE_S3_ACCESS_KEY"),
+			SecretKey: viper.GetString("ARCHIVE_S3_SECRET_KEY"),
+			UseSSL:    viper.GetBool("ARCHIVE_S3_USE_SSL"),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported archive driver: %s", driver)
+	}
+}
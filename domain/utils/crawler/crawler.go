@@ -0,0 +1,256 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultUserAgent      = "tw-media-analytics-service/0.0.1 (+https://github.com/itmrchow/tw-media-analytics-service)"
+	defaultParallelism    = 2
+	defaultDelay          = 1 * time.Second
+	defaultRandomDelay    = 500 * time.Millisecond
+	defaultMaxRetries     = 3
+	defaultRetryBaseDelay = 1 * time.Second
+	defaultRetryMaxDelay  = 30 * time.Second
+
+	ctxKeyStatusCode = "crawler_status_code"
+	ctxKeyRetryAfter = "crawler_retry_after"
+)
+
+// Config 爬蟲設定.
+type Config struct {
+	UserAgent       string        // 對外表明身分的 User-Agent
+	IgnoreRobotsTxt bool          // 是否忽略 robots.txt
+	Parallelism     int           // 每個 host 的最大同時請求數
+	Delay           time.Duration // 每個 host 的請求間隔
+	RandomDelay     time.Duration // 額外的隨機請求間隔
+	MaxRetries      int           // 429/5xx 最大重試次數
+	RetryBaseDelay  time.Duration // 重試 backoff 基準時間
+	RetryMaxDelay   time.Duration // 重試 backoff 上限, Retry-After 超過此值即放棄
+}
+
+// NewConfig 從 viper 讀取爬蟲設定, 未設定則使用預設值.
+func NewConfig() Config {
+	cfg := Config{
+		UserAgent:       viper.GetString("CRAWLER_USER_AGENT"),
+		IgnoreRobotsTxt: viper.GetBool("CRAWLER_IGNORE_ROBOTS_TXT"),
+		Parallelism:     viper.GetInt("CRAWLER_PARALLELISM"),
+		Delay:           viper.GetDuration("CRAWLER_DELAY"),
+		RandomDelay:     viper.GetDuration("CRAWLER_RANDOM_DELAY"),
+		MaxRetries:      viper.GetInt("CRAWLER_MAX_RETRIES"),
+		RetryBaseDelay:  viper.GetDuration("CRAWLER_RETRY_BASE_DELAY"),
+		RetryMaxDelay:   viper.GetDuration("CRAWLER_RETRY_MAX_DELAY"),
+	}
+
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = defaultUserAgent
+	}
+	if cfg.Parallelism <= 0 {
+		cfg.Parallelism = defaultParallelism
+	}
+	if !viper.IsSet("CRAWLER_DELAY") {
+		cfg.Delay = defaultDelay
+	}
+	if !viper.IsSet("CRAWLER_RANDOM_DELAY") {
+		cfg.RandomDelay = defaultRandomDelay
+	}
+	if !viper.IsSet("CRAWLER_MAX_RETRIES") {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.RetryBaseDelay <= 0 {
+		cfg.RetryBaseDelay = defaultRetryBaseDelay
+	}
+	if cfg.RetryMaxDelay <= 0 {
+		cfg.RetryMaxDelay = defaultRetryMaxDelay
+	}
+
+	return cfg
+}
+
+// Factory 所有爬蟲共用的 collector 工廠.
+// 由同一個 base collector clone 出來的 collector 共用 http backend,
+// 因此 per-host 的 LimitRule 與 robots.txt 快取會在所有爬蟲間共享.
+type Factory struct {
+	logger *zerolog.Logger
+	cfg    Config
+	base   *colly.Collector
+
+	mu    sync.Mutex
+	hosts map[string]struct{}
+}
+
+// NewFactory 以 viper 設定建立 collector 工廠.
+func NewFactory(logger *zerolog.Logger) *Factory {
+	return NewFactoryWithConfig(logger, NewConfig())
+}
+
+// NewFactoryWithConfig 以指定設定建立 collector 工廠.
+func NewFactoryWithConfig(logger *zerolog.Logger, cfg Config) *Factory {
+	base := colly.NewCollector(colly.UserAgent(cfg.UserAgent))
+	base.IgnoreRobotsTxt = cfg.IgnoreRobotsTxt
+	base.AllowURLRevisit = true // 重試時需要重新請求相同 URL
+
+	return &Factory{
+		logger: logger,
+		cfg:    cfg,
+		base:   base,
+		hosts:  make(map[string]struct{}),
+	}
+}
+
+// NewCollector 建立新的 collector, 並確保 host 已套用 LimitRule.
+func (f *Factory) NewCollector(host string) (*colly.Collector, error) {
+	if err := f.registerHost(host); err != nil {
+		return nil, err
+	}
+
+	c := f.base.Clone()
+
+	// 記錄錯誤回應的狀態碼與 Retry-After, 供 Visit 判斷是否重試
+	c.OnError(func(r *colly.Response, _ error) {
+		if r == nil || r.Ctx == nil {
+			return
+		}
+		r.Ctx.Put(ctxKeyStatusCode, strconv.Itoa(r.StatusCode))
+		if r.Headers != nil {
+			r.Ctx.Put(ctxKeyRetryAfter, r.Headers.Get("Retry-After"))
+		}
+	})
+
+	return c, nil
+}
+
+// Concurrency 將爬蟲設定的併發數限制在 host 的 Parallelism 內, 0 代表使用 Parallelism.
+func (f *Factory) Concurrency(n int) int {
+	if n <= 0 || n > f.cfg.Parallelism {
+		return f.cfg.Parallelism
+	}
+	return n
+}
+
+// Visit 以 GET 請求 URL, 遇到 429/5xx 或連線錯誤時以 jittered backoff 重試.
+// 若回應帶有 Retry-After 則優先採用.
+func (f *Factory) Visit(ctx context.Context, c *colly.Collector, rawURL string) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		reqCtx := colly.NewContext()
+		err = c.Request(http.MethodGet, rawURL, nil, reqCtx, nil)
+		if err == nil {
+			return nil
+		}
+
+		statusCode, _ := strconv.Atoi(reqCtx.Get(ctxKeyStatusCode))
+		if !isRetryable(err, statusCode) || attempt >= f.cfg.MaxRetries {
+			return err
+		}
+
+		wait, ok := f.retryDelay(attempt, reqCtx.Get(ctxKeyRetryAfter))
+		if !ok {
+			return fmt.Errorf("retry after exceeds max delay %s: %w", f.cfg.RetryMaxDelay, err)
+		}
+
+		f.logger.Warn().Ctx(ctx).Err(err).
+			Str("url", rawURL).
+			Int("status_code", statusCode).
+			Int("attempt", attempt+1).
+			Dur("wait", wait).
+			Msg("crawler retry")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// registerHost 為尚未註冊的 host 加上 LimitRule.
+func (f *Factory) registerHost(host string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.hosts[host]; ok {
+		return nil
+	}
+
+	err := f.base.Limit(&colly.LimitRule{
+		DomainGlob:  host,
+		Parallelism: f.cfg.Parallelism,
+		Delay:       f.cfg.Delay,
+		RandomDelay: f.cfg.RandomDelay,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set limit rule for %s: %w", host, err)
+	}
+
+	f.hosts[host] = struct{}{}
+	return nil
+}
+
+// retryDelay 計算第 attempt 次重試前的等待時間.
+// 回傳 false 代表 Retry-After 要求的等待時間超過上限.
+func (f *Factory) retryDelay(attempt int, retryAfter string) (time.Duration, bool) {
+	if d, ok := parseRetryAfter(retryAfter, time.Now()); ok {
+		if d > f.cfg.RetryMaxDelay {
+			return 0, false
+		}
+		return d, true
+	}
+
+	backoff := f.cfg.RetryBaseDelay << attempt
+	if backoff <= 0 || backoff > f.cfg.RetryMaxDelay {
+		backoff = f.cfg.RetryMaxDelay
+	}
+
+	// full jitter
+	return rand.N(backoff) + 1, true
+}
+
+// isRetryable 判斷錯誤是否值得重試.
+func isRetryable(err error, statusCode int) bool {
+	var urlErr *url.Error
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return true
+	case statusCode >= http.StatusInternalServerError:
+		return true
+	case statusCode == 0 && errors.As(err, &urlErr):
+		return true // 連線錯誤
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter 解析 Retry-After, 支援秒數與 HTTP-date 格式.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+
+	return 0, false
+}
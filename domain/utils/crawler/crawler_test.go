@@ -0,0 +1,170 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFactory(maxRetries int) *Factory {
+	logger := zerolog.Nop()
+	return NewFactoryWithConfig(&logger, Config{
+		UserAgent:      "test-agent",
+		Parallelism:    1,
+		MaxRetries:     maxRetries,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  50 * time.Millisecond,
+	})
+}
+
+func TestFactory_Visit_RetryOnTooManyRequests(t *testing.T) {
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "test-agent", r.Header.Get("User-Agent"))
+		if count.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("<html><body><h1>ok</h1></body></html>"))
+	}))
+	defer srv.Close()
+
+	f := newTestFactory(3)
+	u, _ := url.Parse(srv.URL)
+	c, err := f.NewCollector(u.Host)
+	require.NoError(t, err)
+
+	var text string
+	c.OnHTML("h1", func(e *colly.HTMLElement) {
+		text = e.Text
+	})
+
+	err = f.Visit(context.Background(), c, srv.URL+"/news")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", text)
+	assert.Equal(t, int32(3), count.Load())
+}
+
+func TestFactory_Visit_GiveUpAfterMaxRetries(t *testing.T) {
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		count.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	f := newTestFactory(2)
+	u, _ := url.Parse(srv.URL)
+	c, err := f.NewCollector(u.Host)
+	require.NoError(t, err)
+
+	err = f.Visit(context.Background(), c, srv.URL+"/news")
+	require.Error(t, err)
+	assert.Equal(t, int32(3), count.Load())
+}
+
+func TestFactory_Visit_NoRetryOnNotFound(t *testing.T) {
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		count.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	f := newTestFactory(3)
+	u, _ := url.Parse(srv.URL)
+	c, err := f.NewCollector(u.Host)
+	require.NoError(t, err)
+
+	err = f.Visit(context.Background(), c, srv.URL+"/news")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), count.Load())
+}
+
+func TestFactory_Visit_RetryAfterTooLong(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	f := newTestFactory(3)
+	u, _ := url.Parse(srv.URL)
+	c, err := f.NewCollector(u.Host)
+	require.NoError(t, err)
+
+	err = f.Visit(context.Background(), c, srv.URL+"/news")
+	require.ErrorContains(t, err, "retry after exceeds max delay")
+}
+
+func TestFactory_Visit_RobotsTxtDisallow(t *testing.T) {
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+			return
+		}
+		count.Add(1)
+	}))
+	defer srv.Close()
+
+	f := newTestFactory(3)
+	u, _ := url.Parse(srv.URL)
+	c, err := f.NewCollector(u.Host)
+	require.NoError(t, err)
+
+	err = f.Visit(context.Background(), c, srv.URL+"/private/news")
+	require.ErrorIs(t, err, colly.ErrRobotsTxtBlocked)
+	assert.Equal(t, int32(0), count.Load())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "空值", value: "", wantOK: false},
+		{name: "秒數", value: "120", want: 2 * time.Minute, wantOK: true},
+		{name: "HTTP-date", value: now.Add(30 * time.Second).Format(http.TimeFormat), want: 30 * time.Second, wantOK: true},
+		{name: "過去時間", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, wantOK: true},
+		{name: "格式錯誤", value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,30 +3,93 @@ package db
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 	"go.opentelemetry.io/otel/trace"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/plugin/opentelemetry/tracing"
 
-	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	"itmrchow/tw-media-analytics-service/domain/utils/health"
 )
 
-// NewMysqlDB 初始化 mysql db.
-func NewMysqlDB(ctx context.Context, logger *zerolog.Logger, tracer trace.Tracer) *gorm.DB {
+const (
+	DriverMysql    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSqlite   = "sqlite"
+)
+
+const defaultSqlitePath = "./database.db"
+
+// NewDatabase 依 DB_DRIVER 初始化 db. MySQL 與 PostgreSQL 的 schema 需已由 migrate 子命令更新至最新版本,
+// SQLite 用於開發, 啟動時自動套用 migration.
+func NewDatabase(ctx context.Context, logger *zerolog.Logger, tracer trace.Tracer) *gorm.DB {
 	// Trace
-	ctx, span := tracer.Start(ctx, "domain/utils/db/NewMysqlDB: New MysqlDB")
-	logger.Info().Ctx(ctx).Msg("InitMysqlDb: start")
+	ctx, span := tracer.Start(ctx, "domain/utils/db/NewDatabase: New Database")
+	logger.Info().Ctx(ctx).Msg("NewDatabase: start")
 	defer func() {
 		span.End()
-		logger.Info().Ctx(ctx).Msg("InitMysqlDb end")
+		logger.Info().Ctx(ctx).Msg("NewDatabase end")
 	}()
 
-	dns := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s%s",
+	driver := viper.GetString("DB_DRIVER")
+	db, err := Open(ctx, driver)
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Str("driver", driver).Msg("failed to init db")
+	}
+
+	migrator, err := NewMigrator(logger, db)
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Msg("failed to load migrations")
+	}
+	if db.Dialector.Name() == DriverSqlite {
+		if _, err = migrator.Up(ctx); err != nil {
+			logger.Fatal().Err(err).Ctx(ctx).Msg("failed to migrate sqlite db")
+		}
+		return db
+	}
+
+	// 只檢查 schema 版本, 由 migrate 子命令套用 migration
+	if err = migrator.Check(ctx); err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Msg("schema is not up to date, run `migrate up` first")
+	}
+
+	return db
+}
+
+// Open 依 driver 連線 db, 不檢查 schema 版本.
+func Open(ctx context.Context, driver string) (*gorm.DB, error) {
+	dialector, err := Dialector(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDB(ctx, dialector, &gorm.Config{})
+}
+
+// Dialector 依 driver 與對應的設定建立 gorm dialector, 空字串為 mysql.
+func Dialector(driver string) (gorm.Dialector, error) {
+	switch driver {
+	case "", DriverMysql:
+		return mysql.Open(MysqlDSN()), nil
+	case DriverPostgres:
+		return postgres.Open(PostgresDSN()), nil
+	case DriverSqlite:
+		return sqlite.Open(SqlitePath()), nil
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q, use mysql, postgres or sqlite", driver)
+	}
+}
+
+// MysqlDSN 以 MYSQL_* 設定組成 DSN.
+func MysqlDSN() string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s%s",
 		viper.GetString("MYSQL_DB_ACCOUNT"),
 		viper.GetString("MYSQL_DB_PASSWORD"),
 		viper.GetString("MYSQL_DB_HOST"),
@@ -34,34 +97,38 @@ func NewMysqlDB(ctx context.Context, logger *zerolog.Logger, tracer trace.Tracer
 		viper.GetString("MYSQL_DB_NAME"),
 		viper.GetString("MYSQL_URL_SUFFIX"),
 	)
+}
 
-	db, err := NewDB(ctx, mysql.Open(dns), &gorm.Config{})
-	if err != nil {
-		logger.Fatal().Err(err).Ctx(ctx).Msg("failed to init mysql db")
+// PostgresDSN 以 POSTGRES_* 設定組成 URL 格式的 DSN, 帳號密碼會跳脫特殊字元.
+func PostgresDSN() string {
+	port := viper.GetString("POSTGRES_DB_PORT")
+	if port == "" {
+		port = "5432"
+	}
+	sslMode := viper.GetString("POSTGRES_SSL_MODE")
+	if sslMode == "" {
+		sslMode = "disable"
 	}
 
-	return db
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(viper.GetString("POSTGRES_DB_ACCOUNT"), viper.GetString("POSTGRES_DB_PASSWORD")),
+		Host:     net.JoinHostPort(viper.GetString("POSTGRES_DB_HOST"), port),
+		Path:     "/" + viper.GetString("POSTGRES_DB_NAME"),
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}
+	return dsn.String()
 }
 
-// NewSqliteDB 初始化 sqlLite db.
-func NewSqliteDB(ctx context.Context, logger *zerolog.Logger, tracer trace.Tracer) *gorm.DB {
-	// Trace
-	ctx, span := tracer.Start(ctx, "domain/utils/db/NewSqliteDB: New SqliteDB")
-	logger.Info().Ctx(ctx).Msg("InitSqliteDb: start")
-	defer func() {
-		span.End()
-		logger.Info().Ctx(ctx).Msg("InitSqliteDb end")
-	}()
-
-	db, err := NewDB(ctx, sqlite.Open("./database.db"), &gorm.Config{}) // TODO: CTX
-	if err != nil {
-		logger.Fatal().Err(err).Ctx(ctx).Msg("failed to init sqlite db")
+// SqlitePath SQLITE_DB_PATH, 預設為 ./database.db.
+func SqlitePath() string {
+	if path := viper.GetString("SQLITE_DB_PATH"); path != "" {
+		return path
 	}
-
-	return db
+	return defaultSqlitePath
 }
 
-// NewDB 初始化 db.
+// NewDB 初始化 db, 不建立或修改 schema.
 func NewDB(ctx context.Context, dialector gorm.Dialector, opts ...gorm.Option) (*gorm.DB, error) {
 
 	db, err := gorm.Open(dialector, opts...)
@@ -86,18 +153,6 @@ func NewDB(ctx context.Context, dialector gorm.Dialector, opts ...gorm.Option) (
 	sqlDB.SetConnMaxLifetime(time.Minute * 30)
 	sqlDB.SetConnMaxIdleTime(15 * time.Minute)
 
-	// Auto migrate all entities
-	err = db.AutoMigrate(
-		&entity.Media{},
-		&entity.Author{},
-		&entity.News{},
-		&entity.Analysis{},
-		&entity.AnalysisMetric{},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to auto migrate: %w", err)
-	}
-
 	return db, nil
 }
 
@@ -126,3 +181,28 @@ func PingDB(ctx context.Context, logger *zerolog.Logger, tracer trace.Tracer, db
 	logger.Info().Ctx(ctx).Msg("db pinged")
 	return nil
 }
+
+// HealthCheck 資料庫連線健康檢查.
+func HealthCheck(db *gorm.DB) health.Check {
+	return health.Check{
+		Name:      "db",
+		Readiness: true,
+		Func: func(ctx context.Context) (any, error) {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return nil, err
+			}
+
+			if err = sqlDB.PingContext(ctx); err != nil {
+				return nil, err
+			}
+
+			stats := sqlDB.Stats()
+			return map[string]int{
+				"openConnections": stats.OpenConnections,
+				"inUse":           stats.InUse,
+				"idle":            stats.Idle,
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,432 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+//go:embed migrations
+var migrationFS embed.FS
+
+const (
+	migrationLockName    = "schema_migrations"
+	migrationLockTimeout = 60 // 秒
+)
+
+var (
+	// ErrSchemaOutdated 有尚未套用的 migration.
+	ErrSchemaOutdated = errors.New("schema is outdated")
+	// ErrSchemaDirty migration 執行失敗, 需修正後以 force 標記版本.
+	ErrSchemaDirty = errors.New("schema is dirty")
+	// ErrMigrationLocked 其他 replica 正在執行 migration.
+	ErrMigrationLocked = errors.New("migration is locked by another process")
+)
+
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration 版本化的 schema 變更, 檔名格式為 {version}_{name}.up.sql 與 {version}_{name}.down.sql.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration 已套用的 migration, Dirty 代表執行到一半失敗.
+type SchemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	Dirty     bool      `gorm:"not null;default:false"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus migration 的套用狀態.
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	Applied   bool
+	Dirty     bool
+	AppliedAt time.Time
+}
+
+// LoadMigrations 讀取 migrations/{dialect} 下的 migration, 依版本排序.
+func LoadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for %s: %w", dialect, err)
+	}
+
+	migrationMap := make(map[uint]*Migration)
+	for _, e := range entries {
+		matches := migrationFileRegexp.FindStringSubmatch(e.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", e.Name())
+		}
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version: %s", e.Name())
+		}
+
+		content, err := fs.ReadFile(migrationFS, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := migrationMap[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: matches[2]}
+			migrationMap[uint(version)] = m
+		}
+		if m.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has different names: %s, %s", version, m.Name, matches[2])
+		}
+		if matches[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(migrationMap))
+	for _, m := range migrationMap {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s requires both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return int(a.Version) - int(b.Version) })
+
+	return migrations, nil
+}
+
+// Migrator 套用與回復 migration, 已套用的版本記錄於 schema_migrations.
+type Migrator struct {
+	logger     *zerolog.Logger
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator 依 db 的 dialect 建立 Migrator.
+func NewMigrator(logger *zerolog.Logger, db *gorm.DB) (*Migrator, error) {
+	migrations, err := LoadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		logger:     logger,
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Up 依序套用所有尚未套用的 migration, 回傳套用的數量.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	applied, err := m.applied(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	if err = checkDirty(applied); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		m.logger.Info().Ctx(ctx).Uint("version", migration.Version).Str("name", migration.Name).Msg("migrate up")
+		err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// MySQL 的 DDL 會隱含 commit, 失敗時保留 dirty 記錄
+			record := SchemaMigration{Version: migration.Version, Name: migration.Name, Dirty: true, AppliedAt: time.Now()}
+			if err := tx.Create(&record).Error; err != nil {
+				return err
+			}
+			if err := execStatements(tx, migration.Up); err != nil {
+				return err
+			}
+			return tx.Model(&record).Update("dirty", false).Error
+		})
+		if err != nil {
+			return count, fmt.Errorf("failed to migrate up %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Down 依序回復最新的 steps 個已套用的 migration, 回傳回復的數量.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	applied, err := m.applied(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	if err = checkDirty(applied); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range slices.Backward(m.migrations) {
+		if count >= steps {
+			break
+		}
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		m.logger.Info().Ctx(ctx).Uint("version", migration.Version).Str("name", migration.Name).Msg("migrate down")
+		err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			record := SchemaMigration{Version: migration.Version}
+			if err := tx.Model(&record).Update("dirty", true).Error; err != nil {
+				return err
+			}
+			if err := execStatements(tx, migration.Down); err != nil {
+				return err
+			}
+			return tx.Delete(&record).Error
+		})
+		if err != nil {
+			return count, fmt.Errorf("failed to migrate down %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Force 不執行 SQL, 直接將 version 以前的 migration 標記為已套用, 之後的標記為未套用.
+// 用於修正 dirty 的版本, 或讓既有 (AutoMigrate 建立) 的資料庫採用 migration.
+func (m *Migrator) Force(ctx context.Context, version uint) error {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err = m.applied(ctx, true); err != nil {
+		return err
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("version > ?", version).Delete(&SchemaMigration{}).Error; err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			record := SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+			err := tx.Where(SchemaMigration{Version: migration.Version}).
+				Assign(map[string]any{"dirty": false}).
+				FirstOrCreate(&record).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Status 列出各 migration 的套用狀態, 包含資料庫中有記錄但程式不認得的版本.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	statusList := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.Dirty = record.Dirty
+			status.AppliedAt = record.AppliedAt
+			delete(applied, migration.Version)
+		}
+		statusList = append(statusList, status)
+	}
+	for _, record := range applied {
+		statusList = append(statusList, MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			Dirty:     record.Dirty,
+			AppliedAt: record.AppliedAt,
+		})
+	}
+	slices.SortFunc(statusList, func(a, b MigrationStatus) int { return int(a.Version) - int(b.Version) })
+
+	return statusList, nil
+}
+
+// Check 確認所有 migration 皆已套用且沒有 dirty 的版本, 不修改資料庫.
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.applied(ctx, false)
+	if err != nil {
+		return err
+	}
+	if err = checkDirty(applied); err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			return fmt.Errorf("%w: migration %d_%s is not applied", ErrSchemaOutdated, migration.Version, migration.Name)
+		}
+		delete(applied, migration.Version)
+	}
+	for version := range applied {
+		// 新版本已先執行 migration, 舊版本仍在滾動更新
+		m.logger.Warn().Ctx(ctx).Uint("version", version).Msg("schema has migration unknown to this build")
+	}
+
+	return nil
+}
+
+// applied 讀取已套用的版本, create 為 true 時建立 schema_migrations.
+func (m *Migrator) applied(ctx context.Context, create bool) (map[uint]SchemaMigration, error) {
+	tx := m.db.WithContext(ctx)
+	if !tx.Migrator().HasTable(&SchemaMigration{}) {
+		if !create {
+			return map[uint]SchemaMigration{}, nil
+		}
+		if err := tx.Migrator().CreateTable(&SchemaMigration{}); err != nil {
+			return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+	}
+
+	var records []SchemaMigration
+	if err := tx.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to find schema migrations: %w", err)
+	}
+
+	applied := make(map[uint]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// lock 取得 migration 鎖, 避免多個 replica 同時執行. SQLite 為單一檔案, 由資料庫本身的寫入鎖保護.
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	dialect := m.db.Dialector.Name()
+	if dialect != "mysql" && dialect != "postgres" {
+		return func() {}, nil
+	}
+
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// GET_LOCK 與 advisory lock 屬於連線, 需固定使用同一個連線取得與釋放
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlockSQL := "SELECT RELEASE_LOCK(?)"
+	locked := false
+	if dialect == "mysql" {
+		var result sql.NullInt64
+		err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrationLockName, migrationLockTimeout).Scan(&result)
+		locked = result.Int64 == 1
+	} else {
+		// pg_advisory_lock 沒有逾時, 以 pg_try_advisory_lock 重試
+		unlockSQL = "SELECT pg_advisory_unlock(hashtext($1))"
+		deadline := time.Now().Add(migrationLockTimeout * time.Second)
+		for err == nil && !locked && time.Now().Before(deadline) {
+			if err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", migrationLockName).
+				Scan(&locked); err != nil || locked {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to get migration lock: %w", err)
+	}
+	if !locked {
+		_ = conn.Close()
+		return nil, ErrMigrationLocked
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), unlockSQL, migrationLockName); err != nil {
+			m.logger.Warn().Err(err).Msg("failed to release migration lock")
+		}
+		_ = conn.Close()
+	}, nil
+}
+
+func checkDirty(applied map[uint]SchemaMigration) error {
+	for _, record := range applied {
+		if record.Dirty {
+			return fmt.Errorf("%w: migration %d_%s failed, fix it and run `migrate force`",
+				ErrSchemaDirty, record.Version, record.Name)
+		}
+	}
+	return nil
+}
+
+// execStatements 逐一執行 SQL, 每個 statement 以行尾的分號結束, 忽略 -- 開頭的註解.
+func execStatements(tx *gorm.DB, sql string) error {
+	var statement strings.Builder
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		statement.WriteString(line)
+		statement.WriteString("\n")
+		if !strings.HasSuffix(trimmed, ";") {
+			continue
+		}
+
+		if err := tx.Exec(statement.String()).Error; err != nil {
+			return err
+		}
+		statement.Reset()
+	}
+
+	if strings.TrimSpace(statement.String()) != "" {
+		return errors.New("last statement must end with a semicolon")
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package db
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	aiEntity "itmrchow/tw-media-analytics-service/domain/ai/entity"
+	"itmrchow/tw-media-analytics-service/domain/news/entity"
+	reviewEntity "itmrchow/tw-media-analytics-service/domain/review/entity"
+)
+
+func TestMigratorSuite(t *testing.T) {
+	suite.Run(t, new(MigratorTestSuite))
+}
+
+type MigratorTestSuite struct {
+	suite.Suite
+	logger   zerolog.Logger
+	db       *gorm.DB
+	migrator *Migrator
+}
+
+func (s *MigratorTestSuite) SetupTest() {
+	s.logger = zerolog.New(os.Stdout).Level(zerolog.InfoLevel)
+	s.db = s.openDB("migrate.db")
+
+	var err error
+	s.migrator, err = NewMigrator(&s.logger, s.db)
+	s.Require().NoError(err)
+}
+
+func (s *MigratorTestSuite) openDB(name string) *gorm.DB {
+	db, err := NewDB(context.Background(), sqlite.Open(filepath.Join(s.T().TempDir(), name)), &gorm.Config{})
+	s.Require().NoError(err)
+	return db
+}
+
+func (s *MigratorTestSuite) TestLoadMigrations() {
+	for _, dialect := range []string{"mysql", "postgres", "sqlite"} {
+		migrations, err := LoadMigrations(dialect)
+		s.Require().NoError(err)
+		s.Require().NotEmpty(migrations)
+		s.Equal(uint(1), migrations[0].Version)
+		s.Equal("init_schema", migrations[0].Name)
+	}
+
+	_, err := LoadMigrations("oracle")
+	s.Error(err)
+}
+
+func (s *MigratorTestSuite) TestUpAndDown() {
+	ctx := context.Background()
+
+	// 尚未套用
+	s.ErrorIs(s.migrator.Check(ctx), ErrSchemaOutdated)
+
+	count, err := s.migrator.Up(ctx)
+	s.Require().NoError(err)
+	s.Equal(len(s.migrator.migrations), count)
+	s.NoError(s.migrator.Check(ctx))
+	s.True(s.db.Migrator().HasTable(&entity.News{}))
+
+	// 重複執行不會再套用
+	count, err = s.migrator.Up(ctx)
+	s.Require().NoError(err)
+	s.Zero(count)
+
+	statusList, err := s.migrator.Status(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(statusList, len(s.migrator.migrations))
+	s.True(statusList[0].Applied)
+	s.False(statusList[0].Dirty)
+
+	count, err = s.migrator.Down(ctx, len(s.migrator.migrations))
+	s.Require().NoError(err)
+	s.Equal(len(s.migrator.migrations), count)
+	s.False(s.db.Migrator().HasTable(&entity.News{}))
+	s.ErrorIs(s.migrator.Check(ctx), ErrSchemaOutdated)
+}
+
+func (s *MigratorTestSuite) TestFailedMigration() {
+	ctx := context.Background()
+
+	s.migrator.migrations = append(s.migrator.migrations, Migration{
+		Version: 9999,
+		Name:    "broken",
+		Up:      "CREATE TABLE broken (id integer);\nSELECT * FROM not_exists;\n",
+		Down:    "DROP TABLE broken;\n",
+	})
+
+	_, err := s.migrator.Up(ctx)
+	s.Require().Error(err)
+
+	// SQLite 的 DDL 可 rollback, 失敗的版本不會留下記錄
+	s.False(s.db.Migrator().HasTable("broken"))
+	s.ErrorIs(s.migrator.Check(ctx), ErrSchemaOutdated)
+
+	// dirty 的版本需以 force 修正
+	s.Require().NoError(s.db.Create(&SchemaMigration{Version: 9999, Name: "broken", Dirty: true}).Error)
+	s.ErrorIs(s.migrator.Check(ctx), ErrSchemaDirty)
+	_, err = s.migrator.Up(ctx)
+	s.ErrorIs(err, ErrSchemaDirty)
+
+	s.Require().NoError(s.migrator.Force(ctx, 1))
+	statusList, err := s.migrator.Status(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(statusList, len(s.migrator.migrations))
+	s.True(statusList[0].Applied)
+	s.False(statusList[len(statusList)-1].Applied)
+}
+
+func (s *MigratorTestSuite) TestExecStatements() {
+	s.Require().NoError(execStatements(s.db, `
+-- comment
+CREATE TABLE t (
+    id integer
+);
+INSERT INTO t (id) VALUES (1);
+`))
+
+	var count int64
+	s.Require().NoError(s.db.Table("t").Count(&count).Error)
+	s.Equal(int64(1), count)
+
+	s.Error(execStatements(s.db, "INSERT INTO t (id) VALUES (2)"))
+}
+
+// TestMigrationsMatchEntities 修改 entity 時需新增對應的 migration.
+// 預設比對 SQLite, TEST_DB_DRIVER=postgres 時 (make test-postgres) 比對 PostgreSQL migration.
+func (s *MigratorTestSuite) TestMigrationsMatchEntities() {
+	ctx := context.Background()
+
+	migrated, expected := s.db, s.openDB("auto.db")
+	if os.Getenv("TEST_DB_DRIVER") == DriverPostgres {
+		migrated = s.openPostgresSchema("migrate_test_migrated")
+		expected = s.openPostgresSchema("migrate_test_auto")
+	}
+
+	migrator, err := NewMigrator(&s.logger, migrated)
+	s.Require().NoError(err)
+	_, err = migrator.Up(ctx)
+	s.Require().NoError(err)
+
+	models := []any{
+		&entity.Media{},
+		&entity.Author{},
+		&entity.News{},
+		&entity.Analysis{},
+		&entity.AnalysisMetric{},
+		&entity.AnalysisRun{},
+		&entity.NewsEntity{},
+		&entity.Story{},
+		&entity.StoryArticle{},
+		&entity.NewsSummary{},
+		&entity.NewsClaim{},
+		&entity.NewsEmbedding{},
+		&aiEntity.AiUsage{},
+		&aiEntity.AnalysisCache{},
+		&reviewEntity.MetricOverride{},
+		&reviewEntity.ReviewAudit{},
+		&reviewEntity.ReviewItem{},
+	}
+	s.Require().NoError(expected.AutoMigrate(models...))
+
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: expected}
+		s.Require().NoError(stmt.Parse(model))
+		table := stmt.Schema.Table
+
+		s.Require().True(migrated.Migrator().HasTable(table), "missing table %s", table)
+		s.Equal(s.columns(expected, table), s.columns(migrated, table), "columns of %s", table)
+		s.Equal(s.indexes(expected, table), s.indexes(migrated, table), "indexes of %s", table)
+	}
+}
+
+// openPostgresSchema 在 TEST_POSTGRES_DSN (URL 格式) 的資料庫建立空的 schema 並以其為預設 schema 連線,
+// 與其他 package 的測試資料表分開. pg_trgm 固定建立於 public, 供各 schema 的索引使用.
+func (s *MigratorTestSuite) openPostgresSchema(schema string) *gorm.DB {
+	ctx := context.Background()
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+
+	base, err := NewDB(ctx, postgres.Open(dsn), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(base.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm SCHEMA public").Error)
+	s.Require().NoError(base.Exec("DROP SCHEMA IF EXISTS " + schema + " CASCADE").Error)
+	s.Require().NoError(base.Exec("CREATE SCHEMA " + schema).Error)
+
+	u, err := url.Parse(dsn)
+	s.Require().NoError(err)
+	query := u.Query()
+	query.Set("search_path", schema+",public")
+	u.RawQuery = query.Encode()
+
+	db, err := NewDB(ctx, postgres.Open(u.String()), &gorm.Config{})
+	s.Require().NoError(err)
+
+	s.T().Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+		_ = base.Exec("DROP SCHEMA IF EXISTS " + schema + " CASCADE").Error
+		if sqlDB, err := base.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	return db
+}
+
+// migrationOnlyIndexes 只存在於 migration 的檢索索引, entity 無法描述.
+var migrationOnlyIndexes = map[string]bool{
+	"idx_news_title_trgm":   true,
+	"idx_news_content_trgm": true,
+}
+
+func (s *MigratorTestSuite) columns(db *gorm.DB, table string) map[string]string {
+	columnTypes, err := db.Migrator().ColumnTypes(table)
+	s.Require().NoError(err)
+
+	columns := make(map[string]string, len(columnTypes))
+	for _, c := range columnTypes {
+		nullable, _ := c.Nullable()
+		columns[c.Name()] = c.DatabaseTypeName()
+		// PostgreSQL migration 以 varchar 取代會補空白的 char
+		if columns[c.Name()] == "bpchar" {
+			columns[c.Name()] = "varchar"
+		}
+		if !nullable {
+			columns[c.Name()] += " NOT NULL"
+		}
+	}
+	return columns
+}
+
+func (s *MigratorTestSuite) indexes(db *gorm.DB, table string) map[string][]string {
+	indexList, err := db.Migrator().GetIndexes(table)
+	s.Require().NoError(err)
+
+	indexes := make(map[string][]string, len(indexList))
+	for _, idx := range indexList {
+		if migrationOnlyIndexes[idx.Name()] {
+			continue
+		}
+		indexes[idx.Name()] = idx.Columns()
+	}
+	return indexes
+}
@@ -0,0 +1,274 @@
+CREATE TABLE `media` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `name` varchar(255) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_media_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `authors` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `name` varchar(255) NOT NULL,
+    `media_id` bigint unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_authors_deleted_at` (`deleted_at`),
+    CONSTRAINT `fk_media_author_list` FOREIGN KEY (`media_id`) REFERENCES `media`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `news` (
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `news_id` char(36),
+    `media_id` bigint unsigned,
+    `title` varchar(255) NOT NULL,
+    `content` text NOT NULL,
+    `url` varchar(255) NOT NULL,
+    `author_id` bigint unsigned NOT NULL,
+    `category` varchar(255) NOT NULL DEFAULT '',
+    `published_at` datetime(3) NULL,
+    `category_key` varchar(64) NOT NULL DEFAULT '',
+    `category_source` varchar(32) NOT NULL DEFAULT '',
+    PRIMARY KEY (`news_id`,`media_id`),
+    INDEX `idx_news_deleted_at` (`deleted_at`),
+    INDEX `idx_news_category_key` (`category_key`),
+    CONSTRAINT `fk_authors_news_list` FOREIGN KEY (`author_id`) REFERENCES `authors`(`id`),
+    CONSTRAINT `fk_media_news_list` FOREIGN KEY (`media_id`) REFERENCES `media`(`id`),
+    CONSTRAINT `uni_news_url` UNIQUE (`url`),
+    FULLTEXT INDEX `idx_news_fulltext` (`title`,`content`) WITH PARSER ngram
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `analyses` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `news_id` char(36) NOT NULL,
+    `media_id` bigint unsigned NOT NULL,
+    `type` varchar(255) NOT NULL,
+    `score` decimal(10,2) NOT NULL,
+    `reason` text NOT NULL,
+    `injection_suspected` boolean NOT NULL DEFAULT false,
+    PRIMARY KEY (`id`),
+    INDEX `idx_analyses_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_news_media_type` (`news_id`,`media_id`,`type`),
+    CONSTRAINT `fk_news_analysis_list` FOREIGN KEY (`news_id`,`media_id`) REFERENCES `news`(`news_id`,`media_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `analysis_metrics` (
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `analysis_id` bigint unsigned NOT NULL,
+    `metric_key` varchar(255) NOT NULL,
+    `score` decimal(10,2) NOT NULL,
+    `reason` text NOT NULL,
+    `variance` decimal(10,2) NOT NULL DEFAULT '0',
+    PRIMARY KEY (`analysis_id`,`metric_key`),
+    INDEX `idx_analysis_metrics_deleted_at` (`deleted_at`),
+    INDEX `idx_analysis_metrics_analysis_id` (`analysis_id`),
+    INDEX `idx_analysis_metrics_metric_key` (`metric_key`),
+    CONSTRAINT `fk_analyses_analysis_metrics_list` FOREIGN KEY (`analysis_id`) REFERENCES `analyses`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `analysis_runs` (
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `id` bigint unsigned AUTO_INCREMENT,
+    `analysis_id` bigint unsigned NOT NULL,
+    `model` varchar(255) NOT NULL,
+    `run` bigint NOT NULL,
+    `score` decimal(10,2) NOT NULL,
+    `reason` text NOT NULL,
+    `metric_list` text NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_analysis_runs_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_analysis_run` (`analysis_id`,`model`,`run`),
+    CONSTRAINT `fk_analyses_run_list` FOREIGN KEY (`analysis_id`) REFERENCES `analyses`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `news_entities` (
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `id` bigint unsigned AUTO_INCREMENT,
+    `news_id` char(36) NOT NULL,
+    `media_id` bigint unsigned NOT NULL,
+    `entity_id` varchar(64) NOT NULL,
+    `entity_name` varchar(255) NOT NULL,
+    `entity_type` varchar(32) NOT NULL,
+    `mentions` bigint NOT NULL,
+    `in_title` boolean NOT NULL DEFAULT false,
+    `aliases` varchar(512) NOT NULL DEFAULT '',
+    PRIMARY KEY (`id`),
+    INDEX `idx_news_entities_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_news_entity` (`news_id`,`media_id`,`entity_id`),
+    INDEX `idx_news_entities_entity_id` (`entity_id`),
+    CONSTRAINT `fk_news_entities_news` FOREIGN KEY (`news_id`,`media_id`) REFERENCES `news`(`news_id`,`media_id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `stories` (
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `id` bigint unsigned AUTO_INCREMENT,
+    `title` varchar(255) NOT NULL,
+    `first_published_at` datetime(3) NOT NULL,
+    `last_published_at` datetime(3) NOT NULL,
+    `article_count` bigint NOT NULL,
+    `media_count` bigint NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_stories_deleted_at` (`deleted_at`),
+    INDEX `idx_stories_first_published_at` (`first_published_at`),
+    INDEX `idx_stories_media_count` (`media_count`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `story_articles` (
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `id` bigint unsigned AUTO_INCREMENT,
+    `story_id` bigint unsigned NOT NULL,
+    `news_id` char(36) NOT NULL,
+    `media_id` bigint unsigned NOT NULL,
+    `similarity` double NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_story_articles_deleted_at` (`deleted_at`),
+    INDEX `idx_story_articles_story_id` (`story_id`),
+    UNIQUE INDEX `idx_story_article_news` (`news_id`,`media_id`),
+    CONSTRAINT `fk_story_articles_news` FOREIGN KEY (`news_id`,`media_id`) REFERENCES `news`(`news_id`,`media_id`) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT `fk_stories_article_list` FOREIGN KEY (`story_id`) REFERENCES `stories`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `news_summaries` (
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `id` bigint unsigned AUTO_INCREMENT,
+    `news_id` char(36) NOT NULL,
+    `media_id` bigint unsigned NOT NULL,
+    `summary` text NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_news_summaries_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_news_summary` (`news_id`,`media_id`),
+    CONSTRAINT `fk_news_summaries_news` FOREIGN KEY (`news_id`,`media_id`) REFERENCES `news`(`news_id`,`media_id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `news_claims` (
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `id` bigint unsigned AUTO_INCREMENT,
+    `summary_id` bigint unsigned NOT NULL,
+    `news_id` char(36) NOT NULL,
+    `media_id` bigint unsigned NOT NULL,
+    `seq` bigint NOT NULL,
+    `claim` text NOT NULL,
+    `quote` text NOT NULL,
+    `quote_start` bigint,
+    `quote_end` bigint,
+    PRIMARY KEY (`id`),
+    INDEX `idx_news_claims_deleted_at` (`deleted_at`),
+    INDEX `idx_news_claims_summary_id` (`summary_id`),
+    INDEX `idx_news_claim` (`news_id`,`media_id`),
+    CONSTRAINT `fk_news_claims_news` FOREIGN KEY (`news_id`,`media_id`) REFERENCES `news`(`news_id`,`media_id`) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT `fk_news_summaries_claim_list` FOREIGN KEY (`summary_id`) REFERENCES `news_summaries`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `news_embeddings` (
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `id` bigint unsigned AUTO_INCREMENT,
+    `news_id` char(36) NOT NULL,
+    `media_id` bigint unsigned NOT NULL,
+    `model` varchar(64) NOT NULL,
+    `dimensions` bigint NOT NULL,
+    `vector` longblob NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_news_embeddings_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_news_embedding` (`news_id`,`media_id`,`model`),
+    CONSTRAINT `fk_news_embeddings_news` FOREIGN KEY (`news_id`,`media_id`) REFERENCES `news`(`news_id`,`media_id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `ai_usages` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `model` varchar(255) NOT NULL,
+    `prompt_version` varchar(64) NOT NULL,
+    `operation` varchar(64) NOT NULL,
+    `prompt_tokens` bigint NOT NULL,
+    `completion_tokens` bigint NOT NULL,
+    `total_tokens` bigint NOT NULL,
+    `cost` decimal(12,6) NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_ai_usages_model` (`model`),
+    INDEX `idx_ai_usages_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `analysis_caches` (
+    `key` char(64),
+    `model` varchar(255) NOT NULL,
+    `prompt_version` varchar(64) NOT NULL,
+    `result` text NOT NULL,
+    `hit_count` bigint NOT NULL DEFAULT 0,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `metric_overrides` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `analysis_id` bigint unsigned NOT NULL,
+    `metric_key` varchar(255) NOT NULL,
+    `score` decimal(10,2) NOT NULL,
+    `reason` text NOT NULL,
+    `reviewer` varchar(255) NOT NULL,
+    `reviewed_at` datetime(3) NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_override_analysis_metric` (`analysis_id`,`metric_key`),
+    INDEX `idx_metric_overrides_reviewed_at` (`reviewed_at`),
+    CONSTRAINT `fk_metric_overrides_analysis` FOREIGN KEY (`analysis_id`) REFERENCES `analyses`(`id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `review_audits` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `analysis_id` bigint unsigned NOT NULL,
+    `metric_key` varchar(255) NOT NULL DEFAULT '',
+    `action` varchar(32) NOT NULL,
+    `reviewer` varchar(255) NOT NULL,
+    `old_score` decimal(10,2),
+    `new_score` decimal(10,2),
+    `old_reason` text,
+    `new_reason` text,
+    `comment` text,
+    `created_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_review_audits_analysis_id` (`analysis_id`),
+    INDEX `idx_review_audits_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `review_items` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `analysis_id` bigint unsigned NOT NULL,
+    `reason` varchar(64) NOT NULL,
+    `status` varchar(32) NOT NULL,
+    `note` text,
+    `requested_by` varchar(255) NOT NULL DEFAULT '',
+    `resolved_by` varchar(255) NOT NULL DEFAULT '',
+    `resolved_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_review_items_status` (`status`),
+    INDEX `idx_review_items_created_at` (`created_at`),
+    UNIQUE INDEX `idx_review_analysis_reason` (`analysis_id`,`reason`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,275 @@
+-- 識別碼使用 varchar 而非 char, PostgreSQL 的 char 查詢結果會以空白補足長度
+
+CREATE TABLE "media" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(255) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_media_deleted_at" ON "media" ("deleted_at");
+
+CREATE TABLE "authors" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(255) NOT NULL,
+    "media_id" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_media_author_list" FOREIGN KEY ("media_id") REFERENCES "media"("id")
+);
+CREATE INDEX "idx_authors_deleted_at" ON "authors" ("deleted_at");
+
+CREATE TABLE "news" (
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "news_id" varchar(36),
+    "media_id" bigint,
+    "title" varchar(255) NOT NULL,
+    "content" text NOT NULL,
+    "url" varchar(255) NOT NULL,
+    "author_id" bigint NOT NULL,
+    "category" varchar(255) NOT NULL DEFAULT '',
+    "published_at" timestamptz,
+    "category_key" varchar(64) NOT NULL DEFAULT '',
+    "category_source" varchar(32) NOT NULL DEFAULT '',
+    PRIMARY KEY ("news_id","media_id"),
+    CONSTRAINT "fk_media_news_list" FOREIGN KEY ("media_id") REFERENCES "media"("id"),
+    CONSTRAINT "fk_authors_news_list" FOREIGN KEY ("author_id") REFERENCES "authors"("id"),
+    CONSTRAINT "uni_news_url" UNIQUE ("url")
+);
+CREATE INDEX "idx_news_category_key" ON "news" ("category_key");
+CREATE INDEX "idx_news_deleted_at" ON "news" ("deleted_at");
+
+CREATE TABLE "analyses" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "news_id" varchar(36) NOT NULL,
+    "media_id" bigint NOT NULL,
+    "type" varchar(255) NOT NULL,
+    "score" decimal(10,2) NOT NULL,
+    "reason" text NOT NULL,
+    "injection_suspected" boolean NOT NULL DEFAULT false,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_news_analysis_list" FOREIGN KEY ("news_id","media_id") REFERENCES "news"("news_id","media_id")
+);
+CREATE UNIQUE INDEX "idx_news_media_type" ON "analyses" ("news_id","media_id","type");
+CREATE INDEX "idx_analyses_deleted_at" ON "analyses" ("deleted_at");
+
+CREATE TABLE "analysis_metrics" (
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "analysis_id" bigint NOT NULL,
+    "metric_key" varchar(255) NOT NULL,
+    "score" decimal(10,2) NOT NULL,
+    "reason" text NOT NULL,
+    "variance" decimal(10,2) NOT NULL DEFAULT '0',
+    PRIMARY KEY ("analysis_id","metric_key"),
+    CONSTRAINT "fk_analyses_analysis_metrics_list" FOREIGN KEY ("analysis_id") REFERENCES "analyses"("id")
+);
+CREATE INDEX "idx_analysis_metrics_metric_key" ON "analysis_metrics" ("metric_key");
+CREATE INDEX "idx_analysis_metrics_analysis_id" ON "analysis_metrics" ("analysis_id");
+CREATE INDEX "idx_analysis_metrics_deleted_at" ON "analysis_metrics" ("deleted_at");
+
+CREATE TABLE "analysis_runs" (
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "id" bigserial,
+    "analysis_id" bigint NOT NULL,
+    "model" varchar(255) NOT NULL,
+    "run" bigint NOT NULL,
+    "score" decimal(10,2) NOT NULL,
+    "reason" text NOT NULL,
+    "metric_list" text NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_analyses_run_list" FOREIGN KEY ("analysis_id") REFERENCES "analyses"("id")
+);
+CREATE UNIQUE INDEX "idx_analysis_run" ON "analysis_runs" ("analysis_id","model","run");
+CREATE INDEX "idx_analysis_runs_deleted_at" ON "analysis_runs" ("deleted_at");
+
+CREATE TABLE "news_entities" (
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "id" bigserial,
+    "news_id" varchar(36) NOT NULL,
+    "media_id" bigint NOT NULL,
+    "entity_id" varchar(64) NOT NULL,
+    "entity_name" varchar(255) NOT NULL,
+    "entity_type" varchar(32) NOT NULL,
+    "mentions" bigint NOT NULL,
+    "in_title" boolean NOT NULL DEFAULT false,
+    "aliases" varchar(512) NOT NULL DEFAULT '',
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_news_entities_news" FOREIGN KEY ("news_id","media_id") REFERENCES "news"("news_id","media_id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX "idx_news_entities_entity_id" ON "news_entities" ("entity_id");
+CREATE UNIQUE INDEX "idx_news_entity" ON "news_entities" ("news_id","media_id","entity_id");
+CREATE INDEX "idx_news_entities_deleted_at" ON "news_entities" ("deleted_at");
+
+CREATE TABLE "stories" (
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "id" bigserial,
+    "title" varchar(255) NOT NULL,
+    "first_published_at" timestamptz NOT NULL,
+    "last_published_at" timestamptz NOT NULL,
+    "article_count" bigint NOT NULL,
+    "media_count" bigint NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_stories_deleted_at" ON "stories" ("deleted_at");
+CREATE INDEX "idx_stories_media_count" ON "stories" ("media_count");
+CREATE INDEX "idx_stories_first_published_at" ON "stories" ("first_published_at");
+
+CREATE TABLE "story_articles" (
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "id" bigserial,
+    "story_id" bigint NOT NULL,
+    "news_id" varchar(36) NOT NULL,
+    "media_id" bigint NOT NULL,
+    "similarity" double precision NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_story_articles_news" FOREIGN KEY ("news_id","media_id") REFERENCES "news"("news_id","media_id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "fk_stories_article_list" FOREIGN KEY ("story_id") REFERENCES "stories"("id")
+);
+CREATE UNIQUE INDEX "idx_story_article_news" ON "story_articles" ("news_id","media_id");
+CREATE INDEX "idx_story_articles_story_id" ON "story_articles" ("story_id");
+CREATE INDEX "idx_story_articles_deleted_at" ON "story_articles" ("deleted_at");
+
+CREATE TABLE "news_summaries" (
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "id" bigserial,
+    "news_id" varchar(36) NOT NULL,
+    "media_id" bigint NOT NULL,
+    "summary" text NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_news_summaries_news" FOREIGN KEY ("news_id","media_id") REFERENCES "news"("news_id","media_id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX "idx_news_summaries_deleted_at" ON "news_summaries" ("deleted_at");
+CREATE UNIQUE INDEX "idx_news_summary" ON "news_summaries" ("news_id","media_id");
+
+CREATE TABLE "news_claims" (
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "id" bigserial,
+    "summary_id" bigint NOT NULL,
+    "news_id" varchar(36) NOT NULL,
+    "media_id" bigint NOT NULL,
+    "seq" bigint NOT NULL,
+    "claim" text NOT NULL,
+    "quote" text NOT NULL,
+    "quote_start" bigint,
+    "quote_end" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_news_claims_news" FOREIGN KEY ("news_id","media_id") REFERENCES "news"("news_id","media_id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "fk_news_summaries_claim_list" FOREIGN KEY ("summary_id") REFERENCES "news_summaries"("id")
+);
+CREATE INDEX "idx_news_claim" ON "news_claims" ("news_id","media_id");
+CREATE INDEX "idx_news_claims_summary_id" ON "news_claims" ("summary_id");
+CREATE INDEX "idx_news_claims_deleted_at" ON "news_claims" ("deleted_at");
+
+CREATE TABLE "news_embeddings" (
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "id" bigserial,
+    "news_id" varchar(36) NOT NULL,
+    "media_id" bigint NOT NULL,
+    "model" varchar(64) NOT NULL,
+    "dimensions" bigint NOT NULL,
+    "vector" bytea NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_news_embeddings_news" FOREIGN KEY ("news_id","media_id") REFERENCES "news"("news_id","media_id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE UNIQUE INDEX "idx_news_embedding" ON "news_embeddings" ("news_id","media_id","model");
+CREATE INDEX "idx_news_embeddings_deleted_at" ON "news_embeddings" ("deleted_at");
+
+CREATE TABLE "ai_usages" (
+    "id" bigserial,
+    "model" varchar(255) NOT NULL,
+    "prompt_version" varchar(64) NOT NULL,
+    "operation" varchar(64) NOT NULL,
+    "prompt_tokens" bigint NOT NULL,
+    "completion_tokens" bigint NOT NULL,
+    "total_tokens" bigint NOT NULL,
+    "cost" decimal(12,6) NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_ai_usages_model" ON "ai_usages" ("model");
+CREATE INDEX "idx_ai_usages_created_at" ON "ai_usages" ("created_at");
+
+CREATE TABLE "analysis_caches" (
+    "key" varchar(64),
+    "model" varchar(255) NOT NULL,
+    "prompt_version" varchar(64) NOT NULL,
+    "result" text NOT NULL,
+    "hit_count" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz NOT NULL,
+    "updated_at" timestamptz NOT NULL,
+    PRIMARY KEY ("key")
+);
+
+CREATE TABLE "metric_overrides" (
+    "id" bigserial,
+    "analysis_id" bigint NOT NULL,
+    "metric_key" varchar(255) NOT NULL,
+    "score" decimal(10,2) NOT NULL,
+    "reason" text NOT NULL,
+    "reviewer" varchar(255) NOT NULL,
+    "reviewed_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_metric_overrides_analysis" FOREIGN KEY ("analysis_id") REFERENCES "analyses"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX "idx_metric_overrides_reviewed_at" ON "metric_overrides" ("reviewed_at");
+CREATE UNIQUE INDEX "idx_override_analysis_metric" ON "metric_overrides" ("analysis_id","metric_key");
+
+CREATE TABLE "review_audits" (
+    "id" bigserial,
+    "analysis_id" bigint NOT NULL,
+    "metric_key" varchar(255) NOT NULL DEFAULT '',
+    "action" varchar(32) NOT NULL,
+    "reviewer" varchar(255) NOT NULL,
+    "old_score" decimal(10,2),
+    "new_score" decimal(10,2),
+    "old_reason" text,
+    "new_reason" text,
+    "comment" text,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_review_audits_created_at" ON "review_audits" ("created_at");
+CREATE INDEX "idx_review_audits_analysis_id" ON "review_audits" ("analysis_id");
+
+CREATE TABLE "review_items" (
+    "id" bigserial,
+    "analysis_id" bigint NOT NULL,
+    "reason" varchar(64) NOT NULL,
+    "status" varchar(32) NOT NULL,
+    "note" text,
+    "requested_by" varchar(255) NOT NULL DEFAULT '',
+    "resolved_by" varchar(255) NOT NULL DEFAULT '',
+    "resolved_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_review_items_created_at" ON "review_items" ("created_at");
+CREATE INDEX "idx_review_items_status" ON "review_items" ("status");
+CREATE UNIQUE INDEX "idx_review_analysis_reason" ON "review_items" ("analysis_id","reason");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_news_content_trgm";
+DROP INDEX IF EXISTS "idx_news_title_trgm";
@@ -0,0 +1,5 @@
+-- 新聞檢索以 ILIKE '%關鍵字%' 比對, 以 pg_trgm 的 GIN 索引避免全表掃描
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX "idx_news_title_trgm" ON "news" USING gin ("title" gin_trgm_ops);
+CREATE INDEX "idx_news_content_trgm" ON "news" USING gin ("content" gin_trgm_ops);
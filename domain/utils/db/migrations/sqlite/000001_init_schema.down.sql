@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS `review_items`;
+DROP TABLE IF EXISTS `review_audits`;
+DROP TABLE IF EXISTS `metric_overrides`;
+DROP TABLE IF EXISTS `analysis_caches`;
+DROP TABLE IF EXISTS `ai_usages`;
+DROP TABLE IF EXISTS `news_embeddings`;
+DROP TABLE IF EXISTS `news_claims`;
+DROP TABLE IF EXISTS `news_summaries`;
+DROP TABLE IF EXISTS `story_articles`;
+DROP TABLE IF EXISTS `stories`;
+DROP TABLE IF EXISTS `news_entities`;
+DROP TABLE IF EXISTS `analysis_runs`;
+DROP TABLE IF EXISTS `analysis_metrics`;
+DROP TABLE IF EXISTS `analyses`;
+DROP TABLE IF EXISTS `news`;
+DROP TABLE IF EXISTS `authors`;
+DROP TABLE IF EXISTS `media`;
@@ -8,11 +8,14 @@ type EventArticleListScraping struct {
 type EventNewsCheck struct {
 	MediaID    uint
 	NewsIDList []string
+	RunID      string // 爬取批次, 用於計算每次爬取的抽取失敗率
 }
 
 type EventArticleContentScraping struct {
 	MediaID uint
 	NewsID  string
+	RunID   string
+	RunSize int // 同一批次送出爬取的文章數
 }
 
 type EventNewsSave struct {
@@ -35,3 +38,12 @@ type EventAnalysisSave struct {
 	NewsID   string
 	Analysis string
 }
+
+// EventStoryClustering 觸發跨媒體事件分群, 範圍與門檻由 STORY_* 設定決定.
+type EventStoryClustering struct {
+}
+
+// EventNewsEmbedding 觸發計算新聞向量, 每次最多 BatchSize 篇.
+type EventNewsEmbedding struct {
+	BatchSize uint
+}
@@ -0,0 +1,199 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const defaultTimeout = 3 * time.Second
+
+// ErrUnknown 元件狀態尚無法判斷 (例如剛啟動尚未爬取), 不視為失敗.
+var ErrUnknown = errors.New("status unknown")
+
+// Status 元件或整體狀態.
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDown     Status = "down"
+	StatusUnknown  Status = "unknown"
+	StatusDegraded Status = "degraded" // 只有非必要元件異常
+)
+
+// CheckFunc 檢查元件, 回傳要顯示的細節, 失敗時回傳 error.
+type CheckFunc func(ctx context.Context) (details any, err error)
+
+// Check 元件檢查設定.
+type Check struct {
+	Name      string
+	Func      CheckFunc
+	Liveness  bool          // 只檢查行程內狀態 (不可依賴 DB 等外部服務), 列入 /healthz, 失敗時回傳 503
+	Readiness bool          // 失敗時 /readyz 回傳 503
+	Timeout   time.Duration // 0 代表使用預設 3s
+	CacheTTL  time.Duration // 快取檢查結果, 避免頻繁呼叫外部服務
+}
+
+// ComponentStatus 單一元件的檢查結果.
+type ComponentStatus struct {
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Details   any       `json:"details,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Report 健康檢查結果.
+type Report struct {
+	Status     Status                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+type registeredCheck struct {
+	Check
+
+	mu     sync.Mutex
+	cached *ComponentStatus
+}
+
+// Health 管理所有元件的健康檢查.
+type Health struct {
+	logger *zerolog.Logger
+
+	mu     sync.RWMutex
+	checks []*registeredCheck
+}
+
+// NewHealth 建立健康檢查.
+func NewHealth(logger *zerolog.Logger) *Health {
+	return &Health{logger: logger}
+}
+
+// Register 註冊元件檢查.
+func (h *Health) Register(check Check) {
+	if check.Timeout <= 0 {
+		check.Timeout = defaultTimeout
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks = append(h.checks, &registeredCheck{Check: check})
+}
+
+// Liveness 只執行 Liveness 元件的檢查, 任一失敗時整體為 down.
+// 外部依賴 (DB, AI) 異常時重啟行程無濟於事, 因此不列入.
+func (h *Health) Liveness(ctx context.Context) Report {
+	liveness := func(c *registeredCheck) bool { return c.Liveness }
+	return h.report(ctx, liveness, liveness)
+}
+
+// Readiness 執行所有檢查, 只有 Readiness 元件失敗時整體為 down.
+func (h *Health) Readiness(ctx context.Context) Report {
+	all := func(*registeredCheck) bool { return true }
+	return h.report(ctx, all, func(c *registeredCheck) bool { return c.Readiness })
+}
+
+// LivenessHandler /healthz.
+func (h *Health) LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.writeReport(w, h.Liveness(r.Context()))
+	}
+}
+
+// ReadinessHandler /readyz.
+func (h *Health) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.writeReport(w, h.Readiness(r.Context()))
+	}
+}
+
+func (h *Health) report(ctx context.Context, include, critical func(c *registeredCheck) bool) Report {
+	h.mu.RLock()
+	var checks []*registeredCheck
+	for _, c := range h.checks {
+		if include(c) {
+			checks = append(checks, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	results := make([]ComponentStatus, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:     StatusUp,
+		Components: make(map[string]ComponentStatus, len(checks)),
+	}
+	for i, c := range checks {
+		report.Components[c.Name] = results[i]
+		if results[i].Status != StatusDown {
+			continue
+		}
+
+		if critical(c) {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	return report
+}
+
+func (h *Health) writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status == StatusDown {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		h.logger.Error().Err(err).Msg("failed to write health report")
+	}
+}
+
+// run 執行檢查, 若快取尚未過期則直接回傳快取結果.
+func (c *registeredCheck) run(ctx context.Context) ComponentStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.cached != nil && now.Sub(c.cached.CheckedAt) < c.CacheTTL {
+		return *c.cached
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	details, err := c.Func(ctx)
+
+	status := ComponentStatus{
+		Status:    StatusUp,
+		Details:   details,
+		CheckedAt: now,
+	}
+	switch {
+	case errors.Is(err, ErrUnknown):
+		status.Status = StatusUnknown
+	case err != nil:
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+
+	c.cached = &status
+	return status
+}
@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHealth() *Health {
+	logger := zerolog.Nop()
+	return NewHealth(&logger)
+}
+
+func okCheck(context.Context) (any, error) { return "ok", nil }
+
+func failCheck(context.Context) (any, error) { return nil, errors.New("boom") }
+
+func TestHealth_Report(t *testing.T) {
+	tests := []struct {
+		name          string
+		checks        []Check
+		wantLiveness  Status
+		wantReadiness Status
+	}{
+		{
+			name: "全部正常",
+			checks: []Check{
+				{Name: "db", Func: okCheck, Readiness: true},
+				{Name: "cron", Func: okCheck, Liveness: true, Readiness: true},
+			},
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusUp,
+		},
+		{
+			name: "readiness 元件失敗",
+			checks: []Check{
+				{Name: "db", Func: failCheck, Readiness: true},
+				{Name: "cron", Func: okCheck, Liveness: true, Readiness: true},
+			},
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusDown,
+		},
+		{
+			name: "非必要元件失敗",
+			checks: []Check{
+				{Name: "ai", Func: failCheck},
+			},
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusDegraded,
+		},
+		{
+			name: "狀態未知不視為失敗",
+			checks: []Check{
+				{Name: "crawl", Func: func(context.Context) (any, error) { return nil, ErrUnknown }, Readiness: true},
+			},
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusUp,
+		},
+		{
+			name: "liveness 元件失敗",
+			checks: []Check{
+				{Name: "cron", Func: failCheck, Liveness: true, Readiness: true},
+			},
+			wantLiveness:  StatusDown,
+			wantReadiness: StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHealth()
+			for _, c := range tt.checks {
+				h.Register(c)
+			}
+
+			assert.Equal(t, tt.wantLiveness, h.Liveness(context.Background()).Status)
+			assert.Equal(t, tt.wantReadiness, h.Readiness(context.Background()).Status)
+		})
+	}
+}
+
+func TestHealth_CacheTTL(t *testing.T) {
+	h := newTestHealth()
+
+	var count int
+	h.Register(Check{
+		Name:     "ai",
+		CacheTTL: time.Minute,
+		Func: func(context.Context) (any, error) {
+			count++
+			return nil, nil
+		},
+	})
+
+	h.Readiness(context.Background())
+	h.Readiness(context.Background())
+	assert.Equal(t, 1, count)
+}
+
+func TestHealth_ReadinessHandler(t *testing.T) {
+	h := newTestHealth()
+	h.Register(Check{Name: "db", Func: failCheck, Readiness: true})
+	h.Register(Check{Name: "cron", Func: okCheck, Liveness: true})
+
+	rec := httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var report Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, StatusDown, report.Components["db"].Status)
+	assert.Equal(t, "boom", report.Components["db"].Error)
+	assert.Equal(t, StatusUp, report.Components["cron"].Status)
+
+	rec = httptest.NewRecorder()
+	h.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// /healthz 不執行外部依賴的檢查
+	report = Report{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.Equal(t, StatusUp, report.Status)
+	assert.NotContains(t, report.Components, "db")
+	assert.Contains(t, report.Components, "cron")
+}
@@ -0,0 +1,94 @@
+package mq
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"itmrchow/tw-media-analytics-service/domain/utils/health"
+)
+
+// SubscriptionState 單一 topic 的訂閱處理狀態.
+type SubscriptionState struct {
+	Running   int    `json:"running"`
+	Stopped   int    `json:"stopped"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// subscriptionRegistry 記錄 Process 的執行狀態, 供健康檢查使用.
+type subscriptionRegistry struct {
+	mu     sync.Mutex
+	states map[string]*SubscriptionState
+}
+
+var subscriptions = &subscriptionRegistry{states: make(map[string]*SubscriptionState)}
+
+func (r *subscriptionRegistry) get(topic string) *SubscriptionState {
+	state, ok := r.states[topic]
+	if !ok {
+		state = &SubscriptionState{}
+		r.states[topic] = state
+	}
+	return state
+}
+
+func (r *subscriptionRegistry) start(topic string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.get(topic).Running++
+}
+
+func (r *subscriptionRegistry) stop(topic string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state := r.get(topic)
+	state.Running--
+	state.Stopped++
+	if err != nil {
+		state.LastError = err.Error()
+	}
+}
+
+func (r *subscriptionRegistry) snapshot() map[string]SubscriptionState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := make(map[string]SubscriptionState, len(r.states))
+	for topic, state := range r.states {
+		snapshot[topic] = *state
+	}
+	return snapshot
+}
+
+// Subscriptions 回傳各 topic 的訂閱處理狀態.
+func Subscriptions() map[string]SubscriptionState {
+	return subscriptions.snapshot()
+}
+
+// HealthCheck Pub/Sub 訂閱健康檢查, 任一訂閱停止處理即視為失敗.
+func HealthCheck() health.Check {
+	return health.Check{
+		Name:      "pubsub",
+		Readiness: true,
+		Func: func(_ context.Context) (any, error) {
+			states := Subscriptions()
+
+			var stopped []string
+			for topic, state := range states {
+				if state.Stopped > 0 {
+					stopped = append(stopped, topic)
+				}
+			}
+			if len(stopped) > 0 {
+				sort.Strings(stopped)
+				return states, fmt.Errorf("subscription stopped: %s", strings.Join(stopped, ","))
+			}
+
+			return states, nil
+		},
+	}
+}
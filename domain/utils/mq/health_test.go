@@ -0,0 +1,61 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"itmrchow/tw-media-analytics-service/domain/utils/health"
+)
+
+func TestHealthCheck_StoppedSubscription(t *testing.T) {
+	subscriptions = &subscriptionRegistry{states: make(map[string]*SubscriptionState)}
+
+	logger := zerolog.Nop()
+	h := health.NewHealth(&logger)
+	h.Register(HealthCheck())
+
+	readiness := func() (int, health.Report) {
+		rec := httptest.NewRecorder()
+		h.ReadinessHandler()(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var report health.Report
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		return rec.Code, report
+	}
+
+	messages := make(chan *message.Message)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Process(&logger, "test_topic", messages, func(_ context.Context, _ []byte) error {
+			return errors.New("handler failed")
+		})
+	}()
+
+	// 訂閱處理中
+	require.Eventually(t, func() bool { return Subscriptions()["test_topic"].Running == 1 }, time.Second, 10*time.Millisecond)
+	code, report := readiness()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusUp, report.Components["pubsub"].Status)
+
+	// handler 失敗後訂閱停止, readiness 為 down
+	messages <- message.NewMessage(watermill.NewUUID(), []byte("payload"))
+	<-done
+
+	code, report = readiness()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, health.StatusDown, report.Components["pubsub"].Status)
+	assert.Equal(t, "subscription stopped: test_topic", report.Components["pubsub"].Error)
+	assert.Equal(t, SubscriptionState{Stopped: 1, LastError: "handler failed"}, Subscriptions()["test_topic"])
+}
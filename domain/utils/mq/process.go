@@ -0,0 +1,68 @@
+package mq
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Process 依序處理訂閱訊息, 並記錄每個 topic 的處理結果與耗時.
+// handler 回傳錯誤時停止處理, 該訂閱會在健康檢查中顯示為停止.
+func Process(
+	logger *zerolog.Logger,
+	topic string,
+	messages <-chan *message.Message,
+	handler func(ctx context.Context, msg []byte) error,
+) {
+	meter := otel.Meter("domain/utils/mq")
+	handled, err := meter.Int64Counter(
+		"mq.messages.handled",
+		metric.WithDescription("Number of messages handled by topic and result"),
+	)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create mq.messages.handled counter")
+	}
+	duration, err := meter.Float64Histogram(
+		"mq.handler.duration",
+		metric.WithDescription("Message handler duration"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create mq.handler.duration histogram")
+	}
+
+	topicAttr := attribute.String("topic", topic)
+
+	subscriptions.start(topic)
+	var processErr error
+	defer func() { subscriptions.stop(topic, processErr) }()
+
+	for msg := range messages {
+		start := time.Now()
+
+		// call handler
+		handleErr := handler(msg.Context(), msg.Payload)
+
+		result := "success"
+		if handleErr != nil {
+			result = "failure"
+		}
+		duration.Record(msg.Context(), time.Since(start).Seconds(), metric.WithAttributes(topicAttr))
+		handled.Add(msg.Context(), 1, metric.WithAttributes(topicAttr, attribute.String("result", result)))
+
+		if handleErr != nil {
+			logger.Error().Ctx(msg.Context()).Err(handleErr).
+				Str("topic", topic).
+				Bytes("payload", msg.Payload).
+				Msg("failed to process message")
+			processErr = handleErr
+			return
+		}
+		msg.Ack()
+	}
+}
@@ -0,0 +1,60 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestProcess(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	messages := make(chan *message.Message, 3)
+	for _, payload := range []string{"ok", "fail", "never"} {
+		messages <- message.NewMessage(watermill.NewUUID(), []byte(payload))
+	}
+	close(messages)
+
+	var handled []string
+	logger := zerolog.Nop()
+	Process(&logger, "test_topic", messages, func(_ context.Context, msg []byte) error {
+		handled = append(handled, string(msg))
+		if string(msg) == "fail" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	// 發生錯誤後停止處理
+	assert.Equal(t, []string{"ok", "fail"}, handled)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	results := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "mq.messages.handled" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				topic, _ := dp.Attributes.Value(attribute.Key("topic"))
+				assert.Equal(t, "test_topic", topic.AsString())
+				result, _ := dp.Attributes.Value(attribute.Key("result"))
+				results[result.AsString()] = dp.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"success": 1, "failure": 1}, results)
+}
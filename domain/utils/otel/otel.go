@@ -9,7 +9,9 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -126,23 +128,33 @@ func newTraceProvider(ctx context.Context, res *resource.Resource) (*trace.Trace
 
 func newMeterProvider(ctx context.Context, res *resource.Resource) (*metric.MeterProvider, error) {
 	// Create OTLP gRPC metric exporter
-	// metricExporter, err := otlpmetricgrpc.New(ctx,
-	// 	otlpmetricgrpc.WithEndpoint("localhost:4317"), // Jaeger OTLP gRPC endpoint
-	// 	otlpmetricgrpc.WithInsecure(),                 // 開發環境使用非加密連線
-	// )
-	// if err != nil {
-	// 	return nil, err
-	// }
-
-	// meterProvider := metric.NewMeterProvider(
-	// 	metric.WithReader(
-	// 		metric.NewPeriodicReader(metricExporter,
-	// 			metric.WithInterval(5*time.Second)),
-	// 	),
-	// 	metric.WithResource(res),
-	// )
-
-	meterProvider := metric.NewMeterProvider()
+	metricExporter, err := otlpmetricgrpc.New(ctx,
+		otlpmetricgrpc.WithEndpoint(viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT")),
+		otlpmetricgrpc.WithInsecure(), // 開發環境使用非加密連線
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prometheus exporter, 註冊至 prometheus.DefaultRegisterer, 由 /metrics 提供
+	promExporter, err := prometheus.New()
+	if err != nil {
+		return nil, err
+	}
+
+	interval := viper.GetDuration("OTEL_METRIC_INTERVAL")
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	meterProvider := metric.NewMeterProvider(
+		metric.WithReader(
+			metric.NewPeriodicReader(metricExporter,
+				metric.WithInterval(interval)),
+		),
+		metric.WithReader(promExporter),
+		metric.WithResource(res),
+	)
 
 	return meterProvider, nil
 }
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultAuthHeader 驗證 proxy 設定使用者的預設 header.
+const DefaultAuthHeader = "X-Authenticated-User"
+
+type authenticatedUserKey struct{}
+
+// Authenticator 從前置驗證 proxy 設定的 header 取得使用者.
+// 服務本身不驗證身分, 需部署在會覆寫此 header 的驗證 proxy 之後.
+type Authenticator struct {
+	header string
+}
+
+// NewAuthenticator 以 configKey 設定的 header 建立, 未設定時為 DefaultAuthHeader.
+func NewAuthenticator(configKey string) *Authenticator {
+	header := viper.GetString(configKey)
+	if header == "" {
+		header = DefaultAuthHeader
+	}
+
+	return &Authenticator{header: header}
+}
+
+// Authenticate 取得使用者並放入 context, 缺少 header 時回傳 401.
+func (a *Authenticator) Authenticate(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := strings.TrimSpace(r.Header.Get(a.header))
+		if user == "" {
+			http.Error(w, "missing "+a.header+" header", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), authenticatedUserKey{}, user)))
+	})
+}
+
+// AuthenticatedUser 取得 Authenticate 設定的使用者, 未驗證時為空.
+func AuthenticatedUser(ctx context.Context) string {
+	user, _ := ctx.Value(authenticatedUserKey{}).(string)
+	return user
+}
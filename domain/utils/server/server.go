@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultHTTPPort = "8080"
+
+// NewServeMux 建立 HTTP 路由, 預設提供 /metrics.
+func NewServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.Handler())
+
+	return mux
+}
+
+// NewHTTPServer 建立監聽 HTTP_PORT 的 HTTP server.
+func NewHTTPServer(mux *http.ServeMux) *http.Server {
+	port := viper.GetString("HTTP_PORT")
+	if port == "" {
+		port = defaultHTTPPort
+	}
+
+	return &http.Server{
+		Addr:              net.JoinHostPort("", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// InitHTTPServer 啟動 HTTP server.
+func InitHTTPServer(ctx context.Context, logger *zerolog.Logger, tracer trace.Tracer, srv *http.Server) {
+	// Tracer
+	ctx, span := tracer.Start(ctx, "domain/utils/server/InitHTTPServer: Init HTTP Server")
+	logger.Info().Ctx(ctx).Str("addr", srv.Addr).Msg("InitHTTPServer: start")
+	defer func() {
+		span.End()
+		logger.Info().Ctx(ctx).Msg("InitHTTPServer: end")
+	}()
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error().Err(err).Msg("http server stopped unexpectedly")
+		}
+	}()
+}
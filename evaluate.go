@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+
+	"itmrchow/tw-media-analytics-service/domain/ai"
+	"itmrchow/tw-media-analytics-service/domain/ai/eval"
+)
+
+// runEval 以人工標註的資料評估 prompt / 模型, 並可與先前的報告比較.
+// 評估不使用分析快取與預算控管, 也不寫入資料庫.
+//
+// Usage:
+//
+//	tw-media-analytics-service eval -dataset gold.jsonl [-model gemini-2.0-flash-001] [-prompt promt.md] \
+//		[-out report.json] [-baseline base.json] [-compare compare.md]
+func runEval(ctx context.Context, logger *zerolog.Logger, args []string) error {
+	flagSet := flag.NewFlagSet("eval", flag.ContinueOnError)
+	datasetPath := flagSet.String("dataset", "", "labelled dataset (jsonl)")
+	modelName := flagSet.String("model", viper.GetString("AI_MODEL"), "model name")
+	promptPath := flagSet.String("prompt", "promt.md", "prompt file")
+	outPath := flagSet.String("out", "eval-report.json", "report output (json)")
+	baselinePath := flagSet.String("baseline", "", "baseline report (json) to compare with")
+	comparePath := flagSet.String("compare", "", "comparison output (markdown), empty means stdout")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+	if *datasetPath == "" {
+		return errors.New("eval: -dataset is required")
+	}
+	if *modelName == "" {
+		return errors.New("eval: -model is required")
+	}
+
+	samples, err := eval.LoadDataset(*datasetPath)
+	if err != nil {
+		return err
+	}
+
+	prompt, err := ai.ReadPromptFile(*promptPath)
+	if err != nil {
+		return err
+	}
+
+	pricing, err := ai.NewPricing()
+	if err != nil {
+		return err
+	}
+	usage := eval.NewUsageCollector(pricing)
+
+	// model
+	gemini, err := ai.NewGemini(ctx, logger, *modelName, usage, ai.WithPromptFile(*promptPath))
+	if err != nil {
+		return err
+	}
+	defer gemini.CloseClient()
+	model := ai.NewChunkedModel(logger, gemini, ai.NewChunkConfig())
+
+	// run
+	report, err := eval.NewEvaluator(logger, model, usage).Run(ctx, samples)
+	if err != nil {
+		return err
+	}
+	report.Model = *modelName
+	report.PromptVersion = ai.PromptVersion(prompt)
+	report.Name = report.Model + "@" + report.PromptVersion
+	report.Dataset = *datasetPath
+
+	if err := eval.WriteReport(*outPath, report); err != nil {
+		return err
+	}
+	logger.Info().
+		Str("report", *outPath).
+		Int("samples", report.Samples).
+		Int("failures", report.Failures).
+		Float64("cost", report.Usage.Cost).
+		Msg("eval: report written")
+
+	if *baselinePath == "" {
+		return nil
+	}
+
+	// compare
+	baseline, err := eval.ReadReport(*baselinePath)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if *comparePath != "" {
+		f, err := os.Create(*comparePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	return eval.Compare(baseline, report).WriteMarkdown(out)
+}
@@ -0,0 +1,45 @@
+// Package testdb 提供 repository 測試用的資料庫, 僅供測試使用.
+package testdb
+
+import (
+	"context"
+	"os"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"itmrchow/tw-media-analytics-service/domain/utils/db"
+)
+
+const sqlitePath = "./database.db"
+
+// New 初始化 repository 測試用的 db 並套用 migration, 預設為目前目錄的 database.db.
+// TEST_DB_DRIVER=postgres 時連線 TEST_POSTGRES_DSN, 各 package 共用同一個資料庫, 需以 go test -p 1 執行.
+func New(ctx context.Context, logger *zerolog.Logger, tracer trace.Tracer) *gorm.DB {
+	// Trace
+	ctx, span := tracer.Start(ctx, "internal/testdb/New: New Test DB")
+	defer span.End()
+
+	var dialector gorm.Dialector = sqlite.Open(sqlitePath)
+	if os.Getenv("TEST_DB_DRIVER") == db.DriverPostgres {
+		dialector = postgres.Open(os.Getenv("TEST_POSTGRES_DSN"))
+	}
+
+	testDB, err := db.NewDB(ctx, dialector, &gorm.Config{})
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Msg("failed to init test db")
+	}
+
+	migrator, err := db.NewMigrator(logger, testDB)
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Msg("failed to load migrations")
+	}
+	if _, err = migrator.Up(ctx); err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Msg("failed to migrate test db")
+	}
+
+	return testDB
+}
@@ -3,26 +3,44 @@ package main
 import (
 	"context"
 	"errors"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"gorm.io/gorm"
 
 	"itmrchow/tw-media-analytics-service/domain/ai"
+	aiDelivery "itmrchow/tw-media-analytics-service/domain/ai/delivery"
+	aiRepository "itmrchow/tw-media-analytics-service/domain/ai/repository"
+	"itmrchow/tw-media-analytics-service/domain/category"
 	"itmrchow/tw-media-analytics-service/domain/cronjob"
+	"itmrchow/tw-media-analytics-service/domain/ner"
+	newsDelivery "itmrchow/tw-media-analytics-service/domain/news/delivery"
 	"itmrchow/tw-media-analytics-service/domain/news/repository"
+	newsService "itmrchow/tw-media-analytics-service/domain/news/service"
+	reviewDelivery "itmrchow/tw-media-analytics-service/domain/review/delivery"
+	reviewRepository "itmrchow/tw-media-analytics-service/domain/review/repository"
+	reviewService "itmrchow/tw-media-analytics-service/domain/review/service"
+	spiderRepository "itmrchow/tw-media-analytics-service/domain/spider/repository"
+	spiderUsecase "itmrchow/tw-media-analytics-service/domain/spider/usecase"
 	mAi "itmrchow/tw-media-analytics-service/domain/utils/ai"
+	"itmrchow/tw-media-analytics-service/domain/utils/alert"
+	"itmrchow/tw-media-analytics-service/domain/utils/blob"
 	"itmrchow/tw-media-analytics-service/domain/utils/config"
+	"itmrchow/tw-media-analytics-service/domain/utils/crawler"
 	"itmrchow/tw-media-analytics-service/domain/utils/db"
+	"itmrchow/tw-media-analytics-service/domain/utils/health"
 	"itmrchow/tw-media-analytics-service/domain/utils/logger"
 	"itmrchow/tw-media-analytics-service/domain/utils/mq"
 	mOtel "itmrchow/tw-media-analytics-service/domain/utils/otel"
+	"itmrchow/tw-media-analytics-service/domain/utils/server"
 )
 
 func main() {
@@ -39,6 +57,28 @@ func main() {
 	// logger
 	logger := logger.InitLogger()
 
+	// 子命令
+	if len(os.Args) > 1 {
+		var run func(context.Context, *zerolog.Logger, []string) error
+		switch os.Args[1] {
+		case "reparse":
+			run = runReparse
+		case "eval":
+			run = runEval
+		case "migrate":
+			run = runMigrate
+		case "backfill-category":
+			run = runBackfillCategory
+		}
+		if run != nil {
+			if err := run(ctx, logger, os.Args[2:]); err != nil {
+				logger.Fatal().Err(err).Msgf("%s failed", os.Args[1])
+			}
+			cancel()
+			return
+		}
+	}
+
 	// fx
 	app := fx.New(
 		// Supply , 如果接Interface要用annotate註記
@@ -78,22 +118,139 @@ func main() {
 		),
 		// db
 		fx.Provide(
-			db.NewMysqlDB,
+			db.NewDatabase,
 		),
 		// repository
 		fx.Provide(
 			repository.NewNewsRepositoryImpl,
 			repository.NewAuthorRepositoryImpl,
-			repository.NewAnalysisRepositoryImpl,
+			fx.Annotate(
+				repository.NewUnitOfWorkImpl,
+				fx.As(new(repository.UnitOfWork)),
+			),
+			fx.Annotate(
+				repository.NewAnalysisRepositoryImpl,
+				fx.As(new(repository.AnalysisRepository)),
+			),
+			fx.Annotate(
+				repository.NewNewsEntityRepositoryImpl,
+				fx.As(new(repository.NewsEntityRepository)),
+			),
+			fx.Annotate(
+				repository.NewNewsSummaryRepositoryImpl,
+				fx.As(new(repository.NewsSummaryRepository)),
+			),
+			fx.Annotate(
+				repository.NewStoryRepositoryImpl,
+				fx.As(new(repository.StoryRepository)),
+			),
+			fx.Annotate(
+				repository.NewNewsEmbeddingRepositoryImpl,
+				fx.As(new(repository.NewsEmbeddingRepository)),
+			),
+			fx.Annotate(
+				repository.NewNewsSearchRepositoryImpl,
+				fx.As(new(repository.NewsSearchRepository)),
+			),
 		),
 		// ai
 		fx.Provide(
-			mAi.NewGemini,
+			fx.Annotate(
+				aiRepository.NewUsageRepositoryImpl,
+				fx.As(new(aiRepository.UsageRepository)),
+			),
+			fx.Annotate(
+				aiRepository.NewAnalysisCacheRepositoryImpl,
+				fx.As(new(aiRepository.AnalysisCacheRepository)),
+			),
+			ai.NewUsageService,
+			mAi.NewAiModel,
+			aiDelivery.NewUsageHandler,
+		),
+		// review
+		fx.Provide(
+			fx.Annotate(
+				reviewRepository.NewReviewRepositoryImpl,
+				fx.As(new(reviewRepository.ReviewRepository)),
+			),
+			fx.Annotate(
+				reviewService.NewReviewServiceImpl,
+				fx.As(new(reviewService.ReviewService)),
+			),
+			reviewDelivery.NewReviewHandler,
+		),
+		// category
+		fx.Provide(
+			mAi.NewCategoryLLM,
+			category.NewClassifier,
+			newsDelivery.NewCategoryHandler,
+		),
+		// named entity
+		fx.Provide(
+			ner.NewExtractor,
+			newsDelivery.NewEntityHandler,
+		),
+		// framing
+		fx.Provide(
+			mAi.NewFramingModel,
+			newsDelivery.NewFramingHandler,
+		),
+		// summary
+		fx.Provide(
+			mAi.NewSummaryModel,
+			newsDelivery.NewSummaryHandler,
+		),
+		// story
+		fx.Provide(
+			fx.Annotate(
+				newsService.NewStoryServiceImpl,
+				fx.As(new(newsService.StoryService)),
+			),
+			newsDelivery.NewStoryHandler,
+		),
+		// embedding
+		fx.Provide(
+			mAi.NewEmbeddingModel,
+			fx.Annotate(
+				newsService.NewEmbeddingServiceImpl,
+				fx.As(new(newsService.EmbeddingService)),
+			),
+			newsDelivery.NewSimilarHandler,
+		),
+		// full-text search
+		fx.Provide(
+			newsDelivery.NewSearchHandler,
 		),
 		// cronjob
 		fx.Provide(
 			cronjob.NewCronJob,
 		),
+		// crawler
+		fx.Provide(
+			crawler.NewFactory,
+		),
+		// archive
+		fx.Provide(
+			blob.NewStore,
+			fx.Annotate(
+				spiderRepository.NewArchiveRepositoryImpl,
+				fx.As(new(spiderRepository.ArchiveRepository)),
+			),
+		),
+		// http server
+		fx.Provide(
+			server.NewServeMux,
+			server.NewHTTPServer,
+			health.NewHealth,
+		),
+		// spider quality
+		fx.Provide(
+			fx.Annotate(
+				alert.NewWebhookNotifier,
+				fx.As(new(alert.Notifier)),
+			),
+			spiderUsecase.NewQualityMonitor,
+		),
 
 		// // news module
 		// fx.Provide(
@@ -101,6 +258,9 @@ func main() {
 		// 		newsService.NewNewsServiceImpl,
 		// 		fx.As(new(newsService.NewsService)),
 		// 	),
+		// 	newsService.NewNewsSaver,
+		// 	clickbait.NewAnalyzer,
+		// 	func(review reviewService.ReviewService) newsService.ReviewQueue { return review },
 		// 	fx.Annotate(
 		// 		newsDelivery.NewNewsEventHandler,
 		// 		fx.As(new(newsDelivery.NewsEventHandler)),
@@ -160,6 +320,81 @@ func main() {
 			// Ping DB
 			db.PingDB,
 
+			// Health check (/healthz, /readyz)
+			func(
+				mux *http.ServeMux,
+				h *health.Health,
+				ormDB *gorm.DB,
+				aiModel ai.AiModel,
+				cronJob *cronjob.CronJob,
+				newsRepo *repository.NewsRepositoryImpl,
+			) {
+				h.Register(db.HealthCheck(ormDB))
+				h.Register(mq.HealthCheck())
+				h.Register(mAi.HealthCheck(aiModel))
+				h.Register(cronJob.HealthCheck())
+				h.Register(newsService.CrawlHealthCheck(newsRepo, viper.GetDuration("HEALTH_CRAWL_MAX_AGE")))
+
+				mux.Handle("GET /healthz", h.LivenessHandler())
+				mux.Handle("GET /readyz", h.ReadinessHandler())
+			},
+
+			// AI usage API
+			func(mux *http.ServeMux, h *aiDelivery.UsageHandler) {
+				mux.HandleFunc("GET /api/ai/usage", h.GetUsage)
+			},
+
+			// Review API
+			func(mux *http.ServeMux, h *reviewDelivery.ReviewHandler) {
+				h.Register(mux)
+			},
+
+			// Category API
+			func(mux *http.ServeMux, h *newsDelivery.CategoryHandler) {
+				h.Register(mux)
+			},
+
+			// Named entity API
+			func(mux *http.ServeMux, h *newsDelivery.EntityHandler) {
+				h.Register(mux)
+			},
+
+			// Framing API
+			func(mux *http.ServeMux, h *newsDelivery.FramingHandler) {
+				h.Register(mux)
+			},
+
+			// News summary API
+			func(mux *http.ServeMux, h *newsDelivery.SummaryHandler) {
+				h.Register(mux)
+			},
+
+			// Story API
+			func(mux *http.ServeMux, h *newsDelivery.StoryHandler) {
+				h.Register(mux)
+			},
+
+			// Similar news API
+			func(mux *http.ServeMux, h *newsDelivery.SimilarHandler) {
+				h.Register(mux)
+			},
+
+			// News search API
+			func(mux *http.ServeMux, h *newsDelivery.SearchHandler) {
+				h.Register(mux)
+			},
+
+			// Init HTTP server (/metrics, /healthz, /readyz, /api)
+			server.InitHTTPServer,
+			func(lf fx.Lifecycle, srv *http.Server) {
+				lf.Append(fx.Hook{
+					OnStop: func(ctx context.Context) error {
+						logger.Info().Ctx(ctx).Msg("shutting down http server")
+						return srv.Shutdown(ctx)
+					},
+				})
+			},
+
 			// subscribe init
 			// - news subscribe
 			// newsDelivery.InitNewsSubscribe,
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+
+	"itmrchow/tw-media-analytics-service/domain/utils/db"
+)
+
+// runMigrate 套用或回復 domain/utils/db/migrations 下的 SQL migration, 以 DB 鎖避免多個 replica 同時執行.
+// 服務啟動時只檢查 schema 是否為最新版本, 部署新版本前需先執行 migrate up.
+//
+// Usage:
+//
+//	tw-media-analytics-service migrate up
+//	tw-media-analytics-service migrate down [-steps 1]
+//	tw-media-analytics-service migrate status
+//	tw-media-analytics-service migrate force -version 1
+func runMigrate(ctx context.Context, logger *zerolog.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New("migrate: command is required (up, down, status, force)")
+	}
+
+	flagSet := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	steps := flagSet.Int("steps", 1, "number of migrations to revert (down)")
+	version := flagSet.Uint("version", 0, "mark migrations up to this version as applied (force)")
+	if err := flagSet.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	// db
+	ormDB, err := db.Open(ctx, viper.GetString("DB_DRIVER"))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if sqlDB, err := ormDB.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}()
+	migrator, err := db.NewMigrator(logger, ormDB)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up(ctx)
+		logger.Info().Int("applied", count).Msg("migrate: up")
+		return err
+	case "down":
+		if *steps <= 0 {
+			return errors.New("migrate: -steps must be positive")
+		}
+		count, err := migrator.Down(ctx, *steps)
+		logger.Info().Int("reverted", count).Msg("migrate: down")
+		return err
+	case "force":
+		if *version == 0 {
+			return errors.New("migrate: -version is required")
+		}
+		return migrator.Force(ctx, *version)
+	case "status":
+		statusList, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statusList {
+			state, appliedAt := "pending", ""
+			if status.Applied {
+				state, appliedAt = "applied", status.AppliedAt.Format(time.RFC3339)
+			}
+			if status.Dirty {
+				state = "dirty"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("migrate: unknown command %q", args[0])
+	}
+}
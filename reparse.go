@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+
+	"itmrchow/tw-media-analytics-service/domain/ai"
+	aiRepository "itmrchow/tw-media-analytics-service/domain/ai/repository"
+	"itmrchow/tw-media-analytics-service/domain/category"
+	"itmrchow/tw-media-analytics-service/domain/ner"
+	"itmrchow/tw-media-analytics-service/domain/news/repository"
+	newsService "itmrchow/tw-media-analytics-service/domain/news/service"
+	spiderRepository "itmrchow/tw-media-analytics-service/domain/spider/repository"
+	spiderUsecase "itmrchow/tw-media-analytics-service/domain/spider/usecase"
+	"itmrchow/tw-media-analytics-service/domain/utils"
+	mAi "itmrchow/tw-media-analytics-service/domain/utils/ai"
+	"itmrchow/tw-media-analytics-service/domain/utils/alert"
+	"itmrchow/tw-media-analytics-service/domain/utils/blob"
+	"itmrchow/tw-media-analytics-service/domain/utils/crawler"
+	"itmrchow/tw-media-analytics-service/domain/utils/db"
+)
+
+// runReparse 以封存的原始 HTML 重新解析新聞並更新資料庫, 不會對新聞網站發出請求.
+// 解析結果需通過品質檢查, 並與爬蟲相同以 NewsSaver 儲存 (統一分類與具名實體).
+//
+// Usage:
+//
+//	tw-media-analytics-service reparse -media 2 [-news 1600001,1600002]
+func runReparse(ctx context.Context, logger *zerolog.Logger, args []string) error {
+	flagSet := flag.NewFlagSet("reparse", flag.ContinueOnError)
+	mediaID := flagSet.Uint("media", 0, "media id (1: 中天, 2: 三立)")
+	newsIDs := flagSet.String("news", "", "comma separated news ids, empty means all archived news of the media")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+	if *mediaID == 0 {
+		return errors.New("reparse: -media is required")
+	}
+
+	var newsIDList []string
+	if *newsIDs != "" {
+		newsIDList = strings.Split(*newsIDs, ",")
+	}
+
+	tracer := otel.Tracer("tw-media-analytics-service")
+
+	// db
+	ormDB := db.NewDatabase(ctx, logger, tracer)
+	defer func() {
+		if sqlDB, err := ormDB.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}()
+
+	// news saver, 與爬蟲相同的分類與具名實體擷取
+	usage := ai.NewUsageService(logger, aiRepository.NewUsageRepositoryImpl(logger, ormDB))
+	saver := newsService.NewNewsSaver(
+		logger,
+		repository.NewUnitOfWorkImpl(logger, ormDB),
+		repository.NewNewsEntityRepositoryImpl(logger, ormDB),
+		category.NewClassifier(logger, mAi.NewCategoryLLM(ctx, logger, usage)),
+		ner.NewExtractor(logger),
+	)
+
+	// spider
+	archiveRepo := spiderRepository.NewArchiveRepositoryImpl(logger, blob.NewStore(ctx, logger, tracer))
+	factory := crawler.NewFactory(logger)
+	quality := spiderUsecase.NewQualityMonitor(logger, alert.NewWebhookNotifier(logger))
+	reparser := spiderUsecase.NewReparser(logger, tracer, archiveRepo, quality, []spiderUsecase.Spider{
+		spiderUsecase.NewCtiNewsSpider(logger, tracer, factory, archiveRepo, quality),
+		spiderUsecase.NewSetnSpider(logger, tracer, factory, archiveRepo, quality),
+	})
+
+	newsList, errMap, err := reparser.Reparse(ctx, *mediaID, newsIDList)
+	if err != nil {
+		return err
+	}
+
+	// save
+	var saveErr error
+	for _, news := range newsList {
+		err = saver.SaveNews(ctx, utils.EventNewsSave{
+			MediaID:     *mediaID,
+			NewsID:      news.NewsID,
+			Title:       news.Headline,
+			Content:     news.NewsContext,
+			URL:         news.URL,
+			AuthorName:  news.Author.Name,
+			PublishedAt: news.DatePublished,
+			Category:    news.Category,
+		})
+		if err != nil {
+			saveErr = errors.Join(saveErr, err)
+		}
+	}
+
+	logger.Info().Ctx(ctx).
+		Uint("media_id", *mediaID).
+		Int("reparsed", len(newsList)).
+		Int("failed", len(errMap)).
+		Msg("reparse completed")
+
+	return saveErr
+}